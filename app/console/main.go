@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"os"
 
 	"github.com/eolinker/goku-api-gateway/console/module/account"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
@@ -19,6 +20,10 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrate(os.Args[2:])
+		return
+	}
 	flag.StringVar(&confFilePath, "c", "./config/goku.conf", "Please provide a valid configuration file path")
 	flag.StringVar(&userName, "u", "", "Please provide user name")
 	flag.StringVar(&userPassword, "p", "", "Please provide user password")
@@ -35,6 +40,11 @@ func main() {
 	}
 	// 初始化db
 	console.InitDatabase()
+	// 初始化或升级表结构，数据库版本高于当前程序时拒绝启动
+	if _, err := console.Migrate(false); err != nil {
+		log.Fatalf("[ERROR] Fail to migrate database: %s", err)
+		return
+	}
 	console.InitLog()
 
 	//console.InitClusters()
@@ -43,11 +53,8 @@ func main() {
 	// 检测是否安装
 	s, err := account.CheckSuperAdminCount()
 	if err != nil {
-		err = console.InitTable()
-		if err != nil {
-			log.Panic(err)
-			return
-		}
+		log.Panic(err)
+		return
 	}
 	if s == 0 {
 		if userName == "" {
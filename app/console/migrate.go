@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/eolinker/goku-api-gateway/common/conf"
+	"github.com/eolinker/goku-api-gateway/common/database"
+	"github.com/eolinker/goku-api-gateway/console"
+)
+
+//migrate 数据库迁移命令：console migrate [-c 配置文件] [-status] [-dry-run]
+func migrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	flags.StringVar(&confFilePath, "c", "./config/goku.conf", "Please provide a valid configuration file path")
+	status := flags.Bool("status", false, "Show the schema version and pending migrations")
+	dryRun := flags.Bool("dry-run", false, "Print pending migrations without applying them")
+	flags.Parse(args)
+
+	if err := conf.ReadConfigure(confFilePath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	console.InitDatabase()
+
+	s, err := console.GetMigrationStatus()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("current version: %d\nlatest version: %d\n", s.Current, s.Latest)
+	if s.Baseline {
+		fmt.Println("schema version is not recorded, treat as version 1")
+	}
+	if s.Current > s.Latest {
+		fmt.Fprintln(os.Stderr, database.ErrorSchemaTooNew)
+		os.Exit(1)
+	}
+	if *status {
+		for _, m := range s.Pending {
+			fmt.Printf("pending: %d_%s\n", m.Version, m.Name)
+		}
+		return
+	}
+	if *dryRun {
+		pending, _ := console.Migrate(true)
+		for _, m := range pending {
+			content, err := ioutil.ReadFile(m.Path)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			fmt.Printf("-- pending: %d_%s\n%s\n", m.Version, m.Name, content)
+		}
+		return
+	}
+	applied, err := console.Migrate(false)
+	for _, m := range applied {
+		fmt.Printf("applied: %d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(applied) == 0 {
+		fmt.Println("database schema is up to date")
+	}
+}
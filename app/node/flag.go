@@ -1,17 +1,60 @@
 package main
 
-import "flag"
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+//tokenEnv 节点凭证的环境变量，凭证不通过命令行传递，避免出现在进程列表中
+const tokenEnv = "GOKU_NODE_TOKEN"
 
 //ParseFlag 获取命令行参数
-func ParseFlag() (port int, admin string, staticConfigFile string, isDebug bool) {
+func ParseFlag() (port int, admin string, staticConfigFile string, isDebug bool, auth *nodeAuth) {
 	adminP := flag.String("admin", "", "Please provide a valid host!")
 	portP := flag.Int("port", 0, "Please provide a valid listen port!")
 	staticConfigFileP := flag.String("config", "", "Please provide a config file")
 
 	isDebugP := flag.Bool("debug", false, "")
 
+	auth = new(nodeAuth)
+	flag.StringVar(&auth.name, "name", "", "Node name used when registering to the console")
+	flag.StringVar(&auth.tokenFile, "token-file", "", "File containing the cluster or node token provided by the console, defaults to $"+tokenEnv)
+	flag.StringVar(&auth.ca, "tls-ca", "", "CA file to verify the console")
+	flag.StringVar(&auth.cert, "tls-cert", "", "Client certificate file")
+	flag.StringVar(&auth.key, "tls-key", "", "Client key file")
+
 	flag.Parse()
 
-	return *portP, *adminP, *staticConfigFileP, *isDebugP
+	return *portP, *adminP, *staticConfigFileP, *isDebugP, auth
+
+}
+
+//nodeAuth 节点接入控制台的凭证
+type nodeAuth struct {
+	name      string
+	token     string
+	tokenFile string
+	ca        string
+	cert      string
+	key       string
+}
 
+//loadToken 从凭证文件读取凭证，未指定文件时读取环境变量
+func (a *nodeAuth) loadToken() error {
+	if a.tokenFile == "" {
+		a.token = strings.TrimSpace(os.Getenv(tokenEnv))
+		return nil
+	}
+	data, err := ioutil.ReadFile(a.tokenFile)
+	if err != nil {
+		return err
+	}
+	a.token = strings.TrimSpace(string(data))
+	if a.token == "" {
+		return errors.New("empty token file:" + a.tokenFile)
+	}
+	return nil
 }
@@ -14,7 +14,7 @@ import (
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
-	port, admin, staticConfigFile, isDebug := ParseFlag()
+	port, admin, staticConfigFile, isDebug, auth := ParseFlag()
 
 	if isDebug {
 		log.StartDebug()
@@ -29,6 +29,18 @@ func main() {
 	if  admin != "" {
 		// 从控制台启动，
 		console := console2.NewConsole(port, admin)
+		console.SetName(auth.name)
+		if err := auth.loadToken(); err != nil {
+			log.Panic("load token error:", err)
+		}
+		if err := console.SetToken(auth.token); err != nil {
+			log.Panic(err)
+		}
+		if auth.ca != "" || auth.cert != "" {
+			if err := console.SetTLS(auth.ca, auth.cert, auth.key); err != nil {
+				log.Panic("load tls config error:", err)
+			}
+		}
 		ser := server.NewServer(port)
 		ser.SetConsole(console)
 		log.Fatal(ser.Server())
@@ -17,7 +17,13 @@ then
 fi
 
 
-buildApp node $VERSION
+# 存在 gen-builtin-plugins.sh 生成的插件列表时，将插件编译进节点
+TAGS=""
+if [ -f "${BasePath}/app/node/builtin_plugins.go" ];then
+    TAGS="builtin_plugins"
+fi
+
+buildApp node $VERSION $TAGS
 
 
 OUTPATH="${BasePath}/out/node-${VERSION}"
@@ -21,14 +21,19 @@ function genVersion(){
     echo "$1"
 
 }
-# 构建app
+# 构建app，第三个参数为可选的build tags
 function buildApp(){
     APP=$1
     VERSION=$2
+    TAGS=$3
     OUTPATH="${BasePath}/out/${APP}-${VERSION}"
     rm -rf ${OUTPATH}
     mkdir -p ${OUTPATH}
     buildCMD="go build  -o ${OUTPATH}/$APP ${BasePath}/app/$APP"
+    if [[ "$TAGS" != "" ]]
+    then
+        buildCMD="go build -tags ${TAGS} -o ${OUTPATH}/$APP ${BasePath}/app/$APP"
+    fi
     echo "build $APP:${buildCMD}"
     ${buildCMD}
 
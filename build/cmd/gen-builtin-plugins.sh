@@ -0,0 +1,35 @@
+#!/usr/bin/env bash
+# 生成编译进节点的插件列表：app/node/builtin_plugins.go
+# 用法：gen-builtin-plugins.sh [插件名=]导入路径 ...，插件包需导出 func Builder() goku_plugin.PluginFactory
+# 插件名默认为导入路径的最后一段；不带参数时删除生成的文件
+. $(dirname $0)/common.sh
+
+OUTFILE="${BasePath}/app/node/builtin_plugins.go"
+
+if [ $# == 0 ] ; then
+    rm -f ${OUTFILE}
+    cd ${ORGPATH}
+    exit 0
+fi
+
+imports=""
+registers=""
+index=0
+for i in $*
+do
+    name=${i%%=*}
+    path=${i#*=}
+    if [[ "$name" == "$i" ]]
+    then
+        name=${path##*/}
+    fi
+    imports="${imports}	p${index} \"${path}\"\n"
+    registers="${registers}	plugin_loader.Register(\"${name}\", p${index}.Builder)\n"
+    index=$((index+1))
+done
+
+printf "// Code generated by gen-builtin-plugins.sh. DO NOT EDIT.\n\n// +build builtin_plugins\n\npackage main\n\nimport (\n\tplugin_loader \"github.com/eolinker/goku-api-gateway/node/plugin-loader\"\n\n${imports})\n\nfunc init() {\n${registers}}\n" > ${OUTFILE}
+gofmt -w ${OUTFILE}
+echo "generate ${OUTFILE}"
+
+cd ${ORGPATH}
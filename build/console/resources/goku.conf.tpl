@@ -1,2 +1,38 @@
 listen_port: 7000
 admin_bind: 127.0.0.1:7005
+# 节点接入认证，开启后节点必须提供集群/节点凭证或客户端证书；凭证只接受经TLS（admin_tls_cert）提交
+# 节点通过 -token-file 或环境变量 GOKU_NODE_TOKEN 读取凭证
+# admin_require_auth: true
+# admin_tls_cert: ./config/admin.crt
+# admin_tls_key: ./config/admin.key
+# admin_client_ca: ./config/node-ca.crt
+# 节点经代理接入时，仅信任来自这些代理（逗号分隔的IP或CIDR）的X-Real-Ip
+# admin_trusted_proxies: 127.0.0.1,10.0.0.0/8
+# LDAP登录，{username}替换为登录名，{dn}替换为用户DN
+# ldap_url: ldap://127.0.0.1:389
+# ldap_bind_dn: cn=admin,dc=example,dc=com
+# ldap_bind_password: secret
+# ldap_base_dn: ou=people,dc=example,dc=com
+# ldap_user_filter: (uid={username})
+# ldap_group_base_dn: ou=groups,dc=example,dc=com
+# ldap_group_filter: (member={dn})
+# ldap_group_mapping: '{"ops":"运维组"}'
+# OIDC登录，回调地址为 http(s)://控制台地址/guest/oidc/callback
+# oidc_issuer: https://accounts.example.com
+# oidc_client_id: goku
+# oidc_client_secret: secret
+# oidc_redirect_url: http://127.0.0.1:7000/guest/oidc/callback
+# oidc_scopes: openid profile email groups
+# oidc_username_claim: preferred_username
+# oidc_groups_claim: groups
+# oidc_group_mapping: '{"gateway-admins":"管理组"}'
+# 目录分组未匹配时使用的权限分组，为空时拒绝登录
+# sso_default_group: 只读组
+# 数据库，默认使用sqlite3（db_path），可选postgres；mysql没有表结构迁移脚本，控制台启动时会拒绝
+# db_type: postgres
+# db_host: 127.0.0.1
+# db_port: 5432
+# db_user: goku
+# db_password: secret
+# db_name: goku
+# db_sslmode: disable
@@ -0,0 +1,408 @@
+-- ----------------------------
+-- Table structure for goku_admin
+-- ----------------------------
+CREATE TABLE goku_admin (
+  userID serial PRIMARY KEY,
+  loginCall varchar(255) NOT NULL,
+  loginPassword varchar(255) NOT NULL,
+  userType integer NOT NULL DEFAULT 0,
+  groupID integer NOT NULL DEFAULT 0,
+  remark varchar(255),
+  permissions text
+);
+
+-- ----------------------------
+-- Table structure for goku_balance
+-- ----------------------------
+CREATE TABLE goku_balance (
+  balanceID serial PRIMARY KEY,
+  balanceName varchar(255) NOT NULL,
+  serviceName varchar(255) NOT NULL,
+  balanceConfig text,
+  createTime text,
+  updateTime text,
+  balanceDesc varchar(255),
+  defaultConfig text NOT NULL,
+  clusterConfig text NOT NULL DEFAULT '',
+  appName varchar(255) NOT NULL DEFAULT '',
+  static text,
+  staticCluster text
+);
+
+-- ----------------------------
+-- Table structure for goku_cluster
+-- ----------------------------
+CREATE TABLE goku_cluster (
+  id serial PRIMARY KEY,
+  name varchar(20) NOT NULL,
+  title varchar(50) NOT NULL,
+  note varchar(255),
+  db text,
+  redis text
+);
+
+-- ----------------------------
+-- Table structure for goku_config_log
+-- ----------------------------
+CREATE TABLE goku_config_log (
+  id serial PRIMARY KEY,
+  name varchar(20) NOT NULL,
+  enable integer NOT NULL DEFAULT 1,
+  dir varchar(255) NOT NULL,
+  file varchar(255) NOT NULL,
+  period varchar(10) NOT NULL,
+  level varchar(10) NOT NULL,
+  fields text NOT NULL,
+  expire integer NOT NULL DEFAULT 3
+);
+
+-- ----------------------------
+-- Table structure for goku_conn_plugin_api
+-- ----------------------------
+CREATE TABLE goku_conn_plugin_api (
+  connID serial PRIMARY KEY,
+  apiID integer NOT NULL,
+  pluginName varchar(255) NOT NULL,
+  pluginConfig text,
+  strategyID varchar(255) NOT NULL,
+  pluginInfo text,
+  createTime text,
+  updateTime text,
+  pluginStatus integer,
+  updateTag varchar(32),
+  updaterID integer NOT NULL DEFAULT 0
+);
+
+-- ----------------------------
+-- Table structure for goku_conn_plugin_strategy
+-- ----------------------------
+CREATE TABLE goku_conn_plugin_strategy (
+  connID serial PRIMARY KEY,
+  strategyID varchar(255) NOT NULL,
+  pluginName varchar(255) NOT NULL,
+  pluginConfig text,
+  pluginInfo text,
+  createTime text,
+  updateTime text,
+  pluginStatus integer,
+  updateTag varchar(32),
+  updaterID integer NOT NULL DEFAULT 0
+);
+
+-- ----------------------------
+-- Table structure for goku_conn_strategy_api
+-- ----------------------------
+CREATE TABLE goku_conn_strategy_api (
+  connID serial PRIMARY KEY,
+  strategyID varchar(255) NOT NULL,
+  apiID integer NOT NULL,
+  apiMonitorStatus integer NOT NULL DEFAULT 0,
+  strategyMonitorStatus integer NOT NULL DEFAULT 0,
+  target varchar(255),
+  updateTime text
+);
+
+-- ----------------------------
+-- Table structure for goku_gateway
+-- ----------------------------
+CREATE TABLE goku_gateway (
+  id integer NOT NULL,
+  successCode varchar(255) NOT NULL,
+  nodeUpdatePeriod integer NOT NULL,
+  monitorUpdatePeriod integer NOT NULL,
+  alertStatus integer NOT NULL,
+  alertPeriodType integer NOT NULL,
+  alertAddress varchar(255),
+  alertLogPath varchar(255),
+  sender varchar(255),
+  senderPassword varchar(255),
+  smtpAddress varchar(255),
+  smtpPort integer NOT NULL,
+  smtpProtocol integer NOT NULL,
+  receiverList varchar(255),
+  monitorTimeout integer NOT NULL,
+  apiAlertInfo text,
+  nodeAlertInfo text,
+  redisAlertInfo text,
+  versionID integer NOT NULL DEFAULT 0,
+  PRIMARY KEY (id)
+);
+
+INSERT INTO goku_gateway VALUES (1, 200, 1, 30, 0, 0, NULL, NULL, NULL, NULL, NULL, 25, 0, NULL, 0, NULL, NULL, NULL, 0);
+
+-- ----------------------------
+-- Table structure for goku_gateway_alert
+-- ----------------------------
+CREATE TABLE goku_gateway_alert (
+  alertID serial PRIMARY KEY,
+  requestURL varchar(255) NOT NULL,
+  targetServer varchar(255) NOT NULL,
+  alertPeriodType integer NOT NULL,
+  alertCount integer NOT NULL,
+  updateTime text,
+  targetURL varchar(255) NOT NULL,
+  clusterName varchar(255),
+  nodeIP varchar(255)
+);
+
+-- ----------------------------
+-- Table structure for goku_gateway_api
+-- ----------------------------
+CREATE TABLE goku_gateway_api (
+  apiID serial PRIMARY KEY,
+  groupID integer NOT NULL,
+  projectID integer NOT NULL,
+  requestURL varchar(255) NOT NULL,
+  apiName varchar(255) NOT NULL,
+  requestMethod varchar(255) NOT NULL,
+  targetServer varchar(255),
+  targetURL varchar(255),
+  targetMethod varchar(255),
+  isFollow varchar(32) NOT NULL,
+  stripPrefix varchar(32),
+  timeout integer,
+  retryCount integer,
+  createTime text,
+  updateTime text,
+  alertValve integer NOT NULL DEFAULT 0,
+  monitorStatus integer NOT NULL DEFAULT 1,
+  managerID integer NOT NULL,
+  lastUpdateUserID integer NOT NULL,
+  createUserID integer NOT NULL,
+  balanceName varchar(255),
+  protocol varchar(20),
+  stripSlash varchar(32),
+  apiType integer NOT NULL DEFAULT 0,
+  responseDataType text NOT NULL DEFAULT 'origin',
+  linkApis TEXT,
+  staticResponse TEXT
+);
+
+-- ----------------------------
+-- Table structure for goku_gateway_api_group
+-- ----------------------------
+CREATE TABLE goku_gateway_api_group (
+  groupID serial PRIMARY KEY,
+  projectID integer NOT NULL,
+  groupName varchar(255) NOT NULL,
+  groupPath varchar(255),
+  groupDepth varchar(255),
+  parentGroupID integer NOT NULL DEFAULT 0
+);
+
+-- ----------------------------
+-- Table structure for goku_gateway_permission_group
+-- ----------------------------
+CREATE TABLE goku_gateway_permission_group (
+  groupID serial PRIMARY KEY,
+  groupName varchar(255) NOT NULL,
+  permissions text
+);
+
+-- ----------------------------
+-- Table structure for goku_gateway_project
+-- ----------------------------
+CREATE TABLE goku_gateway_project (
+  projectID serial PRIMARY KEY,
+  projectName varchar(255) NOT NULL,
+  createTime text,
+  updateTime text
+);
+
+-- ----------------------------
+-- Table structure for goku_gateway_strategy
+-- ----------------------------
+CREATE TABLE goku_gateway_strategy (
+  strategyID varchar(32) NOT NULL,
+  strategyName varchar(255) NOT NULL,
+  updateTime text,
+  createTime text,
+  auth varchar(255),
+  groupID integer NOT NULL DEFAULT 0,
+  monitorStatus integer NOT NULL DEFAULT 0,
+  enableStatus integer NOT NULL DEFAULT 0,
+  strategyType integer NOT NULL DEFAULT 0,
+  PRIMARY KEY (strategyID)
+);
+
+-- ----------------------------
+-- Records of goku_gateway_strategy
+-- ----------------------------
+INSERT INTO goku_gateway_strategy VALUES ('RGAtKBd', '开放策略', '2019-10-17 00:00:00', '2019-10-17 00:00:00', NULL, 0, 0, 0, 1);
+
+-- ----------------------------
+-- Table structure for goku_gateway_strategy_group
+-- ----------------------------
+CREATE TABLE goku_gateway_strategy_group (
+  groupID serial PRIMARY KEY,
+  groupName varchar(255) NOT NULL,
+  groupType integer NOT NULL DEFAULT 0
+);
+
+-- ----------------------------
+-- Records of goku_gateway_strategy_group
+-- ----------------------------
+INSERT INTO goku_gateway_strategy_group VALUES (1, '开放分组', 1);
+
+-- ----------------------------
+-- Table structure for goku_gateway_version_config
+-- ----------------------------
+CREATE TABLE goku_gateway_version_config (
+  versionID serial PRIMARY KEY,
+  name TEXT NOT NULL,
+  version TEXT,
+  remark TEXT,
+  createTime TEXT,
+  updateTime TEXT,
+  publishTime TEXT,
+  config TEXT,
+  balanceConfig TEXT,
+  discoverConfig TEXT
+);
+
+-- ----------------------------
+-- Table structure for goku_monitor_cluster
+-- ----------------------------
+CREATE TABLE goku_monitor_cluster (
+  recordID serial PRIMARY KEY,
+  strategyID varchar(20) NOT NULL,
+  apiID integer NOT NULL,
+  clusterID integer NOT NULL,
+  hour integer NOT NULL,
+  gatewayRequestCount integer NOT NULL,
+  gatewaySuccessCount integer NOT NULL,
+  gatewayStatus2xxCount integer NOT NULL,
+  gatewayStatus4xxCount integer NOT NULL,
+  gatewayStatus5xxCount integer NOT NULL,
+  proxyRequestCount integer NOT NULL,
+  proxySuccessCount integer NOT NULL,
+  proxyStatus2xxCount integer NOT NULL,
+  proxyStatus4xxCount integer NOT NULL,
+  proxyStatus5xxCount integer NOT NULL,
+  proxyTimeoutCount integer NOT NULL,
+  updateTime text NOT NULL
+);
+
+-- ----------------------------
+-- Table structure for goku_node_group
+-- ----------------------------
+CREATE TABLE goku_node_group (
+  groupID serial PRIMARY KEY,
+  groupName varchar(255) NOT NULL,
+  groupType integer NOT NULL,
+  clusterID integer NOT NULL
+);
+
+-- ----------------------------
+-- Table structure for goku_node_info
+-- ----------------------------
+CREATE TABLE goku_node_info (
+  nodeID serial PRIMARY KEY,
+  nodeIP varchar(255) NOT NULL,
+  updateStatus integer NOT NULL DEFAULT 0,
+  createTime text,
+  updateTime text,
+  groupID integer NOT NULL DEFAULT 0,
+  nodeName varchar(255) NOT NULL,
+  nodePort varchar(255),
+  nodeStatus integer NOT NULL,
+  version varchar(255),
+  sshPort varchar(255) DEFAULT 22,
+  userName varchar(255),
+  password varchar(255),
+  gatewayPath varchar(255),
+  key text,
+  authMethod integer NOT NULL DEFAULT 0,
+  clusterID integer NOT NULL DEFAULT 0
+);
+
+-- ----------------------------
+-- Table structure for goku_plugin
+-- ----------------------------
+CREATE TABLE goku_plugin (
+  pluginID serial PRIMARY KEY,
+  pluginName varchar(255) NOT NULL,
+  chineseName varchar(255),
+  pluginStatus integer NOT NULL,
+  pluginPriority integer NOT NULL,
+  pluginConfig text,
+  pluginInfo text,
+  isStop integer NOT NULL,
+  pluginType integer NOT NULL,
+  official varchar(255) NOT NULL,
+  pluginDesc varchar(255),
+  version varchar(255) NOT NULL,
+  isCheck integer NOT NULL
+);
+
+-- ----------------------------
+-- Table structure for goku_service_config
+-- ----------------------------
+CREATE TABLE goku_service_config (
+  id serial PRIMARY KEY,
+  name varchar(255) NOT NULL,
+  "default" integer,
+  driver varchar(20) NOT NULL,
+  "desc" text NOT NULL,
+  config text NOT NULL,
+  clusterConfig text NOT NULL,
+  healthCheck integer NOT NULL,
+  healthCheckPath varchar(255) NOT NULL,
+  healthCheckPeriod integer NOT NULL,
+  healthCheckCode varchar(255) NOT NULL,
+  healthCheckTimeOut integer NOT NULL,
+  createTime text NOT NULL,
+  updateTime text NOT NULL
+);
+
+-- ----------------------------
+-- Table structure for goku_service_discovery
+-- ----------------------------
+CREATE TABLE goku_service_discovery (
+  id serial PRIMARY KEY,
+  name varchar(30),
+  type varchar(20),
+  remark varchar(500),
+  config text,
+  "default" varchar(255),
+  createTime text,
+  updateTime text
+);
+
+-- ----------------------------
+-- Table structure for goku_table_update_record
+-- ----------------------------
+CREATE TABLE goku_table_update_record (
+  name varchar(64) NOT NULL,
+  updateTime text NOT NULL,
+  tableID serial PRIMARY KEY
+);
+
+-- ----------------------------
+-- Indexes structure for table goku_balance
+-- ----------------------------
+CREATE INDEX balanceName
+ON goku_balance (
+  balanceName
+);
+
+-- ----------------------------
+-- Indexes structure for table goku_cluster
+-- ----------------------------
+CREATE INDEX name
+ON goku_cluster (
+  name
+);
+
+-- ----------------------------
+-- Indexes structure for table goku_config_log
+-- ----------------------------
+CREATE UNIQUE INDEX configLogName
+ON goku_config_log (
+  name
+);
+
+-- ----------------------------
+-- Sequence value for goku_gateway_strategy_group
+-- ----------------------------
+SELECT setval(pg_get_serial_sequence('goku_gateway_strategy_group', 'groupid'), 1);
@@ -0,0 +1,7 @@
+-- ----------------------------
+-- 节点接入认证
+-- ----------------------------
+ALTER TABLE goku_cluster ADD COLUMN token text;
+ALTER TABLE goku_cluster ADD COLUMN autoRegister integer NOT NULL DEFAULT 0;
+ALTER TABLE goku_cluster ADD COLUMN registerGroupID integer NOT NULL DEFAULT 0;
+ALTER TABLE goku_node_info ADD COLUMN token text;
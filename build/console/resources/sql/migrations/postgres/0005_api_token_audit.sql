@@ -0,0 +1,55 @@
+-- ----------------------------
+-- Table structure for goku_api_token
+-- ----------------------------
+CREATE TABLE goku_api_token (
+  tokenID serial PRIMARY KEY,
+  tokenName varchar(255) NOT NULL,
+  tokenHash varchar(64) NOT NULL,
+  tokenType varchar(20) NOT NULL DEFAULT 'personal',
+  userID integer NOT NULL,
+  scopes text NOT NULL,
+  expireTime text NOT NULL DEFAULT '',
+  lastUsedTime text NOT NULL DEFAULT '',
+  createTime text NOT NULL
+);
+
+-- ----------------------------
+-- Table structure for goku_audit_log
+-- ----------------------------
+CREATE TABLE goku_audit_log (
+  logID serial PRIMARY KEY,
+  userID integer NOT NULL DEFAULT 0,
+  remoteAddr varchar(255) NOT NULL DEFAULT '',
+  forwardedFor varchar(255) NOT NULL DEFAULT '',
+  method varchar(10) NOT NULL,
+  operation varchar(255) NOT NULL,
+  targetType varchar(50) NOT NULL DEFAULT '',
+  targetID varchar(255) NOT NULL DEFAULT '',
+  requestData text,
+  beforeData text,
+  afterData text,
+  statusCode varchar(20) NOT NULL DEFAULT '',
+  resultDesc text,
+  operateTime text NOT NULL
+);
+
+-- ----------------------------
+-- Indexes structure for table goku_api_token
+-- ----------------------------
+CREATE UNIQUE INDEX tokenHash
+ON goku_api_token (
+  tokenHash
+);
+
+-- ----------------------------
+-- Indexes structure for table goku_audit_log
+-- ----------------------------
+CREATE INDEX operateTime
+ON goku_audit_log (
+  operateTime
+);
+CREATE INDEX auditTarget
+ON goku_audit_log (
+  targetType,
+  targetID
+);
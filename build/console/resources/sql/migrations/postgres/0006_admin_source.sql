@@ -0,0 +1,4 @@
+-- ----------------------------
+-- 用户来源：local、ldap、oidc
+-- ----------------------------
+ALTER TABLE goku_admin ADD COLUMN source varchar(32) NOT NULL DEFAULT 'local';
@@ -0,0 +1,20 @@
+-- ----------------------------
+-- 监控数据：请求耗时，按集群、策略、接口、小时唯一
+-- ----------------------------
+ALTER TABLE goku_monitor_cluster ADD COLUMN requestTime bigint NOT NULL DEFAULT 0;
+ALTER TABLE goku_monitor_cluster ADD COLUMN maxRequestTime integer NOT NULL DEFAULT 0;
+
+-- ----------------------------
+-- Indexes structure for table goku_monitor_cluster
+-- ----------------------------
+CREATE UNIQUE INDEX monitorKey
+ON goku_monitor_cluster (
+  clusterID,
+  strategyID,
+  apiID,
+  hour
+);
+CREATE INDEX monitorHour
+ON goku_monitor_cluster (
+  hour
+);
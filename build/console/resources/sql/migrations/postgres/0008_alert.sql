@@ -0,0 +1,50 @@
+-- ----------------------------
+-- Table structure for goku_alert_rule
+-- ----------------------------
+CREATE TABLE goku_alert_rule (
+  ruleID serial PRIMARY KEY,
+  ruleName varchar(255) NOT NULL,
+  ruleType varchar(50) NOT NULL,
+  clusterID integer NOT NULL DEFAULT 0,
+  strategyID varchar(255) NOT NULL DEFAULT '',
+  apiID integer NOT NULL DEFAULT 0,
+  threshold integer NOT NULL DEFAULT 0,
+  period integer NOT NULL DEFAULT 5,
+  cooldown integer NOT NULL DEFAULT 30,
+  receivers text NOT NULL DEFAULT '',
+  webhookURL text NOT NULL DEFAULT '',
+  webhookTemplate text NOT NULL DEFAULT '',
+  enable integer NOT NULL DEFAULT 1,
+  createTime text NOT NULL,
+  updateTime text NOT NULL
+);
+
+-- ----------------------------
+-- Table structure for goku_alert_history
+-- ----------------------------
+CREATE TABLE goku_alert_history (
+  alertID serial PRIMARY KEY,
+  ruleID integer NOT NULL,
+  ruleName varchar(255) NOT NULL,
+  ruleType varchar(50) NOT NULL,
+  target varchar(255) NOT NULL,
+  value double precision NOT NULL DEFAULT 0,
+  threshold integer NOT NULL DEFAULT 0,
+  message text NOT NULL,
+  notifyStatus integer NOT NULL DEFAULT 0,
+  notifyError text NOT NULL DEFAULT '',
+  alertTime text NOT NULL
+);
+
+-- ----------------------------
+-- Indexes structure for table goku_alert_history
+-- ----------------------------
+CREATE INDEX alertRuleTarget
+ON goku_alert_history (
+  ruleID,
+  target
+);
+CREATE INDEX alertTime
+ON goku_alert_history (
+  alertTime
+);
@@ -1,9 +1,6 @@
-PRAGMA foreign_keys = false;
-
 -- ----------------------------
 -- Table structure for goku_admin
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_admin";
 CREATE TABLE "goku_admin" (
   "userID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "loginCall" text(255) NOT NULL,
@@ -17,7 +14,6 @@ CREATE TABLE "goku_admin" (
 -- ----------------------------
 -- Table structure for goku_balance
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_balance";
 CREATE TABLE "goku_balance" (
   "balanceID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "balanceName" text(255) NOT NULL,
@@ -36,7 +32,6 @@ CREATE TABLE "goku_balance" (
 -- ----------------------------
 -- Table structure for goku_cluster
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_cluster";
 CREATE TABLE "goku_cluster" (
   "id" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "name" text(20) NOT NULL,
@@ -49,7 +44,6 @@ CREATE TABLE "goku_cluster" (
 -- ----------------------------
 -- Table structure for goku_config_log
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_config_log";
 CREATE TABLE "goku_config_log" (
   "id" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "name" text(20) NOT NULL,
@@ -65,7 +59,6 @@ CREATE TABLE "goku_config_log" (
 -- ----------------------------
 -- Table structure for goku_conn_plugin_api
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_conn_plugin_api";
 CREATE TABLE "goku_conn_plugin_api" (
   "connID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "apiID" integer(11) NOT NULL,
@@ -83,7 +76,6 @@ CREATE TABLE "goku_conn_plugin_api" (
 -- ----------------------------
 -- Table structure for goku_conn_plugin_strategy
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_conn_plugin_strategy";
 CREATE TABLE "goku_conn_plugin_strategy" (
   "connID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "strategyID" text(255) NOT NULL,
@@ -100,7 +92,6 @@ CREATE TABLE "goku_conn_plugin_strategy" (
 -- ----------------------------
 -- Table structure for goku_conn_strategy_api
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_conn_strategy_api";
 CREATE TABLE "goku_conn_strategy_api" (
   "connID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "strategyID" text(255) NOT NULL,
@@ -114,7 +105,6 @@ CREATE TABLE "goku_conn_strategy_api" (
 -- ----------------------------
 -- Table structure for goku_gateway
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_gateway";
 CREATE TABLE "goku_gateway" (
   "id" integer(11) NOT NULL,
   "successCode" text(255) NOT NULL,
@@ -143,7 +133,6 @@ INSERT INTO "goku_gateway" VALUES (1, 200, 1, 30, 0, 0, NULL, NULL, NULL, NULL,
 -- ----------------------------
 -- Table structure for goku_gateway_alert
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_gateway_alert";
 CREATE TABLE "goku_gateway_alert" (
   "alertID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "requestURL" text(255) NOT NULL,
@@ -159,7 +148,6 @@ CREATE TABLE "goku_gateway_alert" (
 -- ----------------------------
 -- Table structure for goku_gateway_api
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_gateway_api";
 CREATE TABLE "goku_gateway_api" (
   "apiID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "groupID" integer(11) NOT NULL,
@@ -193,7 +181,6 @@ CREATE TABLE "goku_gateway_api" (
 -- ----------------------------
 -- Table structure for goku_gateway_api_group
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_gateway_api_group";
 CREATE TABLE "goku_gateway_api_group" (
   "groupID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "projectID" integer(11) NOT NULL,
@@ -206,7 +193,6 @@ CREATE TABLE "goku_gateway_api_group" (
 -- ----------------------------
 -- Table structure for goku_gateway_permission_group
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_gateway_permission_group";
 CREATE TABLE "goku_gateway_permission_group" (
   "groupID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "groupName" text(255) NOT NULL,
@@ -216,7 +202,6 @@ CREATE TABLE "goku_gateway_permission_group" (
 -- ----------------------------
 -- Table structure for goku_gateway_project
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_gateway_project";
 CREATE TABLE "goku_gateway_project" (
   "projectID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "projectName" text(255) NOT NULL,
@@ -227,7 +212,6 @@ CREATE TABLE "goku_gateway_project" (
 -- ----------------------------
 -- Table structure for goku_gateway_strategy
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_gateway_strategy";
 CREATE TABLE "goku_gateway_strategy" (
   "strategyID" text(32) NOT NULL,
   "strategyName" text(255) NOT NULL,
@@ -249,7 +233,6 @@ INSERT INTO "goku_gateway_strategy" VALUES ('RGAtKBd', '开放策略', '2019-10-
 -- ----------------------------
 -- Table structure for goku_gateway_strategy_group
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_gateway_strategy_group";
 CREATE TABLE "goku_gateway_strategy_group" (
   "groupID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "groupName" text(255) NOT NULL,
@@ -264,7 +247,6 @@ INSERT INTO "goku_gateway_strategy_group" VALUES (1, '开放分组', 1);
 -- ----------------------------
 -- Table structure for goku_gateway_version_config
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_gateway_version_config";
 CREATE TABLE "goku_gateway_version_config" (
   "versionID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "name" TEXT NOT NULL,
@@ -281,7 +263,6 @@ CREATE TABLE "goku_gateway_version_config" (
 -- ----------------------------
 -- Table structure for goku_monitor_cluster
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_monitor_cluster";
 CREATE TABLE "goku_monitor_cluster" (
   "recordID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "strategyID" text(20) NOT NULL,
@@ -305,7 +286,6 @@ CREATE TABLE "goku_monitor_cluster" (
 -- ----------------------------
 -- Table structure for goku_node_group
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_node_group";
 CREATE TABLE "goku_node_group" (
   "groupID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "groupName" text(255) NOT NULL,
@@ -316,7 +296,6 @@ CREATE TABLE "goku_node_group" (
 -- ----------------------------
 -- Table structure for goku_node_info
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_node_info";
 CREATE TABLE "goku_node_info" (
   "nodeID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "nodeIP" text(255) NOT NULL,
@@ -340,7 +319,6 @@ CREATE TABLE "goku_node_info" (
 -- ----------------------------
 -- Table structure for goku_plugin
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_plugin";
 CREATE TABLE "goku_plugin" (
   "pluginID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "pluginName" text(255) NOT NULL,
@@ -357,11 +335,9 @@ CREATE TABLE "goku_plugin" (
   "isCheck" integer(4) NOT NULL
 );
 
-
 -- ----------------------------
 -- Table structure for goku_service_config
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_service_config";
 CREATE TABLE "goku_service_config" (
   "id" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "name" text(255) NOT NULL,
@@ -382,7 +358,6 @@ CREATE TABLE "goku_service_config" (
 -- ----------------------------
 -- Table structure for goku_service_discovery
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_service_discovery";
 CREATE TABLE "goku_service_discovery" (
   "id" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
   "name" text(30),
@@ -397,14 +372,12 @@ CREATE TABLE "goku_service_discovery" (
 -- ----------------------------
 -- Table structure for goku_table_update_record
 -- ----------------------------
-DROP TABLE IF EXISTS "goku_table_update_record";
 CREATE TABLE "goku_table_update_record" (
   "name" text(64) NOT NULL,
   "updateTime" text NOT NULL,
   "tableID" integer NOT NULL PRIMARY KEY AUTOINCREMENT
 );
 
-
 -- ----------------------------
 -- Records of "sqlite_sequence"
 -- ----------------------------
@@ -453,4 +426,3 @@ UPDATE "sqlite_sequence" SET seq = 1 WHERE name = 'goku_gateway_strategy_group';
 -- ----------------------------
 UPDATE "sqlite_sequence" SET seq = 30 WHERE name = 'goku_plugin';
 
-PRAGMA foreign_keys = true;
@@ -0,0 +1,11 @@
+-- ----------------------------
+-- 灰度流量、跨域、IP访问控制、请求转换及Mock配置
+-- ----------------------------
+ALTER TABLE "goku_conn_strategy_api" ADD COLUMN "canary" text;
+ALTER TABLE "goku_gateway" ADD COLUMN "trustedProxies" text;
+ALTER TABLE "goku_gateway_strategy" ADD COLUMN "cors" text;
+ALTER TABLE "goku_gateway_strategy" ADD COLUMN "ipAccess" text;
+ALTER TABLE "goku_gateway_api" ADD COLUMN "cors" TEXT;
+ALTER TABLE "goku_gateway_api" ADD COLUMN "ipAccess" TEXT;
+ALTER TABLE "goku_gateway_api" ADD COLUMN "transform" TEXT;
+ALTER TABLE "goku_gateway_api" ADD COLUMN "mock" TEXT;
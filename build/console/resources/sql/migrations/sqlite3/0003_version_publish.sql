@@ -0,0 +1,42 @@
+-- ----------------------------
+-- Table structure for goku_gateway_version_publish
+-- ----------------------------
+CREATE TABLE "goku_gateway_version_publish" (
+  "id" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "versionID" integer NOT NULL,
+  "action" TEXT NOT NULL,
+  "operatorID" integer NOT NULL DEFAULT 0,
+  "publishTime" TEXT
+);
+
+-- ----------------------------
+-- Table structure for goku_gateway_version_rollout
+-- ----------------------------
+CREATE TABLE "goku_gateway_version_rollout" (
+  "id" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "versionID" integer NOT NULL,
+  "baseVersionID" integer NOT NULL DEFAULT 0,
+  "targetType" TEXT NOT NULL,
+  "target" TEXT,
+  "autoAbort" integer(4) NOT NULL DEFAULT 0,
+  "failureThreshold" integer NOT NULL DEFAULT 1,
+  "applyTimeout" integer NOT NULL DEFAULT 60,
+  "status" TEXT NOT NULL,
+  "reason" TEXT,
+  "operatorID" integer NOT NULL DEFAULT 0,
+  "createTime" TEXT,
+  "updateTime" TEXT
+);
+
+-- ----------------------------
+-- Table structure for goku_node_apply_status
+-- ----------------------------
+CREATE TABLE "goku_node_apply_status" (
+  "nodeID" integer NOT NULL PRIMARY KEY,
+  "version" TEXT,
+  "status" TEXT NOT NULL,
+  "error" TEXT,
+  "failedVersion" TEXT,
+  "plugins" TEXT,
+  "reportTime" TEXT
+);
@@ -0,0 +1,55 @@
+-- ----------------------------
+-- Table structure for goku_api_token
+-- ----------------------------
+CREATE TABLE "goku_api_token" (
+  "tokenID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "tokenName" text(255) NOT NULL,
+  "tokenHash" text(64) NOT NULL,
+  "tokenType" text(20) NOT NULL DEFAULT 'personal',
+  "userID" integer(11) NOT NULL,
+  "scopes" text NOT NULL,
+  "expireTime" text NOT NULL DEFAULT '',
+  "lastUsedTime" text NOT NULL DEFAULT '',
+  "createTime" text NOT NULL
+);
+
+-- ----------------------------
+-- Table structure for goku_audit_log
+-- ----------------------------
+CREATE TABLE "goku_audit_log" (
+  "logID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "userID" integer(11) NOT NULL DEFAULT 0,
+  "remoteAddr" text(255) NOT NULL DEFAULT '',
+  "forwardedFor" text(255) NOT NULL DEFAULT '',
+  "method" text(10) NOT NULL,
+  "operation" text(255) NOT NULL,
+  "targetType" text(50) NOT NULL DEFAULT '',
+  "targetID" text(255) NOT NULL DEFAULT '',
+  "requestData" text,
+  "beforeData" text,
+  "afterData" text,
+  "statusCode" text(20) NOT NULL DEFAULT '',
+  "resultDesc" text,
+  "operateTime" text NOT NULL
+);
+
+-- ----------------------------
+-- Indexes structure for table goku_api_token
+-- ----------------------------
+CREATE UNIQUE INDEX "tokenHash"
+ON "goku_api_token" (
+  "tokenHash" ASC
+);
+
+-- ----------------------------
+-- Indexes structure for table goku_audit_log
+-- ----------------------------
+CREATE INDEX "operateTime"
+ON "goku_audit_log" (
+  "operateTime" ASC
+);
+CREATE INDEX "auditTarget"
+ON "goku_audit_log" (
+  "targetType" ASC,
+  "targetID" ASC
+);
@@ -0,0 +1,50 @@
+-- ----------------------------
+-- Table structure for goku_alert_rule
+-- ----------------------------
+CREATE TABLE "goku_alert_rule" (
+  "ruleID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "ruleName" text(255) NOT NULL,
+  "ruleType" text(50) NOT NULL,
+  "clusterID" integer(11) NOT NULL DEFAULT 0,
+  "strategyID" text(255) NOT NULL DEFAULT '',
+  "apiID" integer(11) NOT NULL DEFAULT 0,
+  "threshold" integer(11) NOT NULL DEFAULT 0,
+  "period" integer(11) NOT NULL DEFAULT 5,
+  "cooldown" integer(11) NOT NULL DEFAULT 30,
+  "receivers" text NOT NULL DEFAULT '',
+  "webhookURL" text NOT NULL DEFAULT '',
+  "webhookTemplate" text NOT NULL DEFAULT '',
+  "enable" integer(4) NOT NULL DEFAULT 1,
+  "createTime" text NOT NULL,
+  "updateTime" text NOT NULL
+);
+
+-- ----------------------------
+-- Table structure for goku_alert_history
+-- ----------------------------
+CREATE TABLE "goku_alert_history" (
+  "alertID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "ruleID" integer(11) NOT NULL,
+  "ruleName" text(255) NOT NULL,
+  "ruleType" text(50) NOT NULL,
+  "target" text(255) NOT NULL,
+  "value" real NOT NULL DEFAULT 0,
+  "threshold" integer(11) NOT NULL DEFAULT 0,
+  "message" text NOT NULL,
+  "notifyStatus" integer(4) NOT NULL DEFAULT 0,
+  "notifyError" text NOT NULL DEFAULT '',
+  "alertTime" text NOT NULL
+);
+
+-- ----------------------------
+-- Indexes structure for table goku_alert_history
+-- ----------------------------
+CREATE INDEX "alertRuleTarget"
+ON "goku_alert_history" (
+  "ruleID" ASC,
+  "target" ASC
+);
+CREATE INDEX "alertTime"
+ON "goku_alert_history" (
+  "alertTime" ASC
+);
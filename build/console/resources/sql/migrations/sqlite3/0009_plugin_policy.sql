@@ -0,0 +1,35 @@
+-- ----------------------------
+-- 插件执行超时时间（毫秒，0不限制）及panic、超时时的失败策略：continue、stop、fallback
+-- ----------------------------
+ALTER TABLE "goku_plugin" ADD COLUMN "pluginTimeout" integer NOT NULL DEFAULT 0;
+ALTER TABLE "goku_plugin" ADD COLUMN "failPolicy" text(32) NOT NULL DEFAULT 'continue';
+ALTER TABLE "goku_plugin" ADD COLUMN "failStatus" integer NOT NULL DEFAULT 0;
+ALTER TABLE "goku_plugin" ADD COLUMN "failBody" text NOT NULL DEFAULT '';
+
+-- ----------------------------
+-- Table structure for goku_monitor_plugin
+-- 插件执行计数，按集群、插件、小时唯一，耗时单位微秒
+-- ----------------------------
+CREATE TABLE "goku_monitor_plugin" (
+  "recordID" integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "clusterID" integer NOT NULL,
+  "pluginName" text(255) NOT NULL,
+  "hour" integer NOT NULL,
+  "callCount" integer NOT NULL DEFAULT 0,
+  "errorCount" integer NOT NULL DEFAULT 0,
+  "panicCount" integer NOT NULL DEFAULT 0,
+  "timeoutCount" integer NOT NULL DEFAULT 0,
+  "callTime" integer NOT NULL DEFAULT 0,
+  "maxCallTime" integer NOT NULL DEFAULT 0,
+  "updateTime" text NOT NULL
+);
+
+-- ----------------------------
+-- Indexes structure for table goku_monitor_plugin
+-- ----------------------------
+CREATE UNIQUE INDEX "monitorPluginKey"
+ON "goku_monitor_plugin" (
+  "clusterID" ASC,
+  "pluginName" ASC,
+  "hour" ASC
+);
@@ -3,8 +3,6 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"io/ioutil"
-	"strings"
 
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 
@@ -26,7 +24,11 @@ func InitConnection(config Config) error {
 }
 func getConnection(config Config) (*sql.DB, error) {
 
-	db, e := sql.Open(config.GetDriver(), config.GetSource())
+	dialect, has := GetDialect(config.GetDriver())
+	if !has {
+		return nil, fmt.Errorf("unsupported database driver: %s", config.GetDriver())
+	}
+	db, e := sql.Open(dialect.Driver(), config.GetSource())
 
 	if e == nil {
 		if err := db.Ping(); err != nil {
@@ -36,6 +38,7 @@ func getConnection(config Config) (*sql.DB, error) {
 		db.SetMaxOpenConns(1000)
 		db.SetMaxIdleConns(100)
 		defaultDB = db
+		defaultDialect = dialect
 		return db, nil
 	}
 	log.Info(e)
@@ -47,22 +50,3 @@ func getConnection(config Config) (*sql.DB, error) {
 func GetConnection() *sql.DB {
 	return defaultDB
 }
-
-//InitTable 初始化表
-func InitTable() error {
-
-	content, err := ioutil.ReadFile("sql/goku_ce.sql")
-	sqls := strings.Split(string(content), ";")
-	Tx, _ := GetConnection().Begin()
-	for _, sql := range sqls {
-		_, err = Tx.Exec(sql)
-		if err != nil {
-			fmt.Println(sql)
-			Tx.Rollback()
-			panic(err)
-			return err
-		}
-	}
-	Tx.Commit()
-	return nil
-}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+//Dialect SQL方言，DAO统一使用SQLite语法书写语句，执行前由方言转换为目标数据库的语法
+type Dialect interface {
+	//Name 方言名称，与配置中的driver一致
+	Name() string
+	//Driver 注册到database/sql的驱动名称
+	Driver() string
+	//MigrationDir 表结构迁移脚本所在目录，不支持迁移时返回空字符串
+	MigrationDir() string
+	//Rewrite 将SQLite语法的语句转换为目标数据库语法
+	Rewrite(query string) string
+}
+
+//serialDialect 不支持LastInsertId的方言，INSERT时通过RETURNING取回表的自增主键
+type serialDialect interface {
+	//SerialKey 表的自增主键，表没有自增主键时返回false
+	SerialKey(table string) (string, bool)
+}
+
+type nativeDialect string
+
+func (d nativeDialect) Name() string {
+	return string(d)
+}
+
+func (d nativeDialect) Driver() string {
+	return string(d)
+}
+
+//MigrationDir 迁移脚本按SQLite语法书写，无法在mysql上执行，mysql不提供迁移脚本
+func (d nativeDialect) MigrationDir() string {
+	if d != "sqlite3" {
+		return ""
+	}
+	return "sql/migrations/sqlite3"
+}
+
+func (d nativeDialect) Rewrite(query string) string {
+	return query
+}
+
+var (
+	dialects = map[string]Dialect{
+		"sqlite3":  nativeDialect("sqlite3"),
+		"mysql":    nativeDialect("mysql"),
+		"postgres": postgresDialect{},
+	}
+	defaultDialect = dialects["sqlite3"]
+)
+
+//GetDialect 获取driver对应的方言
+func GetDialect(driver string) (Dialect, bool) {
+	d, has := dialects[driver]
+	return d, has
+}
+
+//CurrentDialect 获取当前连接使用的方言
+func CurrentDialect() Dialect {
+	return defaultDialect
+}
+
+//rewriteDriver 在预处理语句前转换语法的驱动，用于不兼容SQLite语法的数据库
+type rewriteDriver struct {
+	driver  driver.Driver
+	dialect Dialect
+}
+
+func (d *rewriteDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &rewriteConn{Conn: conn, dialect: d.dialect}, nil
+}
+
+//rewriteConn 只暴露driver.Conn的方法，使database/sql的全部调用都经过Prepare
+type rewriteConn struct {
+	driver.Conn
+	dialect Dialect
+}
+
+func (c *rewriteConn) Prepare(query string) (driver.Stmt, error) {
+	query = c.dialect.Rewrite(query)
+	insert := false
+	if d, ok := c.dialect.(serialDialect); ok {
+		if key, has := d.SerialKey(insertTable(query)); has {
+			insert = true
+			query = strings.TrimRight(strings.TrimSpace(query), ";") + " RETURNING " + key
+		}
+	}
+	stmt, err := c.Conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &rewriteStmt{Stmt: stmt, insert: insert}, nil
+}
+
+var insertInto = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+("?\w+"?)`)
+
+//insertTable INSERT语句的表名，非INSERT语句或已有RETURNING时返回空字符串
+func insertTable(query string) string {
+	m := insertInto.FindStringSubmatch(query)
+	if m == nil || strings.Contains(strings.ToUpper(query), "RETURNING") {
+		return ""
+	}
+	return strings.ToLower(strings.Trim(m[1], `"`))
+}
+
+type rewriteStmt struct {
+	driver.Stmt
+	insert bool
+}
+
+func (s *rewriteStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if !s.insert {
+		return s.Stmt.Exec(args)
+	}
+	rows, err := s.Stmt.Query(args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := new(insertResult)
+	values := make([]driver.Value, len(rows.Columns()))
+	for {
+		err := rows.Next(values)
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.affected++
+		result.id, result.hasID = values[0].(int64)
+	}
+}
+
+type insertResult struct {
+	id       int64
+	hasID    bool
+	affected int64
+}
+
+var errorNoInsertID = errors.New("database: table has no integer primary key")
+
+func (r *insertResult) LastInsertId() (int64, error) {
+	if !r.hasID {
+		return 0, errorNoInsertID
+	}
+	return r.id, nil
+}
+
+func (r *insertResult) RowsAffected() (int64, error) {
+	return r.affected, nil
+}
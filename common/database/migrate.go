@@ -0,0 +1,178 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Migration 表结构迁移脚本，文件名格式为 版本号_名称.sql
+type Migration struct {
+	Version int
+	Name    string
+	Path    string
+}
+
+//MigrationStatus 表结构版本状态
+type MigrationStatus struct {
+	//Current 数据库当前版本，0表示未初始化
+	Current int
+	//Latest 迁移脚本的最新版本
+	Latest int
+	//Baseline 数据库由未记录版本的旧版本创建，视为第一个版本
+	Baseline bool
+	//Pending 待执行的迁移
+	Pending []*Migration
+}
+
+//ErrorSchemaTooNew 数据库表结构版本高于当前程序
+var ErrorSchemaTooNew = errors.New("database schema is newer than this release, please upgrade the console")
+
+//ErrorMigrationUnsupported 当前数据库没有可用的迁移脚本
+var ErrorMigrationUnsupported = errors.New("database migration is not supported for this driver, please use sqlite3 or postgres")
+
+const createSchemaVersion = `CREATE TABLE IF NOT EXISTS goku_schema_version (
+  version integer NOT NULL PRIMARY KEY,
+  name varchar(255) NOT NULL,
+  applyTime text NOT NULL
+)`
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.sql$`)
+
+//LoadMigrations 读取目录下的迁移脚本，按版本号排序
+func LoadMigrations(dir string) ([]*Migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]*Migration, 0, len(files))
+	for _, f := range files {
+		m := migrationFile.FindStringSubmatch(f.Name())
+		if f.IsDir() || m == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(m[1])
+		migrations = append(migrations, &Migration{Version: version, Name: m[2], Path: filepath.Join(dir, f.Name())})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %s: expect version %d", m.Path, i+1)
+		}
+	}
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("no migration in %s", dir)
+	}
+	return migrations, nil
+}
+
+//GetMigrationStatus 获取当前方言的表结构版本状态
+func GetMigrationStatus() (*MigrationStatus, error) {
+	status, _, err := getMigrationStatus(defaultDialect.MigrationDir())
+	return status, err
+}
+
+//Migrate 执行待执行的迁移，返回执行的迁移，dryRun为true时只返回不执行；数据库版本高于迁移脚本时返回ErrorSchemaTooNew
+func Migrate(dryRun bool) ([]*Migration, error) {
+	return migrate(defaultDialect.MigrationDir(), dryRun)
+}
+
+func getMigrationStatus(dir string) (*MigrationStatus, []*Migration, error) {
+	if dir == "" {
+		return nil, nil, fmt.Errorf("%s: %w", defaultDialect.Name(), ErrorMigrationUnsupported)
+	}
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	status := &MigrationStatus{Latest: migrations[len(migrations)-1].Version}
+	db := GetConnection()
+	if err := db.QueryRow("SELECT IFNULL(MAX(version),0) FROM goku_schema_version;").Scan(&status.Current); err != nil {
+		// 版本表不存在时，已有管理员表说明是旧版本创建的数据库
+		count := 0
+		if db.QueryRow("SELECT COUNT(*) FROM goku_admin;").Scan(&count) == nil {
+			status.Current, status.Baseline = 1, true
+		}
+	}
+	for _, m := range migrations {
+		if m.Version > status.Current {
+			status.Pending = append(status.Pending, m)
+		}
+	}
+	return status, migrations, nil
+}
+
+func migrate(dir string, dryRun bool) ([]*Migration, error) {
+	status, migrations, err := getMigrationStatus(dir)
+	if err != nil {
+		return nil, err
+	}
+	if status.Current > status.Latest {
+		return nil, ErrorSchemaTooNew
+	}
+	if dryRun {
+		return status.Pending, nil
+	}
+	db := GetConnection()
+	if _, err := db.Exec(createSchemaVersion); err != nil {
+		return nil, err
+	}
+	if status.Baseline {
+		if _, err := db.Exec("INSERT INTO goku_schema_version (version,name,applyTime) VALUES (?,?,?);", migrations[0].Version, migrations[0].Name, time.Now().Format("2006-01-02 15:04:05")); err != nil {
+			return nil, err
+		}
+	}
+	for i, m := range status.Pending {
+		if err := applyMigration(m); err != nil {
+			return status.Pending[:i], err
+		}
+	}
+	return status.Pending, nil
+}
+
+//applyMigration 在同一事务中执行迁移脚本并记录版本
+func applyMigration(m *Migration) error {
+	content, err := ioutil.ReadFile(m.Path)
+	if err != nil {
+		return err
+	}
+	tx, err := GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	for _, sql := range splitStatements(string(content)) {
+		if _, err := tx.Exec(sql); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d_%s: %s\n%s", m.Version, m.Name, err, strings.TrimSpace(sql))
+		}
+	}
+	_, err = tx.Exec("INSERT INTO goku_schema_version (version,name,applyTime) VALUES (?,?,?);", m.Version, m.Name, time.Now().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+//splitStatements 按分号拆分语句，跳过只有注释的片段
+func splitStatements(content string) []string {
+	sqls := make([]string, 0)
+	for _, sql := range strings.Split(content, ";") {
+		for _, line := range strings.Split(sql, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "--") {
+				sqls = append(sqls, sql)
+				break
+			}
+		}
+	}
+	return sqls
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testConfig string
+
+func (c testConfig) GetDriver() string {
+	return "sqlite3"
+}
+
+func (c testConfig) GetSource() string {
+	return string(c)
+}
+
+func writeMigrations(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goku-migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := InitConnection(testConfig(filepath.Join(dir, "goku.db"))); err != nil {
+		t.Fatal(err)
+	}
+	migrations := filepath.Join(dir, "migrations")
+	os.Mkdir(migrations, 0755)
+	writeMigrations(t, migrations, map[string]string{
+		"0001_init.sql": "-- 管理员\nCREATE TABLE goku_admin (userID integer NOT NULL PRIMARY KEY);\n-- 空语句\n;",
+		"0002_user.sql": "ALTER TABLE goku_admin ADD COLUMN remark text;",
+		"README.md":     "ignored",
+	})
+
+	pending, err := migrate(migrations, true)
+	if err != nil || len(pending) != 2 {
+		t.Fatal(pending, err)
+	}
+	applied, err := migrate(migrations, false)
+	if err != nil || len(applied) != 2 || applied[1].Name != "user" {
+		t.Fatal(applied, err)
+	}
+	if applied, err := migrate(migrations, false); err != nil || len(applied) != 0 {
+		t.Fatal(applied, err)
+	}
+
+	// 失败的迁移整体回滚，不记录版本
+	writeMigrations(t, migrations, map[string]string{"0003_broken.sql": "ALTER TABLE goku_admin ADD COLUMN source text;\nINSERT INTO goku_missing VALUES (1);"})
+	if _, err := migrate(migrations, false); err == nil {
+		t.Fatal("expect migration error")
+	}
+	status, _, err := getMigrationStatus(migrations)
+	if err != nil || status.Current != 2 || len(status.Pending) != 1 {
+		t.Fatal(status, err)
+	}
+	if _, err := GetConnection().Exec("SELECT source FROM goku_admin;"); err == nil {
+		t.Error("failed migration is not rolled back")
+	}
+
+	// 数据库版本高于迁移脚本时拒绝执行
+	os.Remove(filepath.Join(migrations, "0003_broken.sql"))
+	GetConnection().Exec("INSERT INTO goku_schema_version (version,name,applyTime) VALUES (9,'future','');")
+	if _, err := migrate(migrations, false); err != ErrorSchemaTooNew {
+		t.Errorf("expect ErrorSchemaTooNew, got %v", err)
+	}
+}
+
+func TestMigrateBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goku-migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := InitConnection(testConfig(filepath.Join(dir, "goku.db"))); err != nil {
+		t.Fatal(err)
+	}
+	writeMigrations(t, dir, map[string]string{
+		"0001_init.sql": "CREATE TABLE goku_admin (userID integer NOT NULL PRIMARY KEY);",
+		"0002_user.sql": "ALTER TABLE goku_admin ADD COLUMN remark text;",
+	})
+	// 未记录版本的旧数据库只执行第一个版本之后的迁移
+	GetConnection().Exec("CREATE TABLE goku_admin (userID integer NOT NULL PRIMARY KEY);")
+	status, _, err := getMigrationStatus(dir)
+	if err != nil || !status.Baseline || status.Current != 1 {
+		t.Fatal(status, err)
+	}
+	applied, err := migrate(dir, false)
+	if err != nil || len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatal(applied, err)
+	}
+	if status, _, _ := getMigrationStatus(dir); status.Baseline || status.Current != 2 {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestMigrateUnsupported(t *testing.T) {
+	if _, err := migrate(nativeDialect("mysql").MigrationDir(), true); !errors.Is(err, ErrorMigrationUnsupported) {
+		t.Errorf("expect mysql migration to be refused, got %v", err)
+	}
+}
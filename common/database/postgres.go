@@ -0,0 +1,269 @@
+package database
+
+import (
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+
+	//postgres数据库驱动
+	"github.com/lib/pq"
+)
+
+func init() {
+	sql.Register(postgresDialect{}.Driver(), &rewriteDriver{driver: &pq.Driver{}, dialect: postgresDialect{}})
+}
+
+//postgresSerialKeys 各表的自增主键，INSERT时通过RETURNING取回作为LastInsertId；新增自增主键的表需在此登记
+var postgresSerialKeys = map[string]string{
+	"goku_admin":                    "userid",
+	"goku_alert_history":            "alertid",
+	"goku_alert_rule":               "ruleid",
+	"goku_api_token":                "tokenid",
+	"goku_audit_log":                "logid",
+	"goku_balance":                  "balanceid",
+	"goku_cluster":                  "id",
+	"goku_config_log":               "id",
+	"goku_conn_plugin_api":          "connid",
+	"goku_conn_plugin_strategy":     "connid",
+	"goku_conn_strategy_api":        "connid",
+	"goku_gateway_alert":            "alertid",
+	"goku_gateway_api":              "apiid",
+	"goku_gateway_api_group":        "groupid",
+	"goku_gateway_permission_group": "groupid",
+	"goku_gateway_project":          "projectid",
+	"goku_gateway_strategy_group":   "groupid",
+	"goku_gateway_version_config":   "versionid",
+	"goku_gateway_version_publish":  "id",
+	"goku_gateway_version_rollout":  "id",
+	"goku_monitor_cluster":          "recordid",
+	"goku_monitor_plugin":           "recordid",
+	"goku_node_group":               "groupid",
+	"goku_node_info":                "nodeid",
+	"goku_plugin":                   "pluginid",
+	"goku_service_config":           "id",
+	"goku_service_discovery":        "id",
+	"goku_table_update_record":      "tableid",
+}
+
+//postgresConflictKeys REPLACE INTO转换为ON CONFLICT时各表的唯一键；新增使用REPLACE INTO的表需在此登记
+var postgresConflictKeys = map[string]string{
+	"goku_config_log":        "name",
+	"goku_node_apply_status": "nodeid",
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return "postgres"
+}
+
+func (postgresDialect) Driver() string {
+	return "goku-postgres"
+}
+
+func (postgresDialect) MigrationDir() string {
+	return "sql/migrations/postgres"
+}
+
+//SerialKey 表的自增主键
+func (postgresDialect) SerialKey(table string) (string, bool) {
+	key, has := postgresSerialKeys[table]
+	return key, has
+}
+
+//Rewrite 转换为PostgreSQL语法：
+// 双引号字符串改为单引号，反引号标识符改为小写双引号标识符，占位符?改为$n，
+// IFNULL改为COALESCE，IF改为CASE WHEN，GROUP_CONCAT改为string_agg，
+// REPLACE INTO改为INSERT ... ON CONFLICT，以postgresConflictKeys中登记的唯一键作为冲突目标。
+// 建表等DDL语句由方言对应的SQL文件提供，不做转换
+func (postgresDialect) Rewrite(query string) string {
+	if isDDL(query) {
+		return query
+	}
+	query = normalizeQuery(query)
+	query = rewriteFunc(query, "IFNULL", func(args []string) string {
+		return "COALESCE(" + strings.Join(args, ",") + ")"
+	})
+	query = rewriteFunc(query, "IF", func(args []string) string {
+		if len(args) != 3 {
+			return ""
+		}
+		return "CASE WHEN " + args[0] + " THEN " + args[1] + " ELSE " + args[2] + " END"
+	})
+	query = rewriteFunc(query, "GROUP_CONCAT", func(args []string) string {
+		expr, distinct := strings.TrimSpace(args[0]), ""
+		if len(expr) > 9 && strings.EqualFold(expr[:9], "DISTINCT ") {
+			expr, distinct = strings.TrimSpace(expr[9:]), "DISTINCT "
+		}
+		separator := "','"
+		if len(args) > 1 {
+			separator = args[1]
+		}
+		return "string_agg(" + distinct + "CAST(" + expr + " AS text)," + separator + ")"
+	})
+	return rewriteReplace(query)
+}
+
+var ddl = regexp.MustCompile(`(?is)^(\s*--[^\n]*\n)*\s*(CREATE|DROP|ALTER)\s`)
+
+func isDDL(query string) bool {
+	return ddl.MatchString(query)
+}
+
+//normalizeQuery 统一引号并为占位符编号，单引号字符串保持不变
+func normalizeQuery(query string) string {
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; c {
+		case '\'':
+			end := closeQuote(query, i)
+			if end == len(query) {
+				return b.String() + query[i:]
+			}
+			b.WriteString(query[i : end+1])
+			i = end
+		case '"':
+			end := closeQuote(query, i)
+			s := strings.Replace(query[i+1:end], `""`, `"`, -1)
+			b.WriteString("'" + strings.Replace(s, "'", "''", -1) + "'")
+			i = end
+		case '`':
+			end := strings.IndexByte(query[i+1:], '`')
+			if end < 0 {
+				return b.String() + query[i+1:]
+			}
+			// 未加引号的标识符会被转为小写，带引号时同样使用小写以兼容保留字
+			b.WriteString(`"` + strings.ToLower(query[i+1:i+1+end]) + `"`)
+			i += end + 1
+		case '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+//closeQuote 返回与query[start]配对的引号位置，两个连续引号视为转义，未闭合时返回len(query)
+func closeQuote(query string, start int) int {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(query)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+//rewriteFunc 替换函数调用，参数按顶层逗号拆分并递归替换，replace返回空字符串时保持原样
+func rewriteFunc(query, name string, replace func(args []string) string) string {
+	var b strings.Builder
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if c == '\'' {
+			end := closeQuote(query, i)
+			if end == len(query) {
+				return b.String() + query[i:]
+			}
+			b.WriteString(query[i : end+1])
+			i = end
+			continue
+		}
+		if (i > 0 && isWordByte(query[i-1])) || i+len(name) > len(query) || !strings.EqualFold(query[i:i+len(name)], name) {
+			b.WriteByte(c)
+			continue
+		}
+		open := i + len(name)
+		for open < len(query) && query[open] == ' ' {
+			open++
+		}
+		if open >= len(query) || query[open] != '(' || (i+len(name) < len(query) && isWordByte(query[i+len(name)])) {
+			b.WriteByte(c)
+			continue
+		}
+		args, end := splitArgs(query, open)
+		if end < 0 {
+			b.WriteByte(c)
+			continue
+		}
+		for j := range args {
+			args[j] = rewriteFunc(args[j], name, replace)
+		}
+		s := replace(args)
+		if s == "" {
+			s = query[i:open+1] + strings.Join(args, ",") + ")"
+		}
+		b.WriteString(s)
+		i = end
+	}
+	return b.String()
+}
+
+//splitArgs 拆分query[open]开始的括号内参数，返回参数及右括号位置，括号未闭合时位置为-1
+func splitArgs(query string, open int) ([]string, int) {
+	args := make([]string, 0, 3)
+	depth, start := 0, open+1
+	for i := open; i < len(query); i++ {
+		switch query[i] {
+		case '\'':
+			i = closeQuote(query, i)
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return append(args, query[start:i]), i
+			}
+		case ',':
+			if depth == 1 {
+				args = append(args, query[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return nil, -1
+}
+
+var replaceInto = regexp.MustCompile(`(?is)^\s*REPLACE\s+INTO\s+("?\w+"?)\s*\(([^)]*)\)(.*?)[;\s]*$`)
+
+//rewriteReplace 未登记唯一键的表保持原样，由数据库报错
+func rewriteReplace(query string) string {
+	m := replaceInto.FindStringSubmatch(query)
+	if m == nil {
+		return query
+	}
+	key, has := postgresConflictKeys[unquote(m[1])]
+	if !has {
+		return query
+	}
+	columns := strings.Split(m[2], ",")
+	update := make([]string, 0, len(columns))
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+		if unquote(columns[i]) != key {
+			update = append(update, columns[i]+" = EXCLUDED."+columns[i])
+		}
+	}
+	action := "DO NOTHING"
+	if len(update) > 0 {
+		action = "DO UPDATE SET " + strings.Join(update, ",")
+	}
+	return "INSERT INTO " + m[1] + " (" + strings.Join(columns, ",") + ")" + m[3] + ` ON CONFLICT ("` + key + `") ` + action + ";"
+}
+
+//unquote 去掉标识符的双引号并转为小写
+func unquote(name string) string {
+	return strings.ToLower(strings.Trim(name, `"`))
+}
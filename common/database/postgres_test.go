@@ -0,0 +1,141 @@
+package database
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestPostgresRewrite(t *testing.T) {
+	cases := []struct {
+		query  string
+		expect string
+	}{
+		{
+			`SELECT IFNULL(remark,"") FROM goku_admin WHERE userID = ? AND loginCall = ?;`,
+			`SELECT COALESCE(remark,'') FROM goku_admin WHERE userID = $1 AND loginCall = $2;`,
+		},
+		{
+			"SELECT `name`,`default` FROM `goku_service_config` WHERE `driver`='static?' AND a = ?",
+			`SELECT "name","default" FROM "goku_service_config" WHERE "driver"='static?' AND a = $1`,
+		},
+		{
+			`SELECT IF(remark IS NULL OR remark = "",loginCall,IF(a=1,"it's",remark)) FROM t`,
+			`SELECT CASE WHEN remark IS NULL OR remark = '' THEN loginCall ELSE CASE WHEN a=1 THEN 'it''s' ELSE remark END END FROM t`,
+		},
+		{
+			`SELECT GROUP_CONCAT(DISTINCT groupID) AS groupID, IFNULL(x, 'IF(') FROM g`,
+			`SELECT string_agg(DISTINCT CAST(groupID AS text),',') AS groupID, COALESCE(x, 'IF(') FROM g`,
+		},
+		{
+			"REPLACE INTO goku_node_apply_status (`nodeID`,`version`) VALUES (?,IFNULL((SELECT `version` FROM goku_node_apply_status WHERE `nodeID` = ?),''));",
+			`INSERT INTO goku_node_apply_status ("nodeid","version") VALUES ($1,COALESCE((SELECT "version" FROM goku_node_apply_status WHERE "nodeid" = $2),'')) ON CONFLICT ("nodeid") DO UPDATE SET "version" = EXCLUDED."version";`,
+		},
+		{
+			"REPLACE INTO `goku_config_log`(`name`,`enable`)VALUES(?,?);",
+			`INSERT INTO "goku_config_log" ("name","enable")VALUES($1,$2) ON CONFLICT ("name") DO UPDATE SET "enable" = EXCLUDED."enable";`,
+		},
+		{
+			"REPLACE INTO unknown (`a`) VALUES (?);",
+			`REPLACE INTO unknown ("a") VALUES ($1);`,
+		},
+		{
+			"-- comment\nCREATE TABLE t (\n  \"default\" integer\n)",
+			"-- comment\nCREATE TABLE t (\n  \"default\" integer\n)",
+		},
+		{
+			`SELECT IFNULL(a,"`,
+			`SELECT IFNULL(a,''`,
+		},
+	}
+	d := postgresDialect{}
+	for _, c := range cases {
+		if q := d.Rewrite(c.query); q != c.expect {
+			t.Errorf("rewrite %q\n got %q\nwant %q", c.query, q, c.expect)
+		}
+	}
+}
+
+func TestPostgresSerialKeys(t *testing.T) {
+	for query, expect := range map[string]string{
+		`INSERT INTO goku_table_update_record (name) VALUES ($1);`: "goku_table_update_record",
+		`INSERT INTO "goku_admin" ("logincall") VALUES ($1)`:       "goku_admin",
+		`INSERT INTO goku_admin (a) VALUES ($1) RETURNING userid`:  "",
+		`UPDATE goku_admin SET a = $1`:                             "",
+	} {
+		if table := insertTable(query); table != expect {
+			t.Errorf("insert table of %q: got %q, want %q", query, table, expect)
+		}
+	}
+
+	// 迁移脚本中的自增主键与登记的一致
+	serials, _ := postgresSchema(t)
+	for table, key := range serials {
+		if k, has := (postgresDialect{}).SerialKey(table); !has || k != key {
+			t.Errorf("serial key of %s should be %s, got %q", table, key, k)
+		}
+	}
+	for table, key := range postgresSerialKeys {
+		if serials[table] != key {
+			t.Errorf("serial key %s.%s not found in migrations", table, key)
+		}
+	}
+}
+
+func TestPostgresConflictKeys(t *testing.T) {
+	_, uniques := postgresSchema(t)
+	for table, key := range postgresConflictKeys {
+		if !uniques[table][key] {
+			t.Errorf("conflict key %s.%s is not a unique key in migrations", table, key)
+		}
+	}
+}
+
+//postgresSchema 解析迁移脚本，返回各表的自增主键及唯一键（多列唯一键以逗号连接）
+func postgresSchema(t *testing.T) (map[string]string, map[string]map[string]bool) {
+	files, _ := filepath.Glob("../../build/console/resources/sql/migrations/postgres/*.sql")
+	if len(files) == 0 {
+		t.Fatal("no postgres migration")
+	}
+	table := regexp.MustCompile(`(?s)CREATE TABLE\s+"?(\w+)"?\s*\((.*?)\n\);`)
+	serial := regexp.MustCompile(`(?m)^\s*"?(\w+)"?\s+serial\b`)
+	primary := regexp.MustCompile(`(?m)^\s*"?(\w+)"?\s+\w+[^,\n]*\bPRIMARY KEY\b`)
+	primaryKeys := regexp.MustCompile(`PRIMARY KEY\s*\(([^)]*)\)`)
+	index := regexp.MustCompile(`(?s)CREATE UNIQUE INDEX\s+"?\w+"?\s+ON\s+"?(\w+)"?\s*\(([^)]*)\)`)
+
+	serials := make(map[string]string)
+	uniques := make(map[string]map[string]bool)
+	unique := func(table, columns string) {
+		names := strings.Split(columns, ",")
+		for i, name := range names {
+			names[i] = strings.ToLower(strings.Trim(strings.TrimSpace(name), `"`))
+		}
+		if uniques[table] == nil {
+			uniques[table] = make(map[string]bool)
+		}
+		uniques[table][strings.Join(names, ",")] = true
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range table.FindAllStringSubmatch(string(data), -1) {
+			if s := serial.FindStringSubmatch(m[2]); s != nil {
+				serials[m[1]] = strings.ToLower(s[1])
+			}
+			for _, p := range primary.FindAllStringSubmatch(m[2], -1) {
+				unique(m[1], p[1])
+			}
+			for _, p := range primaryKeys.FindAllStringSubmatch(m[2], -1) {
+				unique(m[1], p[1])
+			}
+		}
+		for _, m := range index.FindAllStringSubmatch(string(data), -1) {
+			unique(m[1], m[2])
+		}
+	}
+	return serials, uniques
+}
@@ -0,0 +1,205 @@
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// BER标签类型
+const (
+	classUniversal   = 0x00
+	classApplication = 0x40
+	classContext     = 0x80
+
+	constructed = 0x20
+
+	tagBoolean     = 0x01
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagEnumerated  = 0x0a
+	tagSequence    = 0x10 | constructed
+	tagSet         = 0x11 | constructed
+
+	maxPacketSize = 16 << 20
+)
+
+var errorPacket = errors.New("ldap: malformed ber packet")
+
+//Packet BER编码的数据单元，仅支持单字节标签
+type Packet struct {
+	Tag      byte
+	Value    []byte
+	Children []*Packet
+}
+
+func (p *Packet) isConstructed() bool {
+	return p.Tag&constructed != 0
+}
+
+//NewPacket 创建简单类型数据单元
+func NewPacket(tag byte, value []byte) *Packet {
+	return &Packet{Tag: tag, Value: value}
+}
+
+//NewConstructed 创建结构类型数据单元
+func NewConstructed(tag byte, children ...*Packet) *Packet {
+	return &Packet{Tag: tag | constructed, Children: children}
+}
+
+//NewString 创建OCTET STRING
+func NewString(tag byte, s string) *Packet {
+	return NewPacket(tag, []byte(s))
+}
+
+//NewInteger 创建INTEGER或ENUMERATED
+func NewInteger(tag byte, v int64) *Packet {
+	b := make([]byte, 0, 8)
+	for {
+		b = append([]byte{byte(v)}, b...)
+		v >>= 8
+		if (v == 0 && b[0]&0x80 == 0) || (v == -1 && b[0]&0x80 != 0) {
+			break
+		}
+	}
+	return NewPacket(tag, b)
+}
+
+//NewBoolean 创建BOOLEAN
+func NewBoolean(v bool) *Packet {
+	if v {
+		return NewPacket(tagBoolean, []byte{0xff})
+	}
+	return NewPacket(tagBoolean, []byte{0x00})
+}
+
+//Append 追加子单元
+func (p *Packet) Append(children ...*Packet) *Packet {
+	p.Children = append(p.Children, children...)
+	return p
+}
+
+//Int 读取整数值
+func (p *Packet) Int() int64 {
+	var v int64
+	for i, b := range p.Value {
+		if i == 0 && b&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int64(b)
+	}
+	return v
+}
+
+//String 读取字符串值
+func (p *Packet) String() string {
+	return string(p.Value)
+}
+
+//Child 获取第i个子单元，不存在时返回空单元
+func (p *Packet) Child(i int) *Packet {
+	if i < 0 || i >= len(p.Children) {
+		return &Packet{}
+	}
+	return p.Children[i]
+}
+
+//Bytes 编码
+func (p *Packet) Bytes() []byte {
+	value := p.Value
+	if p.isConstructed() {
+		value = nil
+		for _, c := range p.Children {
+			value = append(value, c.Bytes()...)
+		}
+	}
+	b := []byte{p.Tag}
+	b = append(b, encodeLength(len(value))...)
+	return append(b, value...)
+}
+
+func encodeLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+	var b []byte
+	for ; n > 0; n >>= 8 {
+		b = append([]byte{byte(n)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+//ReadPacket 从流中读取一个数据单元
+func ReadPacket(r *bufio.Reader) (*Packet, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if tag&0x1f == 0x1f {
+		return nil, errorPacket
+	}
+	first, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	length := int(first)
+	if first&0x80 != 0 {
+		n := int(first & 0x7f)
+		if n == 0 || n > 4 {
+			return nil, errorPacket
+		}
+		length = 0
+		for i := 0; i < n; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxPacketSize {
+		return nil, errorPacket
+	}
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return nil, err
+	}
+	return parsePacket(tag, value)
+}
+
+func parsePacket(tag byte, value []byte) (*Packet, error) {
+	p := &Packet{Tag: tag}
+	if tag&constructed == 0 {
+		p.Value = value
+		return p, nil
+	}
+	for len(value) > 0 {
+		if len(value) < 2 {
+			return nil, errorPacket
+		}
+		childTag := value[0]
+		length := int(value[1])
+		offset := 2
+		if value[1]&0x80 != 0 {
+			n := int(value[1] & 0x7f)
+			if n == 0 || n > 4 || len(value) < 2+n {
+				return nil, errorPacket
+			}
+			length = 0
+			for _, b := range value[2 : 2+n] {
+				length = length<<8 | int(b)
+			}
+			offset += n
+		}
+		if length < 0 || len(value) < offset+length {
+			return nil, errorPacket
+		}
+		child, err := parsePacket(childTag, value[offset:offset+length])
+		if err != nil {
+			return nil, err
+		}
+		p.Children = append(p.Children, child)
+		value = value[offset+length:]
+	}
+	return p, nil
+}
@@ -0,0 +1,166 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// 过滤器标签，RFC 4511 4.5.1
+const (
+	FilterAnd            = classContext | constructed | 0
+	FilterOr             = classContext | constructed | 1
+	FilterNot            = classContext | constructed | 2
+	FilterEqualityMatch  = classContext | constructed | 3
+	FilterSubstrings     = classContext | constructed | 4
+	FilterGreaterOrEqual = classContext | constructed | 5
+	FilterLessOrEqual    = classContext | constructed | 6
+	FilterPresent        = classContext | 7
+	FilterApproxMatch    = classContext | constructed | 8
+
+	SubstringInitial = classContext | 0
+	SubstringAny     = classContext | 1
+	SubstringFinal   = classContext | 2
+)
+
+//EscapeFilter 转义过滤器中的值，RFC 4515
+func EscapeFilter(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '*', '(', ')', '\\', 0:
+			fmt.Fprintf(&b, "\\%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+//CompileFilter 将字符串形式的过滤器编码为BER
+func CompileFilter(filter string) (*Packet, error) {
+	p, rest, err := compileFilter(strings.TrimSpace(filter))
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("ldap: unexpected %q in filter", rest)
+	}
+	return p, nil
+}
+
+func compileFilter(s string) (*Packet, string, error) {
+	if !strings.HasPrefix(s, "(") {
+		return nil, "", fmt.Errorf("ldap: filter must start with '(': %q", s)
+	}
+	s = s[1:]
+	if s == "" {
+		return nil, "", fmt.Errorf("ldap: unexpected end of filter")
+	}
+	switch s[0] {
+	case '&', '|':
+		tag := byte(FilterAnd)
+		if s[0] == '|' {
+			tag = FilterOr
+		}
+		p := &Packet{Tag: tag}
+		s = s[1:]
+		for strings.HasPrefix(s, "(") {
+			child, rest, err := compileFilter(s)
+			if err != nil {
+				return nil, "", err
+			}
+			p.Append(child)
+			s = rest
+		}
+		if !strings.HasPrefix(s, ")") {
+			return nil, "", fmt.Errorf("ldap: unclosed filter")
+		}
+		return p, s[1:], nil
+	case '!':
+		child, rest, err := compileFilter(s[1:])
+		if err != nil {
+			return nil, "", err
+		}
+		if !strings.HasPrefix(rest, ")") {
+			return nil, "", fmt.Errorf("ldap: unclosed filter")
+		}
+		return NewConstructed(FilterNot, child), rest[1:], nil
+	}
+	end := strings.IndexByte(s, ')')
+	if end < 0 {
+		return nil, "", fmt.Errorf("ldap: unclosed filter")
+	}
+	p, err := compileItem(s[:end])
+	return p, s[end+1:], err
+}
+
+func compileItem(item string) (*Packet, error) {
+	i := strings.IndexByte(item, '=')
+	if i <= 0 {
+		return nil, fmt.Errorf("ldap: illegal filter item %q", item)
+	}
+	attr, value := item[:i], item[i+1:]
+	tag := byte(FilterEqualityMatch)
+	switch attr[len(attr)-1] {
+	case '>':
+		tag, attr = FilterGreaterOrEqual, attr[:len(attr)-1]
+	case '<':
+		tag, attr = FilterLessOrEqual, attr[:len(attr)-1]
+	case '~':
+		tag, attr = FilterApproxMatch, attr[:len(attr)-1]
+	}
+	if tag == FilterEqualityMatch && value == "*" {
+		return NewString(FilterPresent, attr), nil
+	}
+	if tag == FilterEqualityMatch && strings.Contains(value, "*") {
+		parts := strings.Split(value, "*")
+		subs := NewConstructed(tagSequence)
+		for j, part := range parts {
+			if part == "" {
+				continue
+			}
+			v, err := unescapeFilter(part)
+			if err != nil {
+				return nil, err
+			}
+			subTag := byte(SubstringAny)
+			if j == 0 {
+				subTag = SubstringInitial
+			} else if j == len(parts)-1 {
+				subTag = SubstringFinal
+			}
+			subs.Append(NewString(subTag, v))
+		}
+		return NewConstructed(FilterSubstrings, NewString(tagOctetString, attr), subs), nil
+	}
+	v, err := unescapeFilter(value)
+	if err != nil {
+		return nil, err
+	}
+	return NewConstructed(tag, NewString(tagOctetString, attr), NewString(tagOctetString, v)), nil
+}
+
+func unescapeFilter(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+3 > len(s) {
+			return "", fmt.Errorf("ldap: illegal escape in %q", s)
+		}
+		c, err := hex.DecodeString(s[i+1 : i+3])
+		if err != nil {
+			return "", fmt.Errorf("ldap: illegal escape in %q", s)
+		}
+		b.Write(c)
+		i += 2
+	}
+	return b.String(), nil
+}
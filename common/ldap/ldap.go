@@ -0,0 +1,240 @@
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LDAP协议操作标签，RFC 4511 4.2
+const (
+	opBindRequest       = classApplication | constructed | 0
+	opBindResponse      = classApplication | constructed | 1
+	opUnbindRequest     = classApplication | 2
+	opSearchRequest     = classApplication | constructed | 3
+	opSearchResultEntry = classApplication | constructed | 4
+	opSearchResultDone  = classApplication | constructed | 5
+	opSearchResultRef   = classApplication | constructed | 19
+
+	authSimple = classContext | 0
+)
+
+// 搜索范围
+const (
+	ScopeBaseObject   = 0
+	ScopeSingleLevel  = 1
+	ScopeWholeSubtree = 2
+)
+
+// 常用结果码
+const (
+	ResultSuccess            = 0
+	ResultNoSuchObject       = 32
+	ResultInvalidCredentials = 49
+)
+
+var errorEmptyPassword = errors.New("ldap: empty password is not allowed")
+
+//Error LDAP操作失败
+type Error struct {
+	ResultCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("ldap: result code %d: %s", e.ResultCode, e.Message)
+}
+
+//IsInvalidCredentials 判断是否为账号或密码错误
+func IsInvalidCredentials(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.ResultCode == ResultInvalidCredentials
+}
+
+//Entry 搜索结果条目，属性名统一为小写
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+//Get 获取属性的第一个值
+func (e *Entry) Get(name string) string {
+	if v := e.Attributes[strings.ToLower(name)]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+//Values 获取属性的全部值
+func (e *Entry) Values(name string) []string {
+	return e.Attributes[strings.ToLower(name)]
+}
+
+//SearchRequest 搜索请求
+type SearchRequest struct {
+	BaseDN     string
+	Scope      int
+	Filter     string
+	Attributes []string
+	SizeLimit  int
+}
+
+//Conn LDAP连接，不支持并发使用
+type Conn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	messageID int64
+	timeout   time.Duration
+}
+
+//Dial 连接LDAP服务，地址格式为 ldap://host:389 或 ldaps://host:636
+func Dial(rawURL string, timeout time.Duration, tlsConfig *tls.Config) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		host := u.Host
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "389")
+		}
+		conn, err = dialer.Dial("tcp", host)
+	case "ldaps":
+		host := u.Host
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "636")
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: u.Hostname()}
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, tlsConfig)
+	default:
+		return nil, fmt.Errorf("ldap: unsupported scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn: conn, reader: bufio.NewReader(conn), timeout: timeout}, nil
+}
+
+func (c *Conn) send(op *Packet) (int64, error) {
+	c.messageID++
+	msg := NewConstructed(tagSequence, NewInteger(tagInteger, c.messageID), op)
+	if c.timeout > 0 {
+		c.conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+	_, err := c.conn.Write(msg.Bytes())
+	return c.messageID, err
+}
+
+func (c *Conn) receive(id int64) (*Packet, error) {
+	for {
+		msg, err := ReadPacket(c.reader)
+		if err != nil {
+			return nil, err
+		}
+		if len(msg.Children) < 2 {
+			return nil, errorPacket
+		}
+		if msg.Child(0).Int() == id {
+			return msg.Child(1), nil
+		}
+	}
+}
+
+func checkResult(op *Packet) error {
+	code := int(op.Child(0).Int())
+	if code != ResultSuccess {
+		return &Error{ResultCode: code, Message: op.Child(2).String()}
+	}
+	return nil
+}
+
+//Bind 简单认证，不允许空密码以避免被当作匿名绑定
+func (c *Conn) Bind(dn, password string) error {
+	if dn != "" && password == "" {
+		return errorEmptyPassword
+	}
+	id, err := c.send(NewConstructed(opBindRequest,
+		NewInteger(tagInteger, 3),
+		NewString(tagOctetString, dn),
+		NewString(authSimple, password),
+	))
+	if err != nil {
+		return err
+	}
+	op, err := c.receive(id)
+	if err != nil {
+		return err
+	}
+	if op.Tag != opBindResponse {
+		return errorPacket
+	}
+	return checkResult(op)
+}
+
+//Search 搜索条目
+func (c *Conn) Search(req *SearchRequest) ([]*Entry, error) {
+	filter, err := CompileFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	attributes := NewConstructed(tagSequence)
+	for _, attr := range req.Attributes {
+		attributes.Append(NewString(tagOctetString, attr))
+	}
+	id, err := c.send(NewConstructed(opSearchRequest,
+		NewString(tagOctetString, req.BaseDN),
+		NewInteger(tagEnumerated, int64(req.Scope)),
+		NewInteger(tagEnumerated, 0),
+		NewInteger(tagInteger, int64(req.SizeLimit)),
+		NewInteger(tagInteger, int64(c.timeout/time.Second)),
+		NewBoolean(false),
+		filter,
+		attributes,
+	))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*Entry, 0)
+	for {
+		op, err := c.receive(id)
+		if err != nil {
+			return nil, err
+		}
+		switch op.Tag {
+		case opSearchResultEntry:
+			entries = append(entries, readEntry(op))
+		case opSearchResultRef:
+		case opSearchResultDone:
+			return entries, checkResult(op)
+		default:
+			return nil, errorPacket
+		}
+	}
+}
+
+func readEntry(op *Packet) *Entry {
+	e := &Entry{DN: op.Child(0).String(), Attributes: make(map[string][]string)}
+	for _, attr := range op.Child(1).Children {
+		name := strings.ToLower(attr.Child(0).String())
+		for _, v := range attr.Child(1).Children {
+			e.Attributes[name] = append(e.Attributes[name], v.String())
+		}
+	}
+	return e
+}
+
+//Close 发送unbind并关闭连接
+func (c *Conn) Close() error {
+	c.send(NewPacket(opUnbindRequest, nil))
+	return c.conn.Close()
+}
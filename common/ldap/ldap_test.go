@@ -0,0 +1,59 @@
+package ldap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/ldap"
+	"github.com/eolinker/goku-api-gateway/common/ldap/ldaptest"
+)
+
+func TestCompileFilter(t *testing.T) {
+	for _, f := range []string{"(uid=a)", "(&(objectClass=person)(|(uid=a*)(mail=*@x.com)))", "(!(cn=*))", "(cn=a\\2ab)"} {
+		if _, err := ldap.CompileFilter(f); err != nil {
+			t.Errorf("%s: %v", f, err)
+		}
+	}
+	for _, f := range []string{"uid=a", "(uid=a", "(&(uid=a)", "(cn=\\zz)"} {
+		if _, err := ldap.CompileFilter(f); err == nil {
+			t.Errorf("%s: expect error", f)
+		}
+	}
+	if ldap.EscapeFilter("a*(b)\\") != "a\\2a\\28b\\29\\5c" {
+		t.Error(ldap.EscapeFilter("a*(b)\\"))
+	}
+}
+
+func TestBindAndSearch(t *testing.T) {
+	s := ldaptest.NewServer()
+	defer s.Close()
+	s.AddEntry("uid=alice,ou=people,dc=example,dc=com", "secret", map[string][]string{"objectClass": {"person"}, "uid": {"alice"}, "mail": {"alice@example.com"}})
+	s.AddEntry("uid=bob,ou=people,dc=example,dc=com", "pass", map[string][]string{"objectClass": {"person"}, "uid": {"bob"}})
+
+	conn, err := ldap.Dial(s.URL, time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.Bind("uid=alice,ou=people,dc=example,dc=com", "wrong"); !ldap.IsInvalidCredentials(err) {
+		t.Errorf("expect invalid credentials, got %v", err)
+	}
+	if err := conn.Bind("uid=alice,ou=people,dc=example,dc=com", ""); err == nil {
+		t.Error("empty password should be rejected")
+	}
+	if err := conn.Bind("uid=alice,ou=people,dc=example,dc=com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     "dc=example,dc=com",
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     "(&(objectClass=person)(uid=" + ldap.EscapeFilter("alice") + "))",
+		Attributes: []string{"mail"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].DN != "uid=alice,ou=people,dc=example,dc=com" || entries[0].Get("MAIL") != "alice@example.com" || entries[0].Get("uid") != "" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+}
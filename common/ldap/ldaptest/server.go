@@ -0,0 +1,235 @@
+//Package ldaptest 提供用于测试的内存LDAP服务，仅支持简单认证及搜索
+package ldaptest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/common/ldap"
+)
+
+const (
+	opBindRequest    = 0x60
+	opBindResponse   = 0x61
+	opUnbindRequest  = 0x42
+	opSearchRequest  = 0x63
+	opSearchEntry    = 0x64
+	opSearchDone     = 0x65
+	tagOctetString   = 0x04
+	tagEnumerated    = 0x0a
+	tagSequence      = 0x30
+	tagSet           = 0x31
+	resultOperations = 1
+)
+
+//Server 内存LDAP服务
+type Server struct {
+	URL      string
+	listener net.Listener
+
+	mu        sync.Mutex
+	entries   map[string]*entry
+	passwords map[string]string
+	wg        sync.WaitGroup
+}
+
+type entry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+//NewServer 启动监听本地随机端口的LDAP服务
+func NewServer() *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := &Server{
+		URL:       "ldap://" + l.Addr().String(),
+		listener:  l,
+		entries:   make(map[string]*entry),
+		passwords: make(map[string]string),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+//AddEntry 新增条目，password非空时可用该条目绑定
+func (s *Server) AddEntry(dn, password string, attributes map[string][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attrs := make(map[string][]string)
+	for k, v := range attributes {
+		attrs[strings.ToLower(k)] = v
+	}
+	s.entries[strings.ToLower(dn)] = &entry{dn: dn, attributes: attrs}
+	if password != "" {
+		s.passwords[strings.ToLower(dn)] = password
+	}
+}
+
+//Close 关闭服务
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		msg, err := ldap.ReadPacket(reader)
+		if err != nil {
+			return
+		}
+		id := msg.Child(0)
+		op := msg.Child(1)
+		switch op.Tag {
+		case opBindRequest:
+			code := s.bind(op.Child(1).String(), op.Child(2).String())
+			conn.Write(response(id, opBindResponse, code).Bytes())
+		case opSearchRequest:
+			for _, e := range s.search(op) {
+				conn.Write(ldap.NewConstructed(tagSequence, id, e).Bytes())
+			}
+			conn.Write(response(id, opSearchDone, ldap.ResultSuccess).Bytes())
+		case opUnbindRequest:
+			return
+		default:
+			conn.Write(response(id, opBindResponse, resultOperations).Bytes())
+		}
+	}
+}
+
+func response(id *ldap.Packet, op byte, code int) *ldap.Packet {
+	return ldap.NewConstructed(tagSequence, id, ldap.NewConstructed(op,
+		ldap.NewInteger(tagEnumerated, int64(code)),
+		ldap.NewString(tagOctetString, ""),
+		ldap.NewString(tagOctetString, ""),
+	))
+}
+
+func (s *Server) bind(dn, password string) int {
+	if dn == "" {
+		return ldap.ResultSuccess
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.passwords[strings.ToLower(dn)]; ok && p == password {
+		return ldap.ResultSuccess
+	}
+	return ldap.ResultInvalidCredentials
+}
+
+func (s *Server) search(op *ldap.Packet) []*ldap.Packet {
+	base := strings.ToLower(op.Child(0).String())
+	scope := op.Child(1).Int()
+	filter := op.Child(6)
+	wanted := make(map[string]bool)
+	for _, a := range op.Child(7).Children {
+		wanted[strings.ToLower(a.String())] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]*ldap.Packet, 0)
+	for dn, e := range s.entries {
+		switch scope {
+		case ldap.ScopeBaseObject:
+			if dn != base {
+				continue
+			}
+		default:
+			if dn != base && !strings.HasSuffix(dn, ","+base) {
+				continue
+			}
+		}
+		if !match(filter, e.attributes) {
+			continue
+		}
+		list := ldap.NewConstructed(tagSequence)
+		for name, values := range e.attributes {
+			if len(wanted) > 0 && !wanted[name] {
+				continue
+			}
+			set := ldap.NewConstructed(tagSet)
+			for _, v := range values {
+				set.Append(ldap.NewString(tagOctetString, v))
+			}
+			list.Append(ldap.NewConstructed(tagSequence, ldap.NewString(tagOctetString, name), set))
+		}
+		result = append(result, ldap.NewConstructed(opSearchEntry, ldap.NewString(tagOctetString, e.dn), list))
+	}
+	return result
+}
+
+func match(f *ldap.Packet, attrs map[string][]string) bool {
+	switch f.Tag {
+	case ldap.FilterAnd:
+		for _, c := range f.Children {
+			if !match(c, attrs) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, c := range f.Children {
+			if match(c, attrs) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !match(f.Child(0), attrs)
+	case ldap.FilterPresent:
+		return len(attrs[strings.ToLower(f.String())]) > 0
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch:
+		for _, v := range attrs[strings.ToLower(f.Child(0).String())] {
+			if strings.EqualFold(v, f.Child(1).String()) {
+				return true
+			}
+		}
+	case ldap.FilterSubstrings:
+		for _, v := range attrs[strings.ToLower(f.Child(0).String())] {
+			if matchSubstrings(strings.ToLower(v), f.Child(1).Children) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchSubstrings(v string, subs []*ldap.Packet) bool {
+	for _, sub := range subs {
+		s := strings.ToLower(sub.String())
+		switch sub.Tag {
+		case ldap.SubstringInitial:
+			if !strings.HasPrefix(v, s) {
+				return false
+			}
+			v = v[len(s):]
+		case ldap.SubstringFinal:
+			return strings.HasSuffix(v, s)
+		default:
+			i := strings.Index(v, s)
+			if i < 0 {
+				return false
+			}
+			v = v[i+len(s):]
+		}
+	}
+	return true
+}
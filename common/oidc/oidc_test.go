@@ -0,0 +1,84 @@
+package oidc_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/oidc"
+	"github.com/eolinker/goku-api-gateway/common/oidc/oidctest"
+)
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	s := oidctest.NewServer("console", "secret")
+	defer s.Close()
+	s.SetClaims(map[string]interface{}{"preferred_username": "alice", "groups": []string{"ops", "dev"}})
+
+	p, err := oidc.NewProvider(oidc.Config{
+		Issuer:       s.URL,
+		ClientID:     "console",
+		ClientSecret: "secret",
+		RedirectURL:  "http://console.local/guest/oidc/callback",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(p.AuthCodeURL("st", "n1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || location.Query().Get("state") != "st" {
+		t.Fatalf("unexpected redirect %q", resp.Header.Get("Location"))
+	}
+	code := location.Query().Get("code")
+
+	if _, err := p.Exchange(code, "other"); err == nil {
+		t.Error("expect nonce mismatch")
+	}
+	resp, _ = client.Get(p.AuthCodeURL("st", "n1"))
+	resp.Body.Close()
+	location, _ = url.Parse(resp.Header.Get("Location"))
+	claims, err := p.Exchange(location.Query().Get("code"), "n1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.String("preferred_username") != "alice" || len(claims.Strings("groups")) != 2 {
+		t.Errorf("unexpected claims %v", claims)
+	}
+	if _, err := p.Exchange(location.Query().Get("code"), "n1"); err == nil {
+		t.Error("expect code to be used only once")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	s := oidctest.NewServer("console", "secret")
+	defer s.Close()
+	p, err := oidc.NewProvider(oidc.Config{Issuer: s.URL, ClientID: "console"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Unix()
+	valid := map[string]interface{}{"iss": s.URL, "aud": "console", "exp": now + 60, "nonce": "n"}
+	if _, err := p.Verify(s.Sign(valid), "n"); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]map[string]interface{}{
+		"issuer":   {"iss": "http://evil", "aud": "console", "exp": now + 60, "nonce": "n"},
+		"audience": {"iss": s.URL, "aud": []string{"other"}, "exp": now + 60, "nonce": "n"},
+		"expired":  {"iss": s.URL, "aud": "console", "exp": now - 3600, "nonce": "n"},
+		"nonce":    {"iss": s.URL, "aud": "console", "exp": now + 60, "nonce": "x"},
+	}
+	for name, claims := range cases {
+		if _, err := p.Verify(s.Sign(claims), "n"); err == nil {
+			t.Errorf("%s: expect error", name)
+		}
+	}
+	token := s.Sign(valid)
+	if _, err := p.Verify(token[:len(token)-4]+"AAAA", "n"); err == nil {
+		t.Error("expect signature error")
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	//CanaryMatchHeader 按请求头匹配
+	CanaryMatchHeader = "header"
+	//CanaryMatchCookie 按cookie匹配
+	CanaryMatchCookie = "cookie"
+	//CanaryMatchQuery 按query参数匹配
+	CanaryMatchQuery = "query"
+	//CanaryMatchIP 按客户端IP匹配（仅用于粘性会话）
+	CanaryMatchIP = "ip"
+)
+
+var (
+	errorCanaryTarget = errors.New("canary targets must not be empty")
+	errorCanaryWeight = errors.New("canary weights must be greater than zero in total")
+	errorCanaryRule   = errors.New("illegal canary rule")
+	errorCanarySticky = errors.New("illegal canary sticky config")
+)
+
+//CanaryConfig 灰度发布配置，按权重或规则将流量分配到不同负载
+type CanaryConfig struct {
+	Targets []*CanaryTarget `json:"targets"`
+	Rules   []*CanaryRule   `json:"rules,omitempty"`
+	Sticky  *CanarySticky   `json:"sticky,omitempty"`
+}
+
+//CanaryTarget 灰度目标负载及权重
+type CanaryTarget struct {
+	Balance string `json:"balance"`
+	Weight  int    `json:"weight"`
+}
+
+//CanaryRule 灰度匹配规则，命中时直接转发到指定负载
+type CanaryRule struct {
+	Type    string `json:"type"` // header | cookie | query
+	Name    string `json:"name"`
+	Value   string `json:"value"` // 为空时只要求存在
+	Balance string `json:"balance"`
+}
+
+//CanarySticky 粘性会话配置，同一客户端标识固定命中同一负载
+type CanarySticky struct {
+	Type string `json:"type"` // header | cookie | query | ip
+	Name string `json:"name"`
+}
+
+//Check 检查灰度配置
+func (c *CanaryConfig) Check() error {
+	if len(c.Targets) == 0 {
+		return errorCanaryTarget
+	}
+	total := 0
+	for _, t := range c.Targets {
+		if t == nil || t.Balance == "" || t.Weight < 0 {
+			return errorCanaryTarget
+		}
+		total += t.Weight
+	}
+	if total <= 0 {
+		return errorCanaryWeight
+	}
+	for _, r := range c.Rules {
+		if r == nil || r.Name == "" || r.Balance == "" {
+			return errorCanaryRule
+		}
+		switch strings.ToLower(r.Type) {
+		case CanaryMatchHeader, CanaryMatchCookie, CanaryMatchQuery:
+		default:
+			return errorCanaryRule
+		}
+	}
+	if c.Sticky != nil {
+		switch strings.ToLower(c.Sticky.Type) {
+		case CanaryMatchIP:
+		case CanaryMatchHeader, CanaryMatchCookie, CanaryMatchQuery:
+			if c.Sticky.Name == "" {
+				return errorCanarySticky
+			}
+		default:
+			return errorCanarySticky
+		}
+	}
+	return nil
+}
@@ -12,6 +12,8 @@ type GokuConfig struct {
 	Strategy            []*StrategyConfig          `json:"strategy,omitempty"`
 	AnonymousStrategyID string                     `json:"anonymousStrategyID,omitempty"`
 	AuthPlugin          map[string]string          `json:"authPlugin,omitempty"`
+	TrustedProxies      []string                   `json:"trustedProxies,omitempty"` // 可信代理IP/CIDR，仅信任来自这些地址的X-Forwarded-For/X-Real-Ip
+	Monitor             *MonitorConfig             `json:"monitor,omitempty"`
 
 	Log       *LogConfig       `json:"log,omitempty"`
 	AccessLog *AccessLogConfig `json:"access_log,omitempty"`
@@ -69,12 +71,28 @@ type BalanceConfig struct {
 	Config       string `json:"config"` // appName(for discovery) or  address (for static)
 }
 
+const (
+	//PluginFailContinue 插件执行失败时继续处理请求
+	PluginFailContinue = "continue"
+	//PluginFailStop 插件执行失败时以FailStatus中断请求
+	PluginFailStop = "stop"
+	//PluginFailFallback 插件执行失败时以FailStatus、FailBody作为响应中断请求
+	PluginFailFallback = "fallback"
+)
+
 //PluginConfig 插件配置
 type PluginConfig struct {
 	Name      string `json:"name"`
 	IsStop    bool   `json:"stop"`
 	Config    string `json:"config"`
 	UpdateTag string `json:"updateTag"`
+	//Timeout 插件执行超时时间，单位毫秒，0表示不限制；进程外插件及Wasm插件超时后中断调用，本地插件超时后放弃等待
+	Timeout int `json:"timeout,omitempty"`
+	//FailPolicy 插件panic、超时或返回错误时的处理方式：continue、stop、fallback，为空时插件返回错误保持插件的结果，panic、超时按continue处理
+	FailPolicy string `json:"failPolicy,omitempty"`
+	//FailStatus、FailBody 中断请求时返回的状态码及内容
+	FailStatus int    `json:"failStatus,omitempty"`
+	FailBody   string `json:"failBody,omitempty"`
 }
 
 //APIContent api详情
@@ -90,6 +108,11 @@ type APIContent struct {
 
 	StaticResponseStrategy string `json:"static_respone_strategy"`
 	StaticResponse         string `json:"staticResponse"`
+
+	CORS      *CORSConfig      `json:"cors,omitempty"`      // 接口跨域配置，优先于策略配置
+	IPAccess  *IPAccessConfig  `json:"ipAccess,omitempty"`  // 接口IP黑白名单，在策略名单之后校验
+	Transform *TransformConfig `json:"transform,omitempty"` // 转发请求头部/参数及响应头部转换规则
+	Mock      *MockConfig      `json:"mock,omitempty"`      // mock配置，开启时不执行链路
 }
 
 //APIStepConfig 链路配置
@@ -162,12 +185,14 @@ type ActionConfig struct {
 
 //StrategyConfig 策略配置
 type StrategyConfig struct {
-	ID      string            `json:"id"`
-	Name    string            `json:"name"`
-	Enable  bool              `json:"enable"`
-	APIS    []*APIOfStrategy  `json:"apis"`
-	AUTH    map[string]string `json:"auth"`
-	Plugins []*PluginConfig   `json:"plugins"`
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Enable   bool              `json:"enable"`
+	APIS     []*APIOfStrategy  `json:"apis"`
+	AUTH     map[string]string `json:"auth"`
+	Plugins  []*PluginConfig   `json:"plugins"`
+	CORS     *CORSConfig       `json:"cors,omitempty"`
+	IPAccess *IPAccessConfig   `json:"ipAccess,omitempty"`
 }
 
 //APIOfStrategy 策略接口配置
@@ -175,6 +200,7 @@ type APIOfStrategy struct {
 	ID      int             `json:"id"`
 	Balance string          `json:"balance"` // 单step有效
 	Plugins []*PluginConfig `json:"plugins"`
+	Canary  *CanaryConfig   `json:"canary,omitempty"` // 单step有效，优先于Balance
 }
 
 //VersionConfig 版本配置
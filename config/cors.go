@@ -0,0 +1,38 @@
+package config
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	errorCORSOrigin = errors.New("cors allow origins must not be empty")
+	errorCORSMaxAge = errors.New("cors max age must not be negative")
+)
+
+//CORSConfig 跨域配置
+type CORSConfig struct {
+	AllowOrigins     []string `json:"allowOrigins"` // * 表示全部，支持 https://*.example.com 形式的子域名通配
+	AllowMethods     []string `json:"allowMethods,omitempty"`
+	AllowHeaders     []string `json:"allowHeaders,omitempty"` // 为空时回显预检请求的 Access-Control-Request-Headers
+	ExposeHeaders    []string `json:"exposeHeaders,omitempty"`
+	AllowCredentials bool     `json:"allowCredentials"`
+	MaxAge           int      `json:"maxAge"` // 秒
+}
+
+//Check 检查跨域配置
+func (c *CORSConfig) Check() error {
+	origins := 0
+	for _, o := range c.AllowOrigins {
+		if strings.TrimSpace(o) != "" {
+			origins++
+		}
+	}
+	if origins == 0 {
+		return errorCORSOrigin
+	}
+	if c.MaxAge < 0 {
+		return errorCORSMaxAge
+	}
+	return nil
+}
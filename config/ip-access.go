@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+//IPAccessConfig IP黑白名单配置，支持IPv4/IPv6地址及CIDR
+type IPAccessConfig struct {
+	Allow []string `json:"allow,omitempty"` // 非空时只允许名单内的IP访问
+	Deny  []string `json:"deny,omitempty"`  // 优先于Allow
+}
+
+//Check 检查IP黑白名单配置
+func (c *IPAccessConfig) Check() error {
+	if _, err := ParseIPNets(c.Allow); err != nil {
+		return err
+	}
+	if _, err := ParseIPNets(c.Deny); err != nil {
+		return err
+	}
+	return nil
+}
+
+//ParseIPNet 解析IP或CIDR，单个IP按/32（IPv6为/128）处理
+func ParseIPNet(v string) (*net.IPNet, error) {
+	v = strings.TrimSpace(v)
+	if strings.Contains(v, "/") {
+		_, ipNet, err := net.ParseCIDR(v)
+		if err != nil {
+			return nil, fmt.Errorf("illegal cidr:%s", v)
+		}
+		return ipNet, nil
+	}
+	ip := net.ParseIP(v)
+	if ip == nil {
+		return nil, fmt.Errorf("illegal ip:%s", v)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+//ParseIPNets 批量解析IP或CIDR，忽略空项
+func ParseIPNets(vs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(vs))
+	for _, v := range vs {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		n, err := ParseIPNet(v)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
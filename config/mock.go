@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	//MockMatchHeader 按头部匹配
+	MockMatchHeader = "header"
+	//MockMatchQuery 按query参数匹配
+	MockMatchQuery = "query"
+
+	//MockMaxDelay 最大模拟延时，毫秒
+	MockMaxDelay = 60000
+)
+
+var (
+	errorMockDelay = fmt.Errorf("mock delay must be between 0 and %d", MockMaxDelay)
+	errorMockMatch = errors.New("mock example match must not be empty")
+)
+
+//MockConfig 接口mock配置，开启后不再转发到后端
+type MockConfig struct {
+	Enable   bool           `json:"enable"`
+	Response MockResponse   `json:"response"` // 未命中示例时的默认响应
+	Examples []*MockExample `json:"examples,omitempty"`
+}
+
+//MockResponse mock响应
+type MockResponse struct {
+	StatusCode int               `json:"statusCode"` // 为0时返回200
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body"`  // 支持 {{query.x}}、{{header.X}}、{{body.x}}、{{restful.x}}、{{context.apiID}} 等变量
+	Delay      int               `json:"delay"` // 模拟延时，毫秒
+}
+
+//MockExample mock示例，按顺序匹配，所有条件均满足时命中
+type MockExample struct {
+	Name     string       `json:"name"`
+	Match    []*MockMatch `json:"match"`
+	Response MockResponse `json:"response"`
+}
+
+//MockMatch mock示例匹配条件
+type MockMatch struct {
+	Type  string `json:"type"` // header | query
+	Name  string `json:"name"`
+	Value string `json:"value"` // 为空时只要存在即可
+}
+
+//Check 检查mock配置
+func (c *MockConfig) Check() error {
+	if err := c.Response.check(); err != nil {
+		return err
+	}
+	for i, e := range c.Examples {
+		if e == nil || len(e.Match) == 0 {
+			return errorMockMatch
+		}
+		for _, m := range e.Match {
+			if m == nil || strings.TrimSpace(m.Name) == "" {
+				return fmt.Errorf("examples[%d]:match name must not be empty", i)
+			}
+			switch strings.ToLower(m.Type) {
+			case MockMatchHeader, MockMatchQuery:
+			default:
+				return fmt.Errorf("examples[%d]:illegal match type:%s", i, m.Type)
+			}
+		}
+		if err := e.Response.check(); err != nil {
+			return fmt.Errorf("examples[%d]:%s", i, err.Error())
+		}
+	}
+	return nil
+}
+
+func (r *MockResponse) check() error {
+	if r.StatusCode != 0 && (r.StatusCode < 100 || r.StatusCode > 599) {
+		return fmt.Errorf("illegal mock status code:%d", r.StatusCode)
+	}
+	if r.Delay < 0 || r.Delay > MockMaxDelay {
+		return errorMockDelay
+	}
+	return nil
+}
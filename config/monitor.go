@@ -0,0 +1,47 @@
+package config
+
+//MonitorConfig 监控配置
+type MonitorConfig struct {
+	Period      int   `json:"period"`      // 上报周期，单位秒
+	SuccessCode []int `json:"successCode"` // 视为请求成功的状态码
+}
+
+//MonitorReport 节点上报的监控数据
+type MonitorReport struct {
+	Items   []*MonitorItem       `json:"items"`
+	Plugins []*PluginMonitorItem `json:"plugins,omitempty"`
+}
+
+//MonitorItem 策略、接口在一小时内的请求计数
+type MonitorItem struct {
+	StrategyID string `json:"strategyID"`
+	APIID      int    `json:"apiID"`
+	Hour       int64  `json:"hour"` // 小时起始时间的unix时间戳
+
+	GatewayRequestCount   int64 `json:"gatewayRequestCount"`
+	GatewaySuccessCount   int64 `json:"gatewaySuccessCount"`
+	GatewayStatus2xxCount int64 `json:"gatewayStatus2xxCount"`
+	GatewayStatus4xxCount int64 `json:"gatewayStatus4xxCount"`
+	GatewayStatus5xxCount int64 `json:"gatewayStatus5xxCount"`
+	ProxyRequestCount     int64 `json:"proxyRequestCount"`
+	ProxySuccessCount     int64 `json:"proxySuccessCount"`
+	ProxyStatus2xxCount   int64 `json:"proxyStatus2xxCount"`
+	ProxyStatus4xxCount   int64 `json:"proxyStatus4xxCount"`
+	ProxyStatus5xxCount   int64 `json:"proxyStatus5xxCount"`
+	ProxyTimeoutCount     int64 `json:"proxyTimeoutCount"`
+	RequestTime           int64 `json:"requestTime"`    // 总耗时，单位毫秒
+	MaxRequestTime        int64 `json:"maxRequestTime"` // 最大耗时，单位毫秒
+}
+
+//PluginMonitorItem 插件在一小时内的执行计数
+type PluginMonitorItem struct {
+	PluginName string `json:"pluginName"`
+	Hour       int64  `json:"hour"` // 小时起始时间的unix时间戳
+
+	CallCount    int64 `json:"callCount"`
+	ErrorCount   int64 `json:"errorCount"`   // 插件返回错误的次数
+	PanicCount   int64 `json:"panicCount"`   // 插件panic的次数
+	TimeoutCount int64 `json:"timeoutCount"` // 插件执行超时的次数
+	CallTime     int64 `json:"callTime"`     // 总耗时，单位微秒
+	MaxCallTime  int64 `json:"maxCallTime"`  // 最大耗时，单位微秒
+}
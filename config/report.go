@@ -0,0 +1,23 @@
+package config
+
+const (
+	//ApplyOk 配置应用成功
+	ApplyOk = "applied"
+	//ApplyFailed 配置应用失败
+	ApplyFailed = "failed"
+)
+
+//ApplyReport 节点上报的配置应用结果
+type ApplyReport struct {
+	Version string              `json:"version"`
+	Status  string              `json:"status"` // applied | failed
+	Error   string              `json:"error,omitempty"`
+	Plugins []*PluginLoadStatus `json:"plugins,omitempty"`
+}
+
+//PluginLoadStatus 插件加载状态
+type PluginLoadStatus struct {
+	Name  string `json:"name"`
+	Code  int    `json:"code"` // 0 成功，其余见plugin_loader的错误码
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+//SignatureHeader 控制台下发配置的签名头部
+const SignatureHeader = "X-Goku-Signature"
+
+//Sign 使用节点凭证计算配置的HMAC-SHA256签名，凭证仅允许经TLS传输，否则签名无法防篡改
+func Sign(secret string, data []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+//CheckSign 校验配置签名
+func CheckSign(secret string, data []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, data)), []byte(signature))
+}
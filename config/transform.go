@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	//TransformAdd 追加
+	TransformAdd = "add"
+	//TransformSet 设置，存在时覆盖
+	TransformSet = "set"
+	//TransformRemove 删除
+	TransformRemove = "remove"
+	//TransformRename 重命名
+	TransformRename = "rename"
+)
+
+//TransformConfig 请求/响应转换配置
+type TransformConfig struct {
+	RequestHeaders  []*TransformRule `json:"requestHeaders,omitempty"`
+	RequestQuery    []*TransformRule `json:"requestQuery,omitempty"`
+	ResponseHeaders []*TransformRule `json:"responseHeaders,omitempty"`
+}
+
+//TransformRule 转换规则
+type TransformRule struct {
+	Action string `json:"action"` // add | set | remove | rename
+	Name   string `json:"name"`
+	// add/set 时为值模板，支持 {{header.X}}、{{query.x}}、{{context.strategyID}} 等变量；rename 时为新名称
+	Value string `json:"value,omitempty"`
+}
+
+//Check 检查转换配置
+func (c *TransformConfig) Check() error {
+	if err := checkTransformRules("requestHeaders", c.RequestHeaders); err != nil {
+		return err
+	}
+	if err := checkTransformRules("requestQuery", c.RequestQuery); err != nil {
+		return err
+	}
+	return checkTransformRules("responseHeaders", c.ResponseHeaders)
+}
+
+func checkTransformRules(kind string, rules []*TransformRule) error {
+	for i, r := range rules {
+		if r == nil || strings.TrimSpace(r.Name) == "" {
+			return fmt.Errorf("%s[%d]:name must not be empty", kind, i)
+		}
+		switch strings.ToLower(r.Action) {
+		case TransformAdd, TransformSet, TransformRemove:
+		case TransformRename:
+			if strings.TrimSpace(r.Value) == "" {
+				return fmt.Errorf("%s[%d]:rename target must not be empty", kind, i)
+			}
+		default:
+			return fmt.Errorf("%s[%d]:illegal action:%s", kind, i, r.Action)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package admin
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/conf"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func requireAuth() bool {
+	v := conf.Value("admin_require_auth")
+	return v == "true" || v == "1"
+}
+
+func getCredential(r *http.Request) *node.Credential {
+	cred := new(node.Credential)
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		cred.Token = strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		// 客户端证书的CommonName为节点所属集群
+		cred.CertCluster = r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	return cred
+}
+
+// isSecure 请求经TLS传输，或由可信代理以https转发
+func isSecure(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	return err == nil && isTrustedProxy(host) && r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// authNode 认证请求的节点，失败时写入错误信息并返回false
+func authNode(httpResponse http.ResponseWriter, httpRequest *http.Request) (*entity.Node, string, bool) {
+	ip, port, err := GetIPPort(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse, "700000", "cluster", err.Error(), err)
+		return nil, "", false
+	}
+	cred := getCredential(httpRequest)
+	if cred.Token != "" && !isSecure(httpRequest) {
+		// 凭证同时是配置签名的密钥，明文传输时签名无法防篡改
+		httpResponse.WriteHeader(http.StatusForbidden)
+		controller.WriteError(httpResponse, "700006", "cluster", "[ERROR]Node token requires TLS:"+ip, node.ErrorNodeForbidden)
+		return nil, "", false
+	}
+	nodeInfo, secret, err := node.AuthenticateNode(ip, port, httpRequest.Form.Get("name"), cred, requireAuth())
+	switch err {
+	case nil:
+		return nodeInfo, secret, true
+	case node.ErrorNodeUnauthorized:
+		httpResponse.WriteHeader(http.StatusUnauthorized)
+		controller.WriteError(httpResponse, "700004", "cluster", "[ERROR]Node unauthorized:"+ip, err)
+	case node.ErrorNodeForbidden:
+		httpResponse.WriteHeader(http.StatusForbidden)
+		controller.WriteError(httpResponse, "700005", "cluster", "[ERROR]Node forbidden:"+ip, err)
+	default:
+		controller.WriteError(httpResponse, "700001", "cluster", err.Error()+ip, err)
+	}
+	return nil, "", false
+}
@@ -1,16 +1,21 @@
 package admin
 
 import (
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/conf"
 )
 
-//GetIPPort 获取客户端IP和端口
+//GetIPPort 获取节点IP和端口，仅当请求来自admin_trusted_proxies中的代理时使用X-Real-Ip
 func GetIPPort(r *http.Request) (string, int, error) {
-	ip := r.RemoteAddr
-	ip = ip[:strings.Index(ip, ":")]
-	if realIP := strings.TrimSpace(r.Header.Get("X-Real-Ip")); realIP != "" {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-Ip")); realIP != "" && isTrustedProxy(ip) {
 		ip = realIP
 	}
 	r.ParseForm()
@@ -21,3 +26,27 @@ func GetIPPort(r *http.Request) (string, int, error) {
 	}
 	return ip, port, nil
 }
+
+//isTrustedProxy 判断地址是否在admin_trusted_proxies（逗号分隔的IP或CIDR）中
+func isTrustedProxy(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, proxy := range strings.Split(conf.Value("admin_trusted_proxies"), ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if _, cidr, err := net.ParseCIDR(proxy); err == nil {
+			if cidr.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if p := net.ParseIP(proxy); p != nil && p.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
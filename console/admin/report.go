@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/monitor"
+	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
+)
+
+//ReportApplyStatus 节点上报配置应用结果
+func ReportApplyStatus(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	nodeInfo, _, ok := authNode(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	body, err := ioutil.ReadAll(httpRequest.Body)
+	if err != nil {
+		controller.WriteError(httpResponse, "700002", "cluster", "[ERROR]Illegal report", err)
+		return
+	}
+	report := new(config.ApplyReport)
+	if err = json.Unmarshal(body, report); err != nil {
+		controller.WriteError(httpResponse, "700002", "cluster", "[ERROR]Illegal report", err)
+		return
+	}
+	if err = versionConfig.ReportApplyStatus(nodeInfo, report); err != nil {
+		controller.WriteError(httpResponse, "700003", "cluster", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "cluster", "", nil)
+}
+
+//ReportMonitor 节点上报监控计数
+func ReportMonitor(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	nodeInfo, _, ok := authNode(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	report := new(config.MonitorReport)
+	if err := json.NewDecoder(httpRequest.Body).Decode(report); err != nil {
+		controller.WriteError(httpResponse, "700002", "cluster", "[ERROR]Illegal report", err)
+		return
+	}
+	if err := monitor.ReportMonitor(nodeInfo, report.Items, report.Plugins); err != nil {
+		controller.WriteError(httpResponse, "700003", "cluster", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "cluster", "", nil)
+}
@@ -7,5 +7,7 @@ func router() http.Handler {
 	serverHandler := http.NewServeMux()
 
 	serverHandler.HandleFunc("/version/config/get", GetVersionConfig)
+	serverHandler.HandleFunc("/version/config/report", ReportApplyStatus)
+	serverHandler.HandleFunc("/monitor/report", ReportMonitor)
 	return serverHandler
 }
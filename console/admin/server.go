@@ -1,8 +1,13 @@
 package admin
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
 	"net/http"
 
+	"github.com/eolinker/goku-api-gateway/common/conf"
 	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
 )
 
@@ -10,5 +15,37 @@ import (
 func StartServer(bind string) error {
 	handler := router()
 	versionConfig.InitVersionConfig()
-	return http.ListenAndServe(bind, handler)
+
+	cert, key := conf.Value("admin_tls_cert"), conf.Value("admin_tls_key")
+	if cert == "" || key == "" {
+		return http.ListenAndServe(bind, handler)
+	}
+	server := &http.Server{
+		Addr:    bind,
+		Handler: handler,
+	}
+	if ca := conf.Value("admin_client_ca"); ca != "" {
+		tlsConfig, err := clientCATLSConfig(ca)
+		if err != nil {
+			return err
+		}
+		server.TLSConfig = tlsConfig
+	}
+	return server.ListenAndServeTLS(cert, key)
+}
+
+// clientCATLSConfig 校验节点提供的客户端证书，证书的CommonName为节点所属集群
+func clientCATLSConfig(caFile string) (*tls.Config, error) {
+	data, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("illegal admin_client_ca:" + caFile)
+	}
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}, nil
 }
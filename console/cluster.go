@@ -34,6 +34,22 @@ func getDefaultDatabase() (*entity.ClusterDB, error) {
 				Path:   conf.MastValue("db_path", "./work/goku.db"),
 			}, nil
 		}
+	case postgresDriver:
+		{
+			dbPort, err := strconv.Atoi(conf.MastValue("db_port", "5432"))
+			if err != nil {
+				return nil, err
+			}
+			return &entity.ClusterDB{
+				Driver:   dbType,
+				Host:     conf.Value("db_host"),
+				Port:     dbPort,
+				UserName: conf.Value("db_user"),
+				Password: conf.Value("db_password"),
+				Database: conf.Value("db_name"),
+				SSLMode:  conf.Value("db_sslmode"),
+			}, nil
+		}
 	default:
 		{
 			return nil, errors.New("unsupported database type")
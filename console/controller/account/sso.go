@@ -0,0 +1,112 @@
+package account
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/account"
+)
+
+const oidcStateCookie = "oidcState"
+
+func setLoginCookie(httpResponse http.ResponseWriter, userID int, userToken string) {
+	userCookie := &http.Cookie{Name: "userToken", Value: userToken, Path: "/", MaxAge: 86400}
+	nameCookie := &http.Cookie{Name: "userID", Value: strconv.Itoa(userID), Path: "/", MaxAge: 86400}
+	http.SetCookie(httpResponse, userCookie)
+	http.SetCookie(httpResponse, nameCookie)
+}
+
+func writeSSOError(httpResponse http.ResponseWriter, err error) {
+	switch err {
+	case account.ErrorSSODisabled:
+		controller.WriteError(httpResponse, "100003", "guest", "[ERROR]Single sign-on is not enabled!", err)
+	case account.ErrorSSOCredentials:
+		controller.WriteError(httpResponse, "100000", "guest", "[ERROR]Wrong username or password!", err)
+	case account.ErrorSSOConflict, account.ErrorSSONoGroup:
+		controller.WriteError(httpResponse, "100004", "guest", "[ERROR]"+err.Error(), err)
+	default:
+		controller.WriteError(httpResponse, "100005", "guest", "[ERROR]Single sign-on failed!", err)
+	}
+}
+
+//LoginLDAP LDAP登录
+func LoginLDAP(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	loginCall := httpRequest.PostFormValue("loginCall")
+	loginPassword := httpRequest.PostFormValue("loginPassword")
+
+	userID, userToken, err := account.LoginLDAP(loginCall, loginPassword)
+	if err != nil {
+		writeSSOError(httpResponse, err)
+		return
+	}
+	setLoginCookie(httpResponse, userID, userToken)
+
+	controller.WriteResultInfo(httpResponse, "guest", "userID", userID)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+//LoginOIDC 跳转到OIDC认证服务
+func LoginOIDC(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	state, nonce := randomString(), randomString()
+	authURL, err := account.OIDCAuthURL(state, nonce)
+	if err != nil {
+		writeSSOError(httpResponse, err)
+		return
+	}
+	http.SetCookie(httpResponse, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state + "." + nonce,
+		Path:     "/guest/oidc",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   httpRequest.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(httpResponse, httpRequest, authURL, http.StatusFound)
+}
+
+//OIDCCallback OIDC认证回调，校验state后使用授权码登录
+func OIDCCallback(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	http.SetCookie(httpResponse, &http.Cookie{Name: oidcStateCookie, Path: "/guest/oidc", MaxAge: -1})
+	query := httpRequest.URL.Query()
+	if e := query.Get("error"); e != "" {
+		controller.WriteError(httpResponse, "100005", "guest", "[ERROR]"+e+" "+query.Get("error_description"), errors.New(e))
+		return
+	}
+	cookie, err := httpRequest.Cookie(oidcStateCookie)
+	if err != nil {
+		controller.WriteError(httpResponse, "100005", "guest", "[ERROR]Login session expired!", err)
+		return
+	}
+	values := strings.SplitN(cookie.Value, ".", 2)
+	if len(values) != 2 || subtle.ConstantTimeCompare([]byte(values[0]), []byte(query.Get("state"))) != 1 {
+		controller.WriteError(httpResponse, "100005", "guest", "[ERROR]Illegal state!", errors.New("illegal state"))
+		return
+	}
+	userID, userToken, err := account.LoginOIDC(query.Get("code"), values[1])
+	if err != nil {
+		writeSSOError(httpResponse, err)
+		return
+	}
+	setLoginCookie(httpResponse, userID, userToken)
+	http.Redirect(httpResponse, httpRequest, "/", http.StatusFound)
+}
+
+//GetSSOInfo 获取已启用的单点登录方式
+func GetSSOInfo(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	controller.WriteResultInfo(httpResponse, "guest", "ssoInfo", map[string]interface{}{
+		"ldap": account.LoadLDAPConfig() != nil,
+		"oidc": account.LoadOIDCConfig() != nil,
+	})
+}
@@ -0,0 +1,81 @@
+package account
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/account"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//AddAPIToken 新增接口访问令牌
+func AddAPIToken(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	token := &entity.APIToken{
+		TokenName:  httpRequest.Form.Get("tokenName"),
+		TokenType:  httpRequest.Form.Get("tokenType"),
+		UserID:     userID,
+		ExpireTime: httpRequest.Form.Get("expireTime"),
+	}
+	if token.TokenName == "" {
+		controller.WriteError(httpResponse, "130001", "user", "[ERROR]Illegal tokenName!", nil)
+		return
+	}
+	if token.TokenType == "" {
+		token.TokenType = entity.TokenPersonal
+	}
+	scopes := httpRequest.Form.Get("scopes")
+	if err := json.Unmarshal([]byte(scopes), &token.Scopes); err != nil {
+		// 兼容以逗号分隔的范围
+		token.Scopes = strings.Split(scopes, ",")
+	}
+	value, err := account.AddAPIToken(token, controller.OperationTypes)
+	if err != nil {
+		controller.WriteError(httpResponse, "130002", "user", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "user", "tokenInfo", map[string]interface{}{
+		"tokenID": token.TokenID,
+		"token":   value,
+	})
+}
+
+//GetAPITokenList 获取接口访问令牌列表
+func GetAPITokenList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	tokens, err := account.GetAPITokenList(userID)
+	if err != nil {
+		controller.WriteError(httpResponse, "130000", "user", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "user", "tokenList", tokens)
+}
+
+//DeleteAPIToken 删除接口访问令牌
+func DeleteAPIToken(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	tokenID, err := strconv.Atoi(httpRequest.PostFormValue("tokenID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "130003", "user", "[ERROR]Illegal tokenID!", err)
+		return
+	}
+	flag, err := account.DeleteAPIToken(tokenID, userID)
+	if !flag {
+		controller.WriteError(httpResponse, "130000", "user", "[ERROR]Token does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "user", "", nil)
+}
@@ -0,0 +1,223 @@
+package alert
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	module "github.com/eolinker/goku-api-gateway/console/module/alert"
+	"github.com/eolinker/goku-api-gateway/console/module/cluster"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//readRule 读取告警规则参数，cluster为空表示全部集群
+func readRule(httpResponse http.ResponseWriter, httpRequest *http.Request) (*entity.AlertRule, bool) {
+	rule := &entity.AlertRule{
+		RuleName:        httpRequest.PostFormValue("ruleName"),
+		RuleType:        httpRequest.PostFormValue("ruleType"),
+		StrategyID:      httpRequest.PostFormValue("strategyID"),
+		Receivers:       httpRequest.PostFormValue("receivers"),
+		WebhookURL:      httpRequest.PostFormValue("webhookURL"),
+		WebhookTemplate: httpRequest.PostFormValue("webhookTemplate"),
+		Enable:          httpRequest.PostFormValue("enable") != "false",
+	}
+	if rule.RuleName == "" {
+		controller.WriteError(httpResponse, "420001", "alert", "[ERROR]Illegal ruleName!", nil)
+		return nil, false
+	}
+	if name := httpRequest.PostFormValue("cluster"); name != "" {
+		rule.ClusterID = cluster.GetClusterIDByName(name)
+		if rule.ClusterID == 0 {
+			controller.WriteError(httpResponse, "420001", "alert", "[ERROR]Illegal cluster!", nil)
+			return nil, false
+		}
+	}
+	ints := []struct {
+		name  string
+		value *int
+	}{
+		{"apiID", &rule.APIID},
+		{"threshold", &rule.Threshold},
+		{"period", &rule.Period},
+		{"cooldown", &rule.Cooldown},
+	}
+	for _, i := range ints {
+		value := httpRequest.PostFormValue(i.name)
+		if value == "" {
+			continue
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			controller.WriteError(httpResponse, "420001", "alert", "[ERROR]Illegal "+i.name+"!", err)
+			return nil, false
+		}
+		*i.value = v
+	}
+	if err := module.CheckRule(rule); err != nil {
+		controller.WriteError(httpResponse, "420001", "alert", "[ERROR]"+err.Error(), err)
+		return nil, false
+	}
+	return rule, true
+}
+
+func readRuleID(httpResponse http.ResponseWriter, httpRequest *http.Request) (int, bool) {
+	ruleID, err := strconv.Atoi(httpRequest.FormValue("ruleID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "420003", "alert", "[ERROR]Illegal ruleID!", err)
+		return 0, false
+	}
+	return ruleID, true
+}
+
+//AddAlertRule 新增告警规则
+func AddAlertRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	rule, ok := readRule(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	ruleID, err := module.AddRule(rule)
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "ruleID", ruleID)
+}
+
+//EditAlertRule 编辑告警规则
+func EditAlertRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	ruleID, ok := readRuleID(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	rule, ok := readRule(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	rule.RuleID = ruleID
+	flag, err := module.EditRule(rule)
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", "[ERROR]"+err.Error(), err)
+		return
+	}
+	if !flag {
+		controller.WriteError(httpResponse, "420002", "alert", "[ERROR]The rule does not exist!", errors.New("rule does not exist"))
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "", nil)
+}
+
+//DeleteAlertRule 删除告警规则
+func DeleteAlertRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	ruleID, ok := readRuleID(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	if err := module.DeleteRule(ruleID); err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "", nil)
+}
+
+//GetAlertRuleInfo 获取告警规则
+func GetAlertRuleInfo(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	ruleID, ok := readRuleID(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	flag, rule, err := module.GetRule(ruleID)
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", "[ERROR]"+err.Error(), err)
+		return
+	}
+	if !flag {
+		controller.WriteError(httpResponse, "420002", "alert", "[ERROR]The rule does not exist!", errors.New("rule does not exist"))
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "ruleInfo", rule)
+}
+
+//GetAlertRuleList 获取告警规则列表
+func GetAlertRuleList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	result, err := module.GetRuleList()
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfoWithPage(httpResponse, "alert", "ruleList", result, controller.NewItemNum(len(result)))
+}
+
+//GetAlertHistoryList 获取告警记录列表
+func GetAlertHistoryList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	cond := &entity.AlertHistoryCondition{
+		RuleType:  httpRequest.Form.Get("ruleType"),
+		BeginTime: httpRequest.Form.Get("beginTime"),
+		EndTime:   httpRequest.Form.Get("endTime"),
+	}
+	if ruleID := httpRequest.Form.Get("ruleID"); ruleID != "" {
+		id, ok := readRuleID(httpResponse, httpRequest)
+		if !ok {
+			return
+		}
+		cond.RuleID = id
+	}
+	page, err := strconv.Atoi(httpRequest.Form.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(httpRequest.Form.Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 15
+	}
+	result, count, err := module.GetHistoryList(cond, page, pageSize)
+	if err != nil {
+		controller.WriteError(httpResponse, "420000", "alert", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfoWithPage(httpResponse, "alert", "historyList", result, &controller.PageInfo{
+		ItemNum:  len(result),
+		Page:     page,
+		PageSize: pageSize,
+		TotalNum: count,
+	})
+}
+
+//GetAlertConfig 获取网关告警配置，不返回邮箱密码
+func GetAlertConfig(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	result, err := module.GetSender()
+	if err != nil {
+		controller.WriteError(httpResponse, "320000", "gateway", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "gateway", "alertInfo", result)
+}
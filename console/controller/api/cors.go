@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+)
+
+//EditAPICORS 编辑接口跨域配置
+func EditAPICORS(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationAPI, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	apiID := httpRequest.PostFormValue("apiID")
+	cors := httpRequest.PostFormValue("cors")
+
+	aID, err := strconv.Atoi(apiID)
+	if err != nil {
+		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	var corsConfig *config.CORSConfig
+	if cors != "" {
+		corsConfig = new(config.CORSConfig)
+		err = json.Unmarshal([]byte(cors), corsConfig)
+		if err != nil {
+			controller.WriteError(httpResponse, "190020", "api", "[ERROR]Illegal cors!", err)
+			return
+		}
+	}
+	flag, err := api.CheckAPIIsExist(aID)
+	if !flag {
+		controller.WriteError(httpResponse, "190000", "api", "[ERROR]The api does not exist!", err)
+		return
+	}
+	flag, result, err := api.SetCORS(aID, corsConfig)
+	if !flag {
+		controller.WriteError(httpResponse, "190020", "api", result, err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "api", "", nil)
+}
+
+//GetAPICORS 获取接口跨域配置
+func GetAPICORS(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationAPI, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+
+	aID, err := strconv.Atoi(httpRequest.Form.Get("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	flag, result, err := api.GetCORS(aID)
+	if !flag {
+		controller.WriteError(httpResponse, "190000", "api", "[ERROR]The api does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "api", "cors", result)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+)
+
+//EditAPIIPAccess 编辑接口IP黑白名单
+func EditAPIIPAccess(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationAPI, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	apiID := httpRequest.PostFormValue("apiID")
+	ipAccess := httpRequest.PostFormValue("ipAccess")
+
+	aID, err := strconv.Atoi(apiID)
+	if err != nil {
+		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	var ipAccessConfig *config.IPAccessConfig
+	if ipAccess != "" {
+		ipAccessConfig = new(config.IPAccessConfig)
+		err = json.Unmarshal([]byte(ipAccess), ipAccessConfig)
+		if err != nil {
+			controller.WriteError(httpResponse, "190021", "api", "[ERROR]Illegal ip access!", err)
+			return
+		}
+	}
+	flag, err := api.CheckAPIIsExist(aID)
+	if !flag {
+		controller.WriteError(httpResponse, "190000", "api", "[ERROR]The api does not exist!", err)
+		return
+	}
+	flag, result, err := api.SetIPAccess(aID, ipAccessConfig)
+	if !flag {
+		controller.WriteError(httpResponse, "190021", "api", result, err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "api", "", nil)
+}
+
+//GetAPIIPAccess 获取接口IP黑白名单
+func GetAPIIPAccess(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationAPI, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+
+	aID, err := strconv.Atoi(httpRequest.Form.Get("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	flag, result, err := api.GetIPAccess(aID)
+	if !flag {
+		controller.WriteError(httpResponse, "190000", "api", "[ERROR]The api does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "api", "ipAccess", result)
+}
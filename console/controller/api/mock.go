@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+)
+
+//EditAPIMock 编辑接口mock配置
+func EditAPIMock(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationAPI, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	apiID := httpRequest.PostFormValue("apiID")
+	mock := httpRequest.PostFormValue("mock")
+
+	aID, err := strconv.Atoi(apiID)
+	if err != nil {
+		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	var mockConfig *config.MockConfig
+	if mock != "" {
+		mockConfig = new(config.MockConfig)
+		err = json.Unmarshal([]byte(mock), mockConfig)
+		if err != nil {
+			controller.WriteError(httpResponse, "190023", "api", "[ERROR]Illegal mock!", err)
+			return
+		}
+	}
+	flag, err := api.CheckAPIIsExist(aID)
+	if !flag {
+		controller.WriteError(httpResponse, "190000", "api", "[ERROR]The api does not exist!", err)
+		return
+	}
+	flag, result, err := api.SetMock(aID, mockConfig)
+	if !flag {
+		controller.WriteError(httpResponse, "190023", "api", result, err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "api", "", nil)
+}
+
+//GetAPIMock 获取接口mock配置
+func GetAPIMock(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationAPI, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+
+	aID, err := strconv.Atoi(httpRequest.Form.Get("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	flag, result, err := api.GetMock(aID)
+	if !flag {
+		controller.WriteError(httpResponse, "190000", "api", "[ERROR]The api does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "api", "mock", result)
+}
+
+//SwitchAPIMock 开启或关闭接口mock
+func SwitchAPIMock(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationAPI, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	aID, err := strconv.Atoi(httpRequest.PostFormValue("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	enable := httpRequest.PostFormValue("enable")
+	if enable != "0" && enable != "1" {
+		controller.WriteError(httpResponse, "190024", "api", "[ERROR]Illegal enable!", nil)
+		return
+	}
+	flag, result, err := api.SwitchMock(aID, enable == "1")
+	if !flag {
+		controller.WriteError(httpResponse, "190023", "api", result, err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "api", "", nil)
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+)
+
+//ImportOpenAPI 导入OpenAPI/Swagger文档
+func ImportOpenAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationAPI, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	contentType := httpRequest.Header.Get("Content-Type")
+
+	if !strings.Contains(contentType, "multipart/form-data") {
+		controller.WriteError(httpResponse,
+			"310001",
+			"import",
+			"[ERROR]Request Content-Type isn't multipart/form-data",
+			nil)
+		return
+	}
+	pjID, err := strconv.Atoi(httpRequest.PostFormValue("projectID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310002",
+			"import",
+			"[ERROR]Illegal projectID!",
+			err)
+		return
+	}
+	option := &api.OpenAPIOption{
+		Balance:    httpRequest.PostFormValue("balanceName"),
+		TargetPath: httpRequest.PostFormValue("targetPath"),
+	}
+	if groupID := httpRequest.PostFormValue("groupID"); groupID != "" {
+		option.GroupID, err = strconv.Atoi(groupID)
+		if err != nil {
+			controller.WriteError(httpResponse,
+				"310008",
+				"import",
+				"[ERROR]Illegal groupID!",
+				err)
+			return
+		}
+	}
+	file, _, err := httpRequest.FormFile("file")
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310004",
+			"import",
+			"[ERROR]Param file does not exist!",
+			err)
+		return
+	}
+	defer file.Close()
+	body, err := ioutil.ReadAll(file)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310005",
+			"import",
+			"[ERROR]Fail to read file!",
+			err)
+		return
+	}
+	created, skipped, err := api.ImportOpenAPI(pjID, userID, body, option)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310000",
+			"import",
+			"[ERROR]"+err.Error(),
+			err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse, "import", "result", map[string]int{
+		"created": created,
+		"skipped": skipped,
+	})
+}
+
+//ExportOpenAPI 导出项目或策略的接口为OpenAPI文档
+func ExportOpenAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationAPI, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	strategyID := httpRequest.Form.Get("strategyID")
+	pjID, err := strconv.Atoi(httpRequest.Form.Get("projectID"))
+	if strategyID == "" && err != nil {
+		controller.WriteError(httpResponse,
+			"310002",
+			"export",
+			"[ERROR]Illegal projectID!",
+			err)
+		return
+	}
+	format := httpRequest.Form.Get("format")
+	data, err := api.ExportOpenAPI(pjID, strategyID, format)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"310009",
+			"export",
+			"[ERROR]Fail to export api!",
+			err)
+		return
+	}
+	if format == "yaml" {
+		httpResponse.Header().Set("Content-Type", "application/x-yaml")
+		httpResponse.Header().Set("Content-Disposition", "attachment; filename=openapi.yaml")
+	} else {
+		httpResponse.Header().Set("Content-Type", "application/json")
+		httpResponse.Header().Set("Content-Disposition", "attachment; filename=openapi.json")
+	}
+	httpResponse.Write(data)
+}
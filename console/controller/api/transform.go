@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+)
+
+//EditAPITransform 编辑接口转换规则
+func EditAPITransform(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationAPI, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	apiID := httpRequest.PostFormValue("apiID")
+	transform := httpRequest.PostFormValue("transform")
+
+	aID, err := strconv.Atoi(apiID)
+	if err != nil {
+		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	var transformConfig *config.TransformConfig
+	if transform != "" {
+		transformConfig = new(config.TransformConfig)
+		err = json.Unmarshal([]byte(transform), transformConfig)
+		if err != nil {
+			controller.WriteError(httpResponse, "190022", "api", "[ERROR]Illegal transform!", err)
+			return
+		}
+	}
+	flag, err := api.CheckAPIIsExist(aID)
+	if !flag {
+		controller.WriteError(httpResponse, "190000", "api", "[ERROR]The api does not exist!", err)
+		return
+	}
+	flag, result, err := api.SetTransform(aID, transformConfig)
+	if !flag {
+		controller.WriteError(httpResponse, "190022", "api", result, err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "api", "", nil)
+}
+
+//GetAPITransform 获取接口转换规则
+func GetAPITransform(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationAPI, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+
+	aID, err := strconv.Atoi(httpRequest.Form.Get("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "190001", "api", "[ERROR]Illegal apiID!", err)
+		return
+	}
+	flag, result, err := api.GetTransform(aID)
+	if !flag {
+		controller.WriteError(httpResponse, "190000", "api", "[ERROR]The api does not exist!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "api", "transform", result)
+}
@@ -0,0 +1,300 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+
+	"github.com/eolinker/goku-api-gateway/console/controller/rest"
+	"github.com/eolinker/goku-api-gateway/console/module/account"
+	module "github.com/eolinker/goku-api-gateway/console/module/audit"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	maxDataSize     = 64 << 10
+	maskValue       = "******"
+	truncatedSuffix = "...(truncated)"
+)
+
+// mutationActions 旧版接口路径中表示变更操作的段，另外以batch开头的段也视为变更
+var mutationActions = map[string]bool{
+	"add":                 true,
+	"edit":                true,
+	"editInfo":            true,
+	"save":                true,
+	"delete":              true,
+	"copy":                true,
+	"start":               true,
+	"stop":                true,
+	"switch":              true,
+	"reset":               true,
+	"target":              true,
+	"default":             true,
+	"import":              true,
+	"publish":             true,
+	"rollback":            true,
+	"promote":             true,
+	"abort":               true,
+	"addPluginToApi":      true,
+	"addPluginToStrategy": true,
+}
+
+// sensitiveKeys 字段名包含以下内容或以token结尾时不记录其值
+var sensitiveKeys = []string{"password", "secret", "apikey", "credential", "basicauth", "privatekey"}
+
+//Handler 记录控制台全部变更操作的审计日志，包括操作人、来源IP、请求参数及目标的变更前后数据
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isMutation(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			log.Warn("audit read request body error:", err)
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		l := &entity.AuditLog{
+			UserID:       requestUserID(r),
+			RemoteAddr:   remoteIP(r.RemoteAddr),
+			ForwardedFor: r.Header.Get("X-Forwarded-For"),
+			Method:       r.Method,
+			Operation:    r.URL.Path,
+			OperateTime:  time.Now().Format("2006-01-02 15:04:05"),
+		}
+		values := requestValues(r, body)
+		l.RequestData = encode(values)
+
+		t := matchTarget(r.URL.Path)
+		l.TargetType = t.targetType
+		l.TargetID = t.id(r.URL.Path, values)
+		if isSingle(l.TargetID) {
+			l.BeforeData = t.snapshot(l.TargetID)
+		}
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		var responseID string
+		l.StatusCode, l.ResultDesc, responseID = rec.result(t.idField)
+		if l.TargetID == "" {
+			l.TargetID = responseID
+		}
+		if isSingle(l.TargetID) {
+			l.AfterData = t.snapshot(l.TargetID)
+		}
+		if err := module.AddAuditLog(l); err != nil {
+			log.Warn("add audit log error:", err)
+		}
+	})
+}
+
+// isMutation REST接口以请求方法区分，旧版接口以路径中的操作名区分
+func isMutation(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPut, http.MethodDelete, http.MethodPatch:
+		return true
+	}
+	if strings.HasPrefix(r.URL.Path, rest.Prefix) {
+		return r.Method == http.MethodPost
+	}
+	for _, seg := range strings.Split(strings.Trim(r.URL.Path, "/"), "/") {
+		if mutationActions[seg] || strings.HasPrefix(seg, "batch") {
+			return true
+		}
+	}
+	return false
+}
+
+// requestUserID 从登录会话或访问令牌中获取操作人，无法识别时返回0
+func requestUserID(r *http.Request) int {
+	if v := r.Header.Get("Authorization"); strings.HasPrefix(v, "Bearer ") {
+		return account.GetAPITokenUserID(strings.TrimSpace(strings.TrimPrefix(v, "Bearer ")))
+	}
+	userIDCookie, idErr := r.Cookie("userID")
+	userCookie, userErr := r.Cookie("userToken")
+	if idErr != nil || userErr != nil {
+		return 0
+	}
+	userID, err := strconv.Atoi(userIDCookie.Value)
+	if err != nil || !account.CheckLogin(userCookie.Value, userID) {
+		return 0
+	}
+	return userID
+}
+
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// requestValues 读取查询参数及请求体，上传文件只记录文件名及大小
+func requestValues(r *http.Request, body []byte) map[string]interface{} {
+	values := make(map[string]interface{})
+	addValues(values, r.URL.Query())
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		form, _ := url.ParseQuery(string(body))
+		addValues(values, form)
+	case "multipart/form-data":
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			if part.FileName() != "" {
+				n, _ := io.Copy(ioutil.Discard, part)
+				values[part.FormName()] = fmt.Sprintf("file:%s (%d bytes)", part.FileName(), n)
+				continue
+			}
+			v, _ := ioutil.ReadAll(io.LimitReader(part, maxDataSize))
+			values[part.FormName()] = redact(part.FormName(), string(v))
+		}
+	default:
+		if len(body) > 0 {
+			values["body"] = redact("body", string(body))
+		}
+	}
+	return values
+}
+
+func addValues(values map[string]interface{}, form url.Values) {
+	for k, v := range form {
+		if len(v) == 1 {
+			values[k] = redact(k, v[0])
+			continue
+		}
+		list := make([]interface{}, 0, len(v))
+		for _, s := range v {
+			list = append(list, redact(k, s))
+		}
+		values[k] = list
+	}
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasSuffix(key, "token") {
+		return true
+	}
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact 屏蔽敏感字段，值为json字符串时解析后逐层屏蔽
+func redact(key string, v interface{}) interface{} {
+	if isSensitive(key) {
+		return maskValue
+	}
+	switch value := v.(type) {
+	case string:
+		s := strings.TrimSpace(value)
+		if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+			var data interface{}
+			if json.Unmarshal([]byte(s), &data) == nil {
+				return redact("", data)
+			}
+		}
+		return value
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = redact(k, item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redact(key, item)
+		}
+		return value
+	}
+	return v
+}
+
+func encode(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	if len(data) > maxDataSize {
+		return string(data[:maxDataSize]) + truncatedSuffix
+	}
+	return string(data)
+}
+
+func isSingle(id string) bool {
+	return id != "" && !strings.ContainsAny(id, ",[")
+}
+
+// recorder 记录响应状态码及响应内容
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	if remain := maxDataSize - r.body.Len(); remain > 0 {
+		if len(data) > remain {
+			r.body.Write(data[:remain])
+		} else {
+			r.body.Write(data)
+		}
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+// result 解析响应中的状态码、错误描述及新增目标的ID
+func (r *recorder) result(idField string) (string, string, string) {
+	statusCode := strconv.Itoa(r.status)
+	ret := make(map[string]interface{})
+	if json.Unmarshal(r.body.Bytes(), &ret) != nil {
+		return statusCode, "", ""
+	}
+	if code, ok := ret["statusCode"].(string); ok {
+		statusCode = code
+	}
+	desc, _ := ret["resultDesc"].(string)
+	if e, ok := ret["error"].(string); ok {
+		desc = e
+	}
+	if idField == "" {
+		return statusCode, desc, ""
+	}
+	id := ret[idField]
+	if data, ok := ret["data"].(map[string]interface{}); ok && id == nil {
+		id = data[idField]
+	}
+	if id == nil {
+		return statusCode, desc, ""
+	}
+	return statusCode, desc, fmt.Sprint(id)
+}
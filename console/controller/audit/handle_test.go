@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIsMutation(t *testing.T) {
+	cases := map[string]bool{
+		"POST /apis/edit":                 true,
+		"POST /apis/batchDelete":          true,
+		"GET /project/delete":             true,
+		"POST /import/openapi":            true,
+		"POST /apis/getList":              false,
+		"POST /export/openapi":            false,
+		"GET /config/log/console":         false,
+		"PUT /config/log/console":         true,
+		"GET /api/v1/apis/1":              false,
+		"POST /api/v1/apis":               true,
+		"DELETE /api/v1/apis/1":           true,
+		"POST /version/config/publish":    true,
+		"POST /plugin/availiable/check":   false,
+		"POST /balance/service/default":   true,
+		"POST /strategy/api/canary/edit":  true,
+		"POST /audit/log/export":          false,
+		"POST /version/config/diff":       false,
+		"POST /user/token/getList":        false,
+		"POST /plugin/api/addPluginToApi": true,
+	}
+	for c, expect := range cases {
+		s := strings.SplitN(c, " ", 2)
+		if isMutation(httptest.NewRequest(s[0], s[1], nil)) != expect {
+			t.Errorf("%s: expect %v", c, expect)
+		}
+	}
+}
+
+func TestRequestValues(t *testing.T) {
+	body := "oldPassword=a&newPassword=b&pluginConfig=%7B%22secretKey%22%3A%22s%22%2C%22name%22%3A%22n%22%7D"
+	r := httptest.NewRequest("POST", "/user/password/edit?from=web", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	data := encode(requestValues(r, []byte(body)))
+	expect := `{"from":"web","newPassword":"******","oldPassword":"******","pluginConfig":{"name":"n","secretKey":"******"}}`
+	if data != expect {
+		t.Errorf("got %s", data)
+	}
+}
+
+func TestMatchTarget(t *testing.T) {
+	values := map[string]interface{}{"apiID": "3", "strategyID": "abc"}
+	cases := []struct{ path, targetType, id string }{
+		{"/apis/edit", "api", "3"},
+		{"/apis/cors/edit", "apiCORS", "3"},
+		{"/strategy/api/add", "strategyAPI", "abc"},
+		{"/api/v1/apis/7", "api", "7"},
+		{"/api/v1/apis", "api", ""},
+		{"/config/log/node", "logConfig", "node"},
+		{"/unknown/edit", "unknown", ""},
+	}
+	for _, c := range cases {
+		target := matchTarget(c.path)
+		if target.targetType != c.targetType || target.id(c.path, values) != c.id {
+			t.Errorf("%s: got %s %s", c.path, target.targetType, target.id(c.path, values))
+		}
+	}
+}
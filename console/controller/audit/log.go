@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	module "github.com/eolinker/goku-api-gateway/console/module/audit"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func readCondition(httpRequest *http.Request) (*entity.AuditLogCondition, bool) {
+	httpRequest.ParseForm()
+	cond := &entity.AuditLogCondition{
+		TargetType: httpRequest.Form.Get("targetType"),
+		TargetID:   httpRequest.Form.Get("targetID"),
+		Keyword:    httpRequest.Form.Get("keyword"),
+		RemoteAddr: httpRequest.Form.Get("remoteAddr"),
+		BeginTime:  httpRequest.Form.Get("beginTime"),
+		EndTime:    httpRequest.Form.Get("endTime"),
+	}
+	if userID := httpRequest.Form.Get("userID"); userID != "" {
+		id, err := strconv.Atoi(userID)
+		if err != nil {
+			return nil, false
+		}
+		cond.UserID = id
+	}
+	return cond, true
+}
+
+//GetAuditLogList 获取审计日志列表
+func GetAuditLogList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationADMIN, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	cond, ok := readCondition(httpRequest)
+	if !ok {
+		controller.WriteError(httpResponse, "400001", "auditLog", "[ERROR]Illegal userID!", nil)
+		return
+	}
+	page, err := strconv.Atoi(httpRequest.Form.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(httpRequest.Form.Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 15
+	}
+	result, count, err := module.GetAuditLogList(cond, page, pageSize)
+	if err != nil {
+		controller.WriteError(httpResponse, "400000", "auditLog", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfoWithPage(httpResponse, "auditLog", "logList", result, &controller.PageInfo{
+		ItemNum:  len(result),
+		Page:     page,
+		PageSize: pageSize,
+		TotalNum: count,
+	})
+}
+
+//ExportAuditLog 导出审计日志
+func ExportAuditLog(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationADMIN, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	cond, ok := readCondition(httpRequest)
+	if !ok {
+		controller.WriteError(httpResponse, "400001", "auditLog", "[ERROR]Illegal userID!", nil)
+		return
+	}
+	format := httpRequest.Form.Get("format")
+	if format == "" {
+		format = module.FormatCSV
+	}
+	data, err := module.ExportAuditLog(cond, format)
+	if err != nil {
+		controller.WriteError(httpResponse, "400000", "auditLog", "[ERROR]"+err.Error(), err)
+		return
+	}
+	contentType := "text/csv; charset=utf-8"
+	if format == module.FormatJSON {
+		contentType = "application/json"
+	}
+	httpResponse.Header().Set("Content-Type", contentType)
+	httpResponse.Header().Set("Content-Disposition", "attachment; filename=audit-"+time.Now().Format("20060102150405")+"."+format)
+	httpResponse.Write(data)
+}
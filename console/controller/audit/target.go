@@ -0,0 +1,238 @@
+package audit
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller/rest"
+	"github.com/eolinker/goku-api-gateway/console/module/alert"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
+	"github.com/eolinker/goku-api-gateway/console/module/cluster"
+	config_log "github.com/eolinker/goku-api-gateway/console/module/config-log"
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+	"github.com/eolinker/goku-api-gateway/console/module/plugin"
+	"github.com/eolinker/goku-api-gateway/console/module/project"
+	"github.com/eolinker/goku-api-gateway/console/module/service"
+	"github.com/eolinker/goku-api-gateway/console/module/strategy"
+)
+
+// target 操作目标，路径按最长前缀匹配
+type target struct {
+	prefix     string
+	targetType string
+	// idField 目标ID的请求参数名，同时用于从新增操作的响应中读取ID
+	idField string
+	// pathID 为true时目标ID取路径中前缀之后的一段
+	pathID bool
+	get    func(id string) (interface{}, error)
+}
+
+var targets = []*target{
+	{prefix: "/user/", targetType: "user"},
+	{prefix: "/user/token/", targetType: "apiToken", idField: "tokenID"},
+	{prefix: "/project/", targetType: "project", idField: "projectID", get: getProject},
+	{prefix: "/apis/", targetType: "api", idField: "apiID", get: getAPI},
+	{prefix: "/apis/group/", targetType: "apiGroup", idField: "groupID"},
+	{prefix: "/apis/cors/", targetType: "apiCORS", idField: "apiID", get: getAPICORS},
+	{prefix: "/apis/ipAccess/", targetType: "apiIPAccess", idField: "apiID", get: getAPIIPAccess},
+	{prefix: "/apis/transform/", targetType: "apiTransform", idField: "apiID", get: getAPITransform},
+	{prefix: "/apis/mock/", targetType: "apiMock", idField: "apiID", get: getAPIMock},
+	{prefix: "/plugin/", targetType: "plugin", idField: "pluginName", get: getPlugin},
+	{prefix: "/plugin/api/", targetType: "apiPlugin", idField: "apiID"},
+	{prefix: "/plugin/strategy/", targetType: "strategyPlugin", idField: "strategyID"},
+	{prefix: "/strategy/", targetType: "strategy", idField: "strategyID", get: getStrategy},
+	{prefix: "/strategy/group/", targetType: "strategyGroup", idField: "groupID"},
+	{prefix: "/strategy/api/", targetType: "strategyAPI", idField: "strategyID"},
+	{prefix: "/strategy/cors/", targetType: "strategyCORS", idField: "strategyID", get: getStrategyCORS},
+	{prefix: "/strategy/ipAccess/", targetType: "strategyIPAccess", idField: "strategyID", get: getStrategyIPAccess},
+	{prefix: "/auth/", targetType: "auth", idField: "strategyID"},
+	{prefix: "/node/", targetType: "node", idField: "nodeID", get: getNode},
+	{prefix: "/node/group/", targetType: "nodeGroup", idField: "groupID", get: getNodeGroup},
+	{prefix: "/balance/", targetType: "balance", idField: "balanceName", get: getBalance},
+	{prefix: "/balance/service/", targetType: "service", idField: "name", get: getService},
+	{prefix: "/cluster/", targetType: "cluster", idField: "name", get: getCluster},
+	{prefix: "/version/", targetType: "version", idField: "versionID"},
+	{prefix: "/gateway/", targetType: "gatewayConfig"},
+	{prefix: "/alert/rule/", targetType: "alertRule", idField: "ruleID", get: getAlertRule},
+	{prefix: "/config/log/", targetType: "logConfig", pathID: true, get: getLogConfig},
+	{prefix: "/config/import", targetType: "declarative"},
+	{prefix: "/import/", targetType: "import", idField: "projectID"},
+	{prefix: rest.Prefix + "projects", targetType: "project", idField: "projectID", pathID: true, get: getProject},
+	{prefix: rest.Prefix + "apis", targetType: "api", idField: "apiID", pathID: true, get: getAPI},
+	{prefix: rest.Prefix + "strategies", targetType: "strategy", idField: "strategyID", pathID: true, get: getStrategy},
+	{prefix: rest.Prefix + "balances", targetType: "balance", idField: "balanceName", pathID: true, get: getBalance},
+	{prefix: rest.Prefix + "versions", targetType: "version", idField: "versionID", pathID: true},
+	{prefix: rest.Prefix + "config", targetType: "declarative"},
+}
+
+func init() {
+	sort.Slice(targets, func(i, j int) bool {
+		return len(targets[i].prefix) > len(targets[j].prefix)
+	})
+}
+
+// matchTarget 未登记的路径以第一段作为目标类型
+func matchTarget(path string) *target {
+	for _, t := range targets {
+		if strings.HasPrefix(path, t.prefix) {
+			return t
+		}
+	}
+	return &target{targetType: strings.SplitN(strings.Trim(path, "/"), "/", 2)[0]}
+}
+
+func (t *target) id(path string, values map[string]interface{}) string {
+	if t.pathID {
+		id := strings.TrimPrefix(strings.TrimPrefix(path, t.prefix), "/")
+		return strings.SplitN(id, "/", 2)[0]
+	}
+	if t.idField == "" {
+		return ""
+	}
+	for _, field := range []string{t.idField, t.idField + "List"} {
+		if v, ok := values[field].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// snapshot 获取目标当前数据，目标不存在时返回空
+func (t *target) snapshot(id string) string {
+	if t.get == nil {
+		return ""
+	}
+	v, err := t.get(id)
+	if err != nil || v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil || value == nil {
+		return ""
+	}
+	return encode(redact("", value))
+}
+
+func getByInt(id string, get func(int) (bool, interface{}, error)) (interface{}, error) {
+	i, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+	flag, v, err := get(i)
+	if !flag {
+		return nil, err
+	}
+	return v, nil
+}
+
+func getProject(id string) (interface{}, error) {
+	return getByInt(id, func(i int) (bool, interface{}, error) {
+		return project.GetProjectInfo(i)
+	})
+}
+
+func getAPI(id string) (interface{}, error) {
+	return getByInt(id, func(i int) (bool, interface{}, error) {
+		return api.GetAPIInfo(i)
+	})
+}
+
+func getAPICORS(id string) (interface{}, error) {
+	return getByInt(id, func(i int) (bool, interface{}, error) {
+		return api.GetCORS(i)
+	})
+}
+
+func getAPIIPAccess(id string) (interface{}, error) {
+	return getByInt(id, func(i int) (bool, interface{}, error) {
+		return api.GetIPAccess(i)
+	})
+}
+
+func getAPITransform(id string) (interface{}, error) {
+	return getByInt(id, func(i int) (bool, interface{}, error) {
+		return api.GetTransform(i)
+	})
+}
+
+func getAPIMock(id string) (interface{}, error) {
+	return getByInt(id, func(i int) (bool, interface{}, error) {
+		return api.GetMock(i)
+	})
+}
+
+func getNode(id string) (interface{}, error) {
+	return getByInt(id, func(i int) (bool, interface{}, error) {
+		return node.GetNodeInfo(i)
+	})
+}
+
+func getAlertRule(id string) (interface{}, error) {
+	return getByInt(id, func(i int) (bool, interface{}, error) {
+		return alert.GetRule(i)
+	})
+}
+
+func getNodeGroup(id string) (interface{}, error) {
+	return getByInt(id, func(i int) (bool, interface{}, error) {
+		return node.GetNodeGroupInfo(i)
+	})
+}
+
+func getPlugin(id string) (interface{}, error) {
+	flag, v, err := plugin.GetPluginInfo(id)
+	if !flag {
+		return nil, err
+	}
+	return v, nil
+}
+
+func getStrategy(id string) (interface{}, error) {
+	flag, v, err := strategy.GetStrategyInfo(id)
+	if !flag {
+		return nil, err
+	}
+	return v, nil
+}
+
+func getStrategyCORS(id string) (interface{}, error) {
+	flag, v, err := strategy.GetCORS(id)
+	if !flag {
+		return nil, err
+	}
+	return v, nil
+}
+
+func getStrategyIPAccess(id string) (interface{}, error) {
+	flag, v, err := strategy.GetIPAccess(id)
+	if !flag {
+		return nil, err
+	}
+	return v, nil
+}
+
+func getBalance(id string) (interface{}, error) {
+	return balance.Get(id)
+}
+
+func getService(id string) (interface{}, error) {
+	return service.Get(id)
+}
+
+func getCluster(id string) (interface{}, error) {
+	return cluster.GetCluster(id)
+}
+
+func getLogConfig(id string) (interface{}, error) {
+	if id == config_log.AccessLog {
+		return config_log.GetAccess()
+	}
+	return config_log.Get(id)
+}
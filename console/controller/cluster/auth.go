@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+)
+
+//GetClusterAuth 获取集群节点接入认证配置
+func GetClusterAuth(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationNode, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	result, err := node.GetClusterAuth(httpRequest.Form.Get("name"))
+	if err != nil {
+		controller.WriteError(httpResponse, "370000", "cluster", "[ERROR]The cluster does not exist", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"cluster",
+		"authInfo",
+		result)
+}
+
+//ResetClusterToken 重新生成集群凭证，enable为0时清除凭证
+func ResetClusterToken(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationNode, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	enable := httpRequest.Form.Get("enable") != "0"
+	token, err := node.ResetClusterToken(httpRequest.Form.Get("name"), enable)
+	if err != nil {
+		controller.WriteError(httpResponse, "370000", "cluster", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"cluster",
+		"token",
+		token)
+}
+
+//EditClusterRegister 设置集群节点自动注册
+func EditClusterRegister(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationNode, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	autoRegister := httpRequest.Form.Get("autoRegister") == "1" || httpRequest.Form.Get("autoRegister") == "true"
+	groupID := httpRequest.Form.Get("groupID")
+	gID, err := strconv.Atoi(groupID)
+	if err != nil && groupID != "" {
+		controller.WriteError(httpResponse, "370004", "cluster", "[ERROR]Illegal groupID", err)
+		return
+	}
+	err = node.SetClusterRegister(httpRequest.Form.Get("name"), autoRegister, gID)
+	if err != nil {
+		controller.WriteError(httpResponse, "370005", "cluster", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"cluster",
+		"",
+		nil)
+}
@@ -0,0 +1,143 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//StartRollout 开始灰度发布
+func StartRollout(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	id, err := strconv.Atoi(httpRequest.Form.Get("versionID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380002", "versionConfig", "[ERROR]Illegal versionID", err)
+		return
+	}
+	r := &entity.Rollout{
+		VersionID:  id,
+		TargetType: httpRequest.Form.Get("targetType"),
+		AutoAbort:  httpRequest.Form.Get("autoAbort") == "1" || httpRequest.Form.Get("autoAbort") == "true",
+		OperatorID: userID,
+		CreateTime: time.Now().Format("2006-01-02 15:04:05"),
+	}
+	switch r.TargetType {
+	case entity.RolloutTargetGroup:
+		err = json.Unmarshal([]byte(httpRequest.Form.Get("groupIDs")), &r.Target.GroupIDs)
+	case entity.RolloutTargetNode:
+		err = json.Unmarshal([]byte(httpRequest.Form.Get("nodeIDs")), &r.Target.NodeIDs)
+	case entity.RolloutTargetPercent:
+		r.Target.Percent, err = strconv.Atoi(httpRequest.Form.Get("percent"))
+	}
+	if err != nil {
+		controller.WriteError(httpResponse, "380006", "versionConfig", "[ERROR]Illegal rollout target", err)
+		return
+	}
+	if v := httpRequest.Form.Get("failureThreshold"); v != "" {
+		r.FailureThreshold, err = strconv.Atoi(v)
+		if err != nil {
+			controller.WriteError(httpResponse, "380007", "versionConfig", "[ERROR]Illegal failureThreshold", err)
+			return
+		}
+	}
+	if v := httpRequest.Form.Get("applyTimeout"); v != "" {
+		r.ApplyTimeout, err = strconv.Atoi(v)
+		if err != nil {
+			controller.WriteError(httpResponse, "380008", "versionConfig", "[ERROR]Illegal applyTimeout", err)
+			return
+		}
+	}
+
+	rolloutID, err := versionConfig.StartRollout(r)
+	if err != nil {
+		controller.WriteError(httpResponse, "380009", "versionConfig", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"rolloutID",
+		rolloutID)
+}
+
+//PromoteRollout 将灰度版本发布到全部节点
+func PromoteRollout(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	err := versionConfig.PromoteRollout(userID, now)
+	if err != nil {
+		controller.WriteError(httpResponse, "380010", "versionConfig", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"",
+		nil)
+}
+
+//AbortRollout 终止灰度发布
+func AbortRollout(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	reason := httpRequest.Form.Get("reason")
+	if reason == "" {
+		reason = "manual abort"
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	err := versionConfig.AbortRollout(reason, now)
+	if err != nil {
+		controller.WriteError(httpResponse, "380010", "versionConfig", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"",
+		nil)
+}
+
+//GetRolloutStatus 获取灰度发布状态及各节点运行的版本
+func GetRolloutStatus(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	rollout, nodes, err := versionConfig.GetRolloutStatus()
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"rolloutInfo",
+		map[string]interface{}{
+			"rollout":  rollout,
+			"nodeList": nodes,
+		})
+}
+
+//GetRolloutList 获取灰度发布记录
+func GetRolloutList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	result, _ := versionConfig.GetRolloutList()
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"rolloutList",
+		result)
+}
@@ -28,7 +28,7 @@ func GetVersionList(httpResponse http.ResponseWriter, httpRequest *http.Request)
 
 //AddVersionConfig 新增版本配置
 func AddVersionConfig(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	userID, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -51,7 +51,7 @@ func AddVersionConfig(httpResponse http.ResponseWriter, httpRequest *http.Reques
 	}
 
 	if p == 1 {
-		versionConfig.PublishVersion(id, now)
+		versionConfig.PublishVersion(id, userID, now)
 	}
 	controller.WriteResultInfo(httpResponse,
 		"versionConfig",
@@ -62,7 +62,7 @@ func AddVersionConfig(httpResponse http.ResponseWriter, httpRequest *http.Reques
 
 //BatchDeleteVersionConfig 批量删除版本配置
 func BatchDeleteVersionConfig(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -91,6 +91,10 @@ func BatchDeleteVersionConfig(httpResponse http.ResponseWriter, httpRequest *htt
 
 //PublishVersion 发布版本
 func PublishVersion(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
 	httpRequest.ParseForm()
 	versionID := httpRequest.Form.Get("versionID")
 	id, err := strconv.Atoi(versionID)
@@ -99,7 +103,7 @@ func PublishVersion(httpResponse http.ResponseWriter, httpRequest *http.Request)
 		return
 	}
 	now := time.Now().Format("2006-01-02 15:04:05")
-	err = versionConfig.PublishVersion(id, now)
+	err = versionConfig.PublishVersion(id, userID, now)
 	if err != nil {
 		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
 		return
@@ -111,3 +115,86 @@ func PublishVersion(httpResponse http.ResponseWriter, httpRequest *http.Request)
 		nil)
 	return
 }
+
+//GetVersionDiff 获取两个版本之间的差异
+func GetVersionDiff(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	from, err := strconv.Atoi(httpRequest.Form.Get("from"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380002", "versionConfig", "[ERROR]Illegal versionID", err)
+		return
+	}
+	to, err := strconv.Atoi(httpRequest.Form.Get("to"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380002", "versionConfig", "[ERROR]Illegal versionID", err)
+		return
+	}
+	result, err := versionConfig.GetVersionDiff(from, to)
+	if err != nil {
+		controller.WriteError(httpResponse, "380004", "versionConfig", "[ERROR]The version does not exist", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"diff",
+		result)
+}
+
+//RollbackVersion 回滚版本，未传versionID时回滚到上一个发布的版本
+func RollbackVersion(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	versionID := httpRequest.Form.Get("versionID")
+	id, err := strconv.Atoi(versionID)
+	if err != nil && versionID != "" {
+		controller.WriteError(httpResponse, "380002", "versionConfig", "[ERROR]Illegal versionID", err)
+		return
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	id, err = versionConfig.RollbackVersion(id, userID, now)
+	if err != nil {
+		controller.WriteError(httpResponse, "380005", "versionConfig", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"versionID",
+		id)
+}
+
+//GetPublishHistory 获取发布记录
+func GetPublishHistory(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	result, _ := versionConfig.GetPublishHistory()
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"publishList",
+		result)
+}
+
+//GetNodeConfigStatus 获取各节点的配置应用状态
+func GetNodeConfigStatus(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	result, err := versionConfig.GetNodeConfigStatus()
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"nodeList",
+		result)
+}
@@ -0,0 +1,84 @@
+package declarative
+
+import (
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/declarative"
+)
+
+//operationTypes 声明式配置包含的资源：接口、服务及负载、策略
+var operationTypes = []string{controller.OperationAPI, controller.OperationLoadBalance, controller.OperationStrategy}
+
+//ExportConfig 导出声明式网关配置
+func ExportConfig(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermissions(httpResponse, httpRequest, controller.OperationREAD, operationTypes...)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	format := httpRequest.Form.Get("format")
+	if format == "" {
+		format = declarative.FormatYAML
+	}
+	data, err := declarative.Export(format)
+	if err != nil {
+		controller.WriteError(httpResponse, "390000", "declarative", "[ERROR]"+err.Error(), err)
+		return
+	}
+	contentType := "application/x-yaml"
+	if format == declarative.FormatJSON {
+		contentType = "application/json"
+	}
+	httpResponse.Header().Set("Content-Type", contentType)
+	httpResponse.Header().Set("Content-Disposition", "attachment; filename=goku."+format)
+	httpResponse.Write(data)
+}
+
+//ImportConfig 导入声明式网关配置，dryRun时只返回变更计划
+func ImportConfig(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	userID, e := controller.CheckPermissions(httpResponse, httpRequest, controller.OperationEDIT, operationTypes...)
+	if e != nil {
+		return
+	}
+	data, err := readConfig(httpRequest)
+	if err != nil || len(data) == 0 {
+		controller.WriteError(httpResponse, "390001", "declarative", "[ERROR]Illegal config", err)
+		return
+	}
+	dryRun := httpRequest.Form.Get("dryRun")
+	changes, err := declarative.Import(data, dryRun == "1" || dryRun == "true", userID)
+	if err != nil {
+		controller.WriteError(httpResponse, "390002", "declarative", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"declarative",
+		"changeList",
+		changes)
+}
+
+// readConfig 配置可通过表单字段config、上传文件file或请求体提交
+func readConfig(httpRequest *http.Request) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(httpRequest.Header.Get("Content-Type"))
+	switch mediaType {
+	case "multipart/form-data":
+		if err := httpRequest.ParseMultipartForm(32 << 20); err != nil {
+			return nil, err
+		}
+		if file, _, err := httpRequest.FormFile("file"); err == nil {
+			defer file.Close()
+			return ioutil.ReadAll(file)
+		}
+		return []byte(httpRequest.Form.Get("config")), nil
+	case "application/x-www-form-urlencoded":
+		if err := httpRequest.ParseForm(); err != nil {
+			return nil, err
+		}
+		return []byte(httpRequest.Form.Get("config")), nil
+	}
+	httpRequest.ParseForm()
+	return ioutil.ReadAll(httpRequest.Body)
+}
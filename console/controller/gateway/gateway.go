@@ -171,9 +171,10 @@ func GetGatewayBasicInfo(httpResponse http.ResponseWriter, httpRequest *http.Req
 
 	}
 	monitorInfo := map[string]interface{}{
-		"statusCode": "000000",
-		"type":       "monitor",
-		"baseInfo":   result.BaseInfo,
+		"statusCode":  "000000",
+		"type":        "monitor",
+		"baseInfo":    result.BaseInfo,
+		"monitorInfo": result.MonitorInfo,
 	}
 	info, _ := json.Marshal(monitorInfo)
 
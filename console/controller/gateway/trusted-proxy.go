@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/gateway"
+)
+
+//EditTrustedProxies 编辑可信代理列表
+func EditTrustedProxies(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	trustedProxies := httpRequest.PostFormValue("trustedProxies")
+	proxies := make([]string, 0)
+	if trustedProxies != "" {
+		err := json.Unmarshal([]byte(trustedProxies), &proxies)
+		if err != nil {
+			controller.WriteError(httpResponse,
+				"320012",
+				"gateway",
+				"[ERROR]Illegal trustedProxies!",
+				err)
+			return
+		}
+	}
+	flag, result, err := gateway.SetTrustedProxies(proxies)
+	if !flag {
+		controller.WriteError(httpResponse,
+			"320012",
+			"gateway",
+			result,
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "gateway", "", nil)
+}
+
+//GetTrustedProxies 获取可信代理列表
+func GetTrustedProxies(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationGatewayConfig, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+
+	flag, result, err := gateway.GetTrustedProxies()
+	if !flag {
+		controller.WriteError(httpResponse,
+			"320000",
+			"gateway",
+			"[ERROR]The gateway config does not exist",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "gateway", "trustedProxies", result)
+}
@@ -0,0 +1,148 @@
+package monitor
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/cluster"
+	module "github.com/eolinker/goku-api-gateway/console/module/monitor"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	timeFormat   = "2006-01-02 15:04:05"
+	defaultRange = time.Hour * 24
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+func parseTime(value string, defaultTime time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultTime, nil
+	}
+	return time.ParseInLocation(timeFormat, value, time.Local)
+}
+
+//readCondition 读取监控查询条件，未指定时间时查询最近24小时
+func readCondition(httpResponse http.ResponseWriter, httpRequest *http.Request) (*entity.MonitorCondition, bool) {
+	httpRequest.ParseForm()
+	end, err := parseTime(httpRequest.Form.Get("endTime"), time.Now())
+	if err != nil {
+		controller.WriteError(httpResponse, "410001", "monitor", "[ERROR]Illegal endTime!", err)
+		return nil, false
+	}
+	begin, err := parseTime(httpRequest.Form.Get("beginTime"), end.Add(-defaultRange))
+	if err != nil || begin.After(end) {
+		controller.WriteError(httpResponse, "410001", "monitor", "[ERROR]Illegal beginTime!", err)
+		return nil, false
+	}
+	cond := &entity.MonitorCondition{
+		BeginHour:  begin.Unix() / 3600 * 3600,
+		EndHour:    end.Unix() / 3600 * 3600,
+		StrategyID: httpRequest.Form.Get("strategyID"),
+	}
+	if name := httpRequest.Form.Get("cluster"); name != "" {
+		cond.ClusterID = cluster.GetClusterIDByName(name)
+		if cond.ClusterID == 0 {
+			controller.WriteError(httpResponse, "410002", "monitor", "[ERROR]Illegal cluster!", nil)
+			return nil, false
+		}
+	}
+	if apiID := httpRequest.Form.Get("apiID"); apiID != "" {
+		cond.APIID, err = strconv.Atoi(apiID)
+		if err != nil {
+			controller.WriteError(httpResponse, "410003", "monitor", "[ERROR]Illegal apiID!", err)
+			return nil, false
+		}
+	}
+	return cond, true
+}
+
+//GetMonitorSummary 获取监控计数汇总
+func GetMonitorSummary(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	cond, ok := readCondition(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	result, err := module.GetSummary(cond)
+	if err != nil {
+		controller.WriteError(httpResponse, "410000", "monitor", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "monitor", "monitorInfo", result)
+}
+
+//GetMonitorTrend 获取每小时的监控计数
+func GetMonitorTrend(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	cond, ok := readCondition(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	result, err := module.GetTrend(cond)
+	if err != nil {
+		controller.WriteError(httpResponse, "410000", "monitor", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "monitor", "trend", result)
+}
+
+//GetMonitorAPIRank 获取最慢或失败最多的接口
+func GetMonitorAPIRank(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	cond, ok := readCondition(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	orderBy := httpRequest.Form.Get("orderBy")
+	if orderBy == "" {
+		orderBy = module.OrderFailure
+	}
+	if orderBy != module.OrderFailure && orderBy != module.OrderSlowest {
+		controller.WriteError(httpResponse, "410004", "monitor", "[ERROR]Illegal orderBy!", nil)
+		return
+	}
+	limit, err := strconv.Atoi(httpRequest.Form.Get("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	result, err := module.GetAPIRank(cond, orderBy, limit)
+	if err != nil {
+		controller.WriteError(httpResponse, "410000", "monitor", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "monitor", "apiList", result)
+}
+
+//GetMonitorPluginList 获取插件执行计数，包括失败、panic、超时次数及耗时
+func GetMonitorPluginList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationNone, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	cond, ok := readCondition(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	result, err := module.GetPluginList(cond)
+	if err != nil {
+		controller.WriteError(httpResponse, "410000", "monitor", "[ERROR]"+err.Error(), err)
+		return
+	}
+	controller.WriteResultInfoWithPage(httpResponse, "monitor", "pluginList", result, controller.NewItemNum(len(result)))
+}
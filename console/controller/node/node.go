@@ -397,3 +397,26 @@ func BatchDeleteNode(httpResponse http.ResponseWriter, httpRequest *http.Request
 
 	return
 }
+
+//ResetNodeToken 重新生成节点凭证，enable为0时清除凭证
+func ResetNodeToken(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationNode, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+	id, err := strconv.Atoi(httpRequest.Form.Get("nodeID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "230001", "node", "[ERROR]Illegal nodeID!", err)
+		return
+	}
+	token, err := node.ResetNodeToken(id, httpRequest.Form.Get("enable") != "0")
+	if err != nil {
+		controller.WriteError(httpResponse, "330000", "node", err.Error(), err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse,
+		"node",
+		"token",
+		token)
+}
@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/eolinker/goku-api-gateway/console/module/node"
 	"github.com/eolinker/goku-api-gateway/console/module/plugin"
 	plugin_config "github.com/eolinker/goku-api-gateway/console/module/plugin/plugin-config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
 	"github.com/eolinker/goku-api-gateway/utils"
 )
 
@@ -42,7 +44,7 @@ func GetPluginList(httpResponse http.ResponseWriter, httpRequest *http.Request)
 
 //AddPlugin 新增插件信息
 func AddPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -108,7 +110,7 @@ func AddPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 
 //EditPlugin 修改插件信息
 func EditPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -162,9 +164,51 @@ func EditPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 	controller.WriteResultInfo(httpResponse, "plugin", "", nil)
 }
 
+//EditPluginPolicy 修改插件执行超时时间及失败策略
+func EditPluginPolicy(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	pluginName := httpRequest.PostFormValue("pluginName")
+	timeout := httpRequest.PostFormValue("timeout")
+	failStatus := httpRequest.PostFormValue("failStatus")
+	policy := &entity.PluginPolicy{
+		FailPolicy: httpRequest.PostFormValue("failPolicy"),
+		FailBody:   httpRequest.PostFormValue("failBody"),
+	}
+	var err error
+	if timeout != "" {
+		policy.Timeout, err = strconv.Atoi(timeout)
+		if err != nil {
+			controller.WriteError(httpResponse, "210012", "plugin", "[ERROR]Illegal timeout!", err)
+			return
+		}
+	}
+	if failStatus != "" {
+		policy.FailStatus, err = strconv.Atoi(failStatus)
+		if err != nil {
+			controller.WriteError(httpResponse, "210012", "plugin", "[ERROR]Illegal failStatus!", err)
+			return
+		}
+	}
+	if err = plugin.CheckPolicy(policy); err != nil {
+		controller.WriteError(httpResponse, "210012", "plugin", "[ERROR]"+err.Error(), err)
+		return
+	}
+	flag, result, err := plugin.EditPluginPolicy(pluginName, policy)
+	if !flag {
+		controller.WriteError(httpResponse, "210000", "plugin", result, err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse, "plugin", "", nil)
+}
+
 //DeletePlugin 删除插件信息
 func DeletePlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -252,9 +296,29 @@ func GetPluginConfig(httpResponse http.ResponseWriter, httpRequest *http.Request
 	return
 }
 
+//GetPluginConfigSchema 获取插件配置的JSON Schema，插件未提供时返回null
+func GetPluginConfigSchema(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+
+	pluginName := httpRequest.PostFormValue("pluginName")
+	source, has, err := plugin_config.GetSchema(pluginName)
+	if err != nil {
+		controller.WriteError(httpResponse, "210013", "plugin", "[ERROR]"+err.Error(), err)
+		return
+	}
+	var schema interface{}
+	if has {
+		json.Unmarshal(source, &schema)
+	}
+	controller.WriteResultInfo(httpResponse, "plugin", "configSchema", schema)
+}
+
 //CheckIndexIsExist 判断插件优先级是否存在
 func CheckIndexIsExist(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -288,7 +352,7 @@ func CheckIndexIsExist(httpResponse http.ResponseWriter, httpRequest *http.Reque
 
 //CheckNameIsExist 检查插件名称是否存在
 func CheckNameIsExist(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -311,7 +375,7 @@ func CheckNameIsExist(httpResponse http.ResponseWriter, httpRequest *http.Reques
 
 //StartPlugin 开启插件
 func StartPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -334,7 +398,7 @@ func StartPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 
 //StopPlugin 关闭插件
 func StopPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -389,7 +453,7 @@ func GetPluginListByPluginType(httpResponse http.ResponseWriter, httpRequest *ht
 
 //BatchStopPlugin 批量关闭插件
 func BatchStopPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -413,7 +477,7 @@ func BatchStopPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request
 
 //BatchStartPlugin 批量关闭插件
 func BatchStartPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -437,7 +501,7 @@ func BatchStartPlugin(httpResponse http.ResponseWriter, httpRequest *http.Reques
 
 //CheckPluginIsAvailable 检测插件
 func CheckPluginIsAvailable(httpResponse http.ResponseWriter, httpRequest *http.Request) {
-	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationPlugin, controller.OperationEDIT)
 	if e != nil {
 		return
 	}
@@ -0,0 +1,154 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+)
+
+type apiBody struct {
+	APIName          string          `json:"apiName"`
+	ProjectID        int             `json:"projectID"`
+	GroupID          int             `json:"groupID"`
+	RequestURL       string          `json:"requestURL"`
+	RequestMethod    string          `json:"requestMethod"`
+	Protocol         string          `json:"protocol"`
+	BalanceName      string          `json:"balanceName"`
+	TargetURL        string          `json:"targetURL"`
+	TargetMethod     string          `json:"targetMethod"`
+	IsFollow         bool            `json:"isFollow"`
+	Timeout          int             `json:"timeout"`
+	RetryCount       int             `json:"retryCount"`
+	AlertValve       int             `json:"alertValve"`
+	ManagerID        int             `json:"managerID"`
+	APIType          int             `json:"apiType"`
+	LinkAPIs         json.RawMessage `json:"linkApis"`
+	StaticResponse   string          `json:"staticResponse"`
+	ResponseDataType string          `json:"responseDataType"`
+}
+
+func routeAPI(router *httprouter.Router) {
+	router.GET(Prefix+"projects/:projectID/apis", auth(controller.OperationAPI, getAPIList))
+	router.POST(Prefix+"apis", auth(controller.OperationAPI, addAPI))
+	router.GET(Prefix+"apis/:apiID", auth(controller.OperationAPI, getAPI))
+	router.PUT(Prefix+"apis/:apiID", auth(controller.OperationAPI, editAPI))
+	router.DELETE(Prefix+"apis/:apiID", auth(controller.OperationAPI, deleteAPI))
+}
+
+func (b *apiBody) check() string {
+	if b.APIName == "" {
+		return "apiName is required"
+	}
+	if b.RequestURL == "" || b.RequestMethod == "" {
+		return "requestURL and requestMethod are required"
+	}
+	if b.ResponseDataType == "" {
+		b.ResponseDataType = "origin"
+	}
+	if b.ResponseDataType != "origin" && b.ResponseDataType != "json" && b.ResponseDataType != "xml" {
+		return "illegal responseDataType"
+	}
+	if b.Timeout < 1 {
+		return "illegal timeout"
+	}
+	if b.Protocol == "" {
+		b.Protocol = "http"
+	}
+	return ""
+}
+
+func (b *apiBody) linkAPIs() string {
+	if len(b.LinkAPIs) == 0 || string(b.LinkAPIs) == "null" {
+		return ""
+	}
+	return string(b.LinkAPIs)
+}
+
+func getAPIList(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	projectID, ok := intParam(w, params, "projectID")
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	_, result, count, err := api.GetAPIList(projectID, intQuery(r, "groupID", -1), query.Get("keyword"), 0, intQuery(r, "page", 1), intQuery(r, "pageSize", 15), nil)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeList(w, result, count)
+}
+
+func addAPI(w http.ResponseWriter, r *http.Request, _ httprouter.Params, userID int) {
+	body := new(apiBody)
+	if !decode(w, r, body) {
+		return
+	}
+	if msg := body.check(); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if body.ManagerID == 0 {
+		body.ManagerID = userID
+	}
+	flag, id, err := api.AddAPI(body.APIName, body.RequestURL, body.TargetURL, body.RequestMethod, body.TargetMethod, strconv.FormatBool(body.IsFollow), body.linkAPIs(), body.StaticResponse, body.ResponseDataType, body.BalanceName, body.Protocol, body.ProjectID, body.GroupID, body.Timeout, body.RetryCount, body.AlertValve, body.ManagerID, userID, body.APIType)
+	if !flag {
+		writeResult(w, false, "fail to add api", err, 0, nil)
+		return
+	}
+	writeData(w, http.StatusCreated, map[string]interface{}{"apiID": id})
+}
+
+func getAPI(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	apiID, ok := intParam(w, params, "apiID")
+	if !ok {
+		return
+	}
+	flag, result, err := api.GetAPIInfo(apiID)
+	if !flag {
+		writeGetError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, result)
+}
+
+func editAPI(w http.ResponseWriter, r *http.Request, params httprouter.Params, userID int) {
+	apiID, ok := intParam(w, params, "apiID")
+	if !ok {
+		return
+	}
+	if flag, _ := api.CheckAPIIsExist(apiID); !flag {
+		writeGetError(w, nil)
+		return
+	}
+	body := new(apiBody)
+	if !decode(w, r, body) {
+		return
+	}
+	if msg := body.check(); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if body.ManagerID == 0 {
+		body.ManagerID = userID
+	}
+	flag, err := api.EditAPI(body.APIName, body.RequestURL, body.TargetURL, body.RequestMethod, body.TargetMethod, strconv.FormatBool(body.IsFollow), body.linkAPIs(), body.StaticResponse, body.ResponseDataType, body.BalanceName, body.Protocol, body.ProjectID, body.GroupID, body.Timeout, body.RetryCount, body.AlertValve, apiID, body.ManagerID, userID)
+	writeResult(w, flag, "fail to edit api", err, http.StatusNoContent, nil)
+}
+
+func deleteAPI(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	apiID, ok := intParam(w, params, "apiID")
+	if !ok {
+		return
+	}
+	if flag, _ := api.CheckAPIIsExist(apiID); !flag {
+		writeGetError(w, nil)
+		return
+	}
+	flag, desc, err := api.BatchDeleteAPI(strconv.Itoa(apiID))
+	writeResult(w, flag, desc, err, http.StatusNoContent, nil)
+}
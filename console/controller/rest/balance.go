@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
+)
+
+type balanceBody struct {
+	BalanceName   string            `json:"balanceName"`
+	ServiceName   string            `json:"serviceName"`
+	AppName       string            `json:"appName"`
+	Static        string            `json:"static"`
+	StaticCluster map[string]string `json:"staticCluster"`
+	BalanceDesc   string            `json:"balanceDesc"`
+}
+
+func routeBalance(router *httprouter.Router) {
+	router.GET(Prefix+"balances", auth(controller.OperationLoadBalance, getBalanceList))
+	router.POST(Prefix+"balances", auth(controller.OperationLoadBalance, addBalance))
+	router.GET(Prefix+"balances/:balanceName", auth(controller.OperationLoadBalance, getBalance))
+	router.PUT(Prefix+"balances/:balanceName", auth(controller.OperationLoadBalance, editBalance))
+	router.DELETE(Prefix+"balances/:balanceName", auth(controller.OperationLoadBalance, deleteBalance))
+}
+
+func (b *balanceBody) param() *balance.Param {
+	param := &balance.Param{
+		Name:        b.BalanceName,
+		ServiceName: b.ServiceName,
+		AppName:     b.AppName,
+		Static:      b.Static,
+		Desc:        b.BalanceDesc,
+	}
+	if len(b.StaticCluster) > 0 {
+		data, _ := json.Marshal(b.StaticCluster)
+		param.StaticCluster = string(data)
+	}
+	return param
+}
+
+func getBalanceList(w http.ResponseWriter, r *http.Request, _ httprouter.Params, _ int) {
+	result, err := balance.Search(r.URL.Query().Get("keyword"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeList(w, result, len(result))
+}
+
+func addBalance(w http.ResponseWriter, r *http.Request, _ httprouter.Params, _ int) {
+	body := new(balanceBody)
+	if !decode(w, r, body) {
+		return
+	}
+	if body.BalanceName == "" || body.ServiceName == "" {
+		writeError(w, http.StatusBadRequest, "balanceName and serviceName are required")
+		return
+	}
+	result, err := balance.Add(body.param())
+	if err != nil {
+		writeResult(w, false, result, err, 0, nil)
+		return
+	}
+	writeData(w, http.StatusCreated, map[string]interface{}{"balanceName": body.BalanceName})
+}
+
+func getBalance(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	result, err := balance.Get(params.ByName("balanceName"))
+	if err != nil {
+		writeGetError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, result)
+}
+
+func editBalance(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	name := params.ByName("balanceName")
+	if _, err := balance.Get(name); err != nil {
+		writeGetError(w, err)
+		return
+	}
+	body := new(balanceBody)
+	if !decode(w, r, body) {
+		return
+	}
+	if body.ServiceName == "" {
+		writeError(w, http.StatusBadRequest, "serviceName is required")
+		return
+	}
+	body.BalanceName = name
+	result, err := balance.Save(body.param())
+	writeResult(w, err == nil, result, err, http.StatusNoContent, nil)
+}
+
+func deleteBalance(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	name := params.ByName("balanceName")
+	if _, err := balance.Get(name); err != nil {
+		writeGetError(w, err)
+		return
+	}
+	result, err := balance.Delete(name)
+	writeResult(w, err == nil, result, err, http.StatusNoContent, nil)
+}
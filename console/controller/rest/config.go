@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/declarative"
+)
+
+func routeConfig(router *httprouter.Router) {
+	router.GET(Prefix+"config", auth(controller.OperationGatewayConfig, exportConfig))
+	router.POST(Prefix+"config", auth(controller.OperationGatewayConfig, importConfig))
+}
+
+func exportConfig(w http.ResponseWriter, r *http.Request, _ httprouter.Params, _ int) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = declarative.FormatJSON
+	}
+	data, err := declarative.Export(format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	contentType := "application/x-yaml"
+	if format == declarative.FormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func importConfig(w http.ResponseWriter, r *http.Request, _ httprouter.Params, userID int) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(data) == 0 {
+		writeError(w, http.StatusBadRequest, "config body is required")
+		return
+	}
+	dryRun := r.URL.Query().Get("dryRun")
+	changes, err := declarative.Import(data, dryRun == "1" || dryRun == "true", userID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeList(w, changes, len(changes))
+}
@@ -0,0 +1,106 @@
+package rest
+
+import (
+	SQL "database/sql"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/project"
+)
+
+type projectBody struct {
+	ProjectName string `json:"projectName"`
+}
+
+func routeProject(router *httprouter.Router) {
+	router.GET(Prefix+"projects", auth(controller.OperationAPI, getProjectList))
+	router.POST(Prefix+"projects", auth(controller.OperationAPI, addProject))
+	router.GET(Prefix+"projects/:projectID", auth(controller.OperationAPI, getProject))
+	router.PUT(Prefix+"projects/:projectID", auth(controller.OperationAPI, editProject))
+	router.DELETE(Prefix+"projects/:projectID", auth(controller.OperationAPI, deleteProject))
+}
+
+// writeGetError 查询不到记录时返回404
+func writeGetError(w http.ResponseWriter, err error) {
+	if err == nil || err == SQL.ErrNoRows {
+		writeError(w, http.StatusNotFound, "resource not found")
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+func getProjectList(w http.ResponseWriter, r *http.Request, _ httprouter.Params, _ int) {
+	_, result, err := project.GetProjectList(r.URL.Query().Get("keyword"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeList(w, result, len(result))
+}
+
+func addProject(w http.ResponseWriter, r *http.Request, _ httprouter.Params, _ int) {
+	body := new(projectBody)
+	if !decode(w, r, body) {
+		return
+	}
+	if body.ProjectName == "" {
+		writeError(w, http.StatusBadRequest, "projectName is required")
+		return
+	}
+	flag, result, err := project.AddProject(body.ProjectName)
+	if !flag {
+		desc, _ := result.(string)
+		writeResult(w, false, desc, err, 0, nil)
+		return
+	}
+	writeData(w, http.StatusCreated, map[string]interface{}{"projectID": result})
+}
+
+func getProject(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	projectID, ok := intParam(w, params, "projectID")
+	if !ok {
+		return
+	}
+	flag, result, err := project.GetProjectInfo(projectID)
+	if !flag {
+		writeGetError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, result)
+}
+
+func editProject(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	projectID, ok := intParam(w, params, "projectID")
+	if !ok {
+		return
+	}
+	body := new(projectBody)
+	if !decode(w, r, body) {
+		return
+	}
+	if body.ProjectName == "" {
+		writeError(w, http.StatusBadRequest, "projectName is required")
+		return
+	}
+	if flag, _ := project.CheckProjectIsExist(projectID); !flag {
+		writeGetError(w, nil)
+		return
+	}
+	flag, desc, err := project.EditProject(body.ProjectName, projectID)
+	writeResult(w, flag, desc, err, http.StatusNoContent, nil)
+}
+
+func deleteProject(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	projectID, ok := intParam(w, params, "projectID")
+	if !ok {
+		return
+	}
+	if flag, _ := project.CheckProjectIsExist(projectID); !flag {
+		writeGetError(w, nil)
+		return
+	}
+	flag, desc, err := project.DeleteProject(projectID)
+	writeResult(w, flag, desc, err, http.StatusNoContent, nil)
+}
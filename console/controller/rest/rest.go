@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/account"
+)
+
+//Prefix REST接口路径前缀
+const Prefix = "/api/v1/"
+
+type handle func(w http.ResponseWriter, r *http.Request, params httprouter.Params, userID int)
+
+//Handler REST接口，使用 Authorization: Bearer <token> 认证，请求及响应均为json
+func Handler() http.Handler {
+	router := httprouter.New()
+	router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "resource not found")
+	})
+	router.MethodNotAllowed = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	})
+	router.PanicHandler = func(w http.ResponseWriter, r *http.Request, v interface{}) {
+		log.Error("rest api panic:", v)
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+
+	routeProject(router)
+	routeAPI(router)
+	routeStrategy(router)
+	routeBalance(router)
+	routeVersion(router)
+	routeConfig(router)
+	return router
+}
+
+// auth 校验令牌范围后执行，读请求需要read范围，其余需要edit范围
+func auth(operationType string, h handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		operation := controller.OperationEDIT
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			operation = controller.OperationREAD
+		}
+		token := ""
+		if v := r.Header.Get("Authorization"); strings.HasPrefix(v, "Bearer ") {
+			token = strings.TrimSpace(strings.TrimPrefix(v, "Bearer "))
+		}
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		userID, err := account.CheckAPIToken(token, operationType, operation)
+		switch err {
+		case nil:
+			h(w, r, params, userID)
+		case account.ErrorTokenInvalid:
+			w.Header().Set("WWW-Authenticate", "Bearer error=\"invalid_token\"")
+			writeError(w, http.StatusUnauthorized, err.Error())
+		case account.ErrorTokenForbidden:
+			writeError(w, http.StatusForbidden, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if v == nil {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debug("write rest response error:", err)
+	}
+}
+
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, map[string]interface{}{"data": data})
+}
+
+func writeList(w http.ResponseWriter, data interface{}, total int) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data, "total": total})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"error": strings.TrimPrefix(message, "[ERROR]")})
+}
+
+// writeResult 转换模块返回的 (bool, 描述, error) 结果
+func writeResult(w http.ResponseWriter, flag bool, desc string, err error, status int, data interface{}) {
+	if !flag {
+		if desc == "" && err != nil {
+			desc = err.Error()
+		}
+		writeError(w, http.StatusBadRequest, desc)
+		return
+	}
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	writeData(w, status, data)
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "illegal json body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func intParam(w http.ResponseWriter, params httprouter.Params, name string) (int, bool) {
+	v, err := strconv.Atoi(params.ByName(name))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "illegal "+name)
+		return 0, false
+	}
+	return v, true
+}
+
+func intQuery(r *http.Request, name string, def int) int {
+	v, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,154 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+	"github.com/eolinker/goku-api-gateway/console/module/strategy"
+)
+
+type strategyBody struct {
+	StrategyName string `json:"strategyName"`
+	GroupID      int    `json:"groupID"`
+}
+
+type strategyAPIBody struct {
+	APIIDs []int `json:"apiIDs"`
+}
+
+func routeStrategy(router *httprouter.Router) {
+	router.GET(Prefix+"strategies", auth(controller.OperationStrategy, getStrategyList))
+	router.POST(Prefix+"strategies", auth(controller.OperationStrategy, addStrategy))
+	router.GET(Prefix+"strategies/:strategyID", auth(controller.OperationStrategy, getStrategy))
+	router.PUT(Prefix+"strategies/:strategyID", auth(controller.OperationStrategy, editStrategy))
+	router.DELETE(Prefix+"strategies/:strategyID", auth(controller.OperationStrategy, deleteStrategy))
+	router.GET(Prefix+"strategies/:strategyID/apis", auth(controller.OperationStrategy, getStrategyAPIList))
+	router.POST(Prefix+"strategies/:strategyID/apis", auth(controller.OperationStrategy, addStrategyAPI))
+	router.DELETE(Prefix+"strategies/:strategyID/apis/:apiID", auth(controller.OperationStrategy, deleteStrategyAPI))
+}
+
+// strategyExist 策略不存在时返回404
+func strategyExist(w http.ResponseWriter, params httprouter.Params) (string, bool) {
+	strategyID := params.ByName("strategyID")
+	if flag, _ := strategy.CheckStrategyIsExist(strategyID); !flag {
+		writeGetError(w, nil)
+		return "", false
+	}
+	return strategyID, true
+}
+
+func getStrategyList(w http.ResponseWriter, r *http.Request, _ httprouter.Params, _ int) {
+	_, result, err := strategy.GetStrategyList(intQuery(r, "groupID", -1), r.URL.Query().Get("keyword"), 0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeList(w, result, len(result))
+}
+
+func addStrategy(w http.ResponseWriter, r *http.Request, _ httprouter.Params, _ int) {
+	body := new(strategyBody)
+	if !decode(w, r, body) {
+		return
+	}
+	if body.StrategyName == "" {
+		writeError(w, http.StatusBadRequest, "strategyName is required")
+		return
+	}
+	flag, result, err := strategy.AddStrategy(body.StrategyName, body.GroupID)
+	if !flag {
+		writeResult(w, false, result, err, 0, nil)
+		return
+	}
+	writeData(w, http.StatusCreated, map[string]interface{}{"strategyID": result})
+}
+
+func getStrategy(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	flag, result, err := strategy.GetStrategyInfo(params.ByName("strategyID"))
+	if !flag {
+		writeGetError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, result)
+}
+
+func editStrategy(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	strategyID, ok := strategyExist(w, params)
+	if !ok {
+		return
+	}
+	body := new(strategyBody)
+	if !decode(w, r, body) {
+		return
+	}
+	if body.StrategyName == "" {
+		writeError(w, http.StatusBadRequest, "strategyName is required")
+		return
+	}
+	flag, desc, err := strategy.EditStrategy(strategyID, body.StrategyName, body.GroupID)
+	writeResult(w, flag, desc, err, http.StatusNoContent, nil)
+}
+
+func deleteStrategy(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	strategyID, ok := strategyExist(w, params)
+	if !ok {
+		return
+	}
+	flag, desc, err := strategy.DeleteStrategy(strategyID)
+	writeResult(w, flag, desc, err, http.StatusNoContent, nil)
+}
+
+func getStrategyAPIList(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	strategyID, ok := strategyExist(w, params)
+	if !ok {
+		return
+	}
+	_, result, count, err := api.GetAPIListFromStrategy(strategyID, r.URL.Query().Get("keyword"), 0, intQuery(r, "page", 1), intQuery(r, "pageSize", 15), nil, nil)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeList(w, result, count)
+}
+
+func addStrategyAPI(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	strategyID, ok := strategyExist(w, params)
+	if !ok {
+		return
+	}
+	body := new(strategyAPIBody)
+	if !decode(w, r, body) {
+		return
+	}
+	if len(body.APIIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "apiIDs is required")
+		return
+	}
+	apiList := make([]string, 0, len(body.APIIDs))
+	for _, id := range body.APIIDs {
+		apiList = append(apiList, strconv.Itoa(id))
+	}
+	flag, desc, err := api.AddAPIToStrategy(apiList, strategyID)
+	writeResult(w, flag, desc, err, http.StatusNoContent, nil)
+}
+
+func deleteStrategyAPI(w http.ResponseWriter, r *http.Request, params httprouter.Params, _ int) {
+	strategyID, ok := strategyExist(w, params)
+	if !ok {
+		return
+	}
+	apiID, ok := intParam(w, params, "apiID")
+	if !ok {
+		return
+	}
+	if flag, _, _ := api.CheckIsExistAPIInStrategy(apiID, strategyID); !flag {
+		writeGetError(w, nil)
+		return
+	}
+	flag, desc, err := api.BatchDeleteAPIInStrategy(strconv.Itoa(apiID), strategyID)
+	writeResult(w, flag, desc, err, http.StatusNoContent, nil)
+}
@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
+)
+
+type versionBody struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Remark  string `json:"remark"`
+	Publish bool   `json:"publish"`
+}
+
+func routeVersion(router *httprouter.Router) {
+	router.GET(Prefix+"versions", auth(controller.OperationGatewayConfig, getVersionList))
+	router.POST(Prefix+"versions", auth(controller.OperationGatewayConfig, addVersion))
+	router.POST(Prefix+"versions/:versionID/publish", auth(controller.OperationGatewayConfig, publishVersion))
+}
+
+func getVersionList(w http.ResponseWriter, r *http.Request, _ httprouter.Params, _ int) {
+	result, err := versionConfig.GetVersionList(r.URL.Query().Get("keyword"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeList(w, result, len(result))
+}
+
+func addVersion(w http.ResponseWriter, r *http.Request, _ httprouter.Params, userID int) {
+	body := new(versionBody)
+	if !decode(w, r, body) {
+		return
+	}
+	if body.Name == "" || body.Version == "" {
+		writeError(w, http.StatusBadRequest, "name and version are required")
+		return
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	id, err := versionConfig.AddVersionConfig(body.Name, body.Version, body.Remark, now)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.Publish {
+		if err := versionConfig.PublishVersion(id, userID, now); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	writeData(w, http.StatusCreated, map[string]interface{}{"versionID": id})
+}
+
+func publishVersion(w http.ResponseWriter, r *http.Request, params httprouter.Params, userID int) {
+	id, ok := intParam(w, params, "versionID")
+	if !ok {
+		return
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	if err := versionConfig.PublishVersion(id, userID, now); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/console/controller"
 	"github.com/eolinker/goku-api-gateway/console/module/api"
 	"github.com/eolinker/goku-api-gateway/console/module/strategy"
@@ -91,6 +92,92 @@ func ResetAPITargetOfStrategy(httpResponse http.ResponseWriter, httpRequest *htt
 
 }
 
+// SetAPICanaryOfStrategy 设置策略接口灰度配置
+func SetAPICanaryOfStrategy(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationStrategy, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	strategyID := httpRequest.PostFormValue("strategyID")
+	apiID := httpRequest.PostFormValue("apiID")
+	canary := httpRequest.PostFormValue("canary")
+	aID, err := strconv.Atoi(apiID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"240014",
+			"apiStrategy",
+			"[ERROR]Illegal apiID!",
+			err)
+		return
+	}
+	var canaryConfig *config.CanaryConfig
+	if canary != "" {
+		canaryConfig = new(config.CanaryConfig)
+		err = json.Unmarshal([]byte(canary), canaryConfig)
+		if err != nil {
+			controller.WriteError(httpResponse,
+				"240015",
+				"apiStrategy",
+				"[ERROR]Illegal canary!",
+				err)
+			return
+		}
+	}
+	flag, err := strategy.CheckStrategyIsExist(strategyID)
+	if !flag {
+		controller.WriteError(httpResponse,
+			"240013",
+			"apiStrategy",
+			"[ERROR]The strategy does not exist!",
+			err)
+		return
+
+	}
+	flag, result, err := api.SetCanary(aID, strategyID, canaryConfig)
+	if !flag {
+		controller.WriteError(httpResponse,
+			"240000",
+			"apiStrategy",
+			result,
+			err)
+		return
+
+	}
+	controller.WriteResultInfo(httpResponse, "apiStrategy", "", nil)
+}
+
+// GetAPICanaryOfStrategy 获取策略接口灰度配置
+func GetAPICanaryOfStrategy(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationStrategy, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+
+	httpRequest.ParseForm()
+	strategyID := httpRequest.Form.Get("strategyID")
+	apiID := httpRequest.Form.Get("apiID")
+	aID, err := strconv.Atoi(apiID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"240014",
+			"apiStrategy",
+			"[ERROR]Illegal apiID!",
+			err)
+		return
+	}
+	flag, result, err := api.GetCanary(aID, strategyID)
+	if !flag && err != nil {
+		controller.WriteError(httpResponse,
+			"240000",
+			"apiStrategy",
+			"[ERROR]The api does not exist in strategy!",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "apiStrategy", "canary", result)
+}
+
 // BatchResetAPITargetOfStrategy 将接口加入策略组
 func BatchResetAPITargetOfStrategy(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationStrategy, controller.OperationEDIT)
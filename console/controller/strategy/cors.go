@@ -0,0 +1,59 @@
+package strategy
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/strategy"
+)
+
+//EditStrategyCORS 编辑策略跨域配置
+func EditStrategyCORS(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationStrategy, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	strategyID := httpRequest.PostFormValue("strategyID")
+	cors := httpRequest.PostFormValue("cors")
+
+	var corsConfig *config.CORSConfig
+	if cors != "" {
+		corsConfig = new(config.CORSConfig)
+		err := json.Unmarshal([]byte(cors), corsConfig)
+		if err != nil {
+			controller.WriteError(httpResponse, "220008", "strategy", "[ERROR]Illegal cors!", err)
+			return
+		}
+	}
+	flag, err := strategy.CheckStrategyIsExist(strategyID)
+	if !flag {
+		controller.WriteError(httpResponse, "220000", "strategy", "[ERROR]Can not find the strategy!", err)
+		return
+	}
+	flag, result, err := strategy.SetCORS(strategyID, corsConfig)
+	if !flag {
+		controller.WriteError(httpResponse, "220008", "strategy", result, err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "strategy", "", nil)
+}
+
+//GetStrategyCORS 获取策略跨域配置
+func GetStrategyCORS(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationStrategy, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+
+	strategyID := httpRequest.Form.Get("strategyID")
+	flag, result, err := strategy.GetCORS(strategyID)
+	if !flag {
+		controller.WriteError(httpResponse, "220000", "strategy", "[ERROR]Can not find the strategy!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "strategy", "cors", result)
+}
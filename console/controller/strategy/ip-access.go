@@ -0,0 +1,59 @@
+package strategy
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/strategy"
+)
+
+//EditStrategyIPAccess 编辑策略IP黑白名单
+func EditStrategyIPAccess(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckPermission(httpResponse, httpRequest, controller.OperationStrategy, controller.OperationEDIT)
+	if e != nil {
+		return
+	}
+
+	strategyID := httpRequest.PostFormValue("strategyID")
+	ipAccess := httpRequest.PostFormValue("ipAccess")
+
+	var ipAccessConfig *config.IPAccessConfig
+	if ipAccess != "" {
+		ipAccessConfig = new(config.IPAccessConfig)
+		err := json.Unmarshal([]byte(ipAccess), ipAccessConfig)
+		if err != nil {
+			controller.WriteError(httpResponse, "220009", "strategy", "[ERROR]Illegal ip access!", err)
+			return
+		}
+	}
+	flag, err := strategy.CheckStrategyIsExist(strategyID)
+	if !flag {
+		controller.WriteError(httpResponse, "220000", "strategy", "[ERROR]Can not find the strategy!", err)
+		return
+	}
+	flag, result, err := strategy.SetIPAccess(strategyID, ipAccessConfig)
+	if !flag {
+		controller.WriteError(httpResponse, "220009", "strategy", result, err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "strategy", "", nil)
+}
+
+//GetStrategyIPAccess 获取策略IP黑白名单
+func GetStrategyIPAccess(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	_, e := controller.CheckLogin(httpResponse, httpRequest, controller.OperationStrategy, controller.OperationREAD)
+	if e != nil {
+		return
+	}
+	httpRequest.ParseForm()
+
+	strategyID := httpRequest.Form.Get("strategyID")
+	flag, result, err := strategy.GetIPAccess(strategyID)
+	if !flag {
+		controller.WriteError(httpResponse, "220000", "strategy", "[ERROR]Can not find the strategy!", err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "strategy", "ipAccess", result)
+}
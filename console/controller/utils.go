@@ -37,6 +37,9 @@ const (
 	OperationGatewayConfig = "gatewayConfig"
 )
 
+//OperationTypes 全部操作类型，用于校验令牌范围
+var OperationTypes = []string{OperationAPI, OperationADMIN, OperationLoadBalance, OperationStrategy, OperationNode, OperationPlugin, OperationGatewayConfig}
+
 //PageInfo 页码信息
 type PageInfo struct {
 	ItemNum  int `json:"itemNum,"`
@@ -161,3 +164,41 @@ func CheckLogin(w http.ResponseWriter, r *http.Request, operationType, operation
 
 	return userID, nil
 }
+
+//CheckPermission 判断是否登录并拥有指定操作权限
+func CheckPermission(w http.ResponseWriter, r *http.Request, operationType, operation string) (int, error) {
+	userID, err := CheckLogin(w, r, operationType, operation)
+	if err != nil {
+		return userID, err
+	}
+	flag, desc, err := account.CheckUserPermission(operationType, operation, userID)
+	if !flag {
+		if desc == "" {
+			desc = "[ERROR]No permissions!"
+		}
+		e := errors.New("permission denied")
+		WriteError(w, "100002", "user", desc, err)
+		return userID, e
+	}
+	return userID, nil
+}
+
+//CheckPermissions 判断是否登录并拥有全部操作类型的指定操作权限，用于同时修改多类资源的操作
+func CheckPermissions(w http.ResponseWriter, r *http.Request, operation string, operationTypes ...string) (int, error) {
+	userID, err := CheckLogin(w, r, OperationNone, operation)
+	if err != nil {
+		return userID, err
+	}
+	for _, operationType := range operationTypes {
+		flag, desc, err := account.CheckUserPermission(operationType, operation, userID)
+		if !flag {
+			if desc == "" {
+				desc = "[ERROR]No permissions!"
+			}
+			e := errors.New("permission denied")
+			WriteError(w, "100002", "user", desc, err)
+			return userID, e
+		}
+	}
+	return userID, nil
+}
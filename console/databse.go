@@ -2,14 +2,18 @@ package console
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 
 	"github.com/eolinker/goku-api-gateway/common/database"
 	"github.com/eolinker/goku-api-gateway/server/entity"
 )
 
 const (
-	mysqlDriver   = "mysql"
-	sqlite3Driver = "sqlite3"
+	mysqlDriver    = "mysql"
+	sqlite3Driver  = "sqlite3"
+	postgresDriver = "postgres"
 )
 
 //ClusterDatabaseConfig 集群数据库配置
@@ -32,6 +36,19 @@ func (c *ClusterDatabaseConfig) GetSource() string {
 		{
 			return c.Path
 		}
+	case postgresDriver:
+		{
+			u := url.URL{
+				Scheme: "postgres",
+				User:   url.UserPassword(c.UserName, c.Password),
+				Host:   net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+				Path:   "/" + c.Database,
+			}
+			if c.SSLMode != "" {
+				u.RawQuery = url.Values{"sslmode": {c.SSLMode}}.Encode()
+			}
+			return u.String()
+		}
 	default:
 		{
 			return ""
@@ -53,6 +70,12 @@ func InitDatabase() {
 	}
 }
 
-func InitTable() error {
-	return database.InitTable()
+//Migrate 升级数据库表结构，dryRun为true时只返回待执行的迁移
+func Migrate(dryRun bool) ([]*database.Migration, error) {
+	return database.Migrate(dryRun)
+}
+
+//GetMigrationStatus 获取数据库表结构版本
+func GetMigrationStatus() (*database.MigrationStatus, error) {
+	return database.GetMigrationStatus()
 }
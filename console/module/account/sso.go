@@ -0,0 +1,335 @@
+package account
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/conf"
+	"github.com/eolinker/goku-api-gateway/common/ldap"
+	"github.com/eolinker/goku-api-gateway/common/oidc"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"github.com/eolinker/goku-api-gateway/utils"
+)
+
+var (
+	//ErrorSSODisabled 未配置单点登录
+	ErrorSSODisabled = errors.New("single sign-on is not enabled")
+	//ErrorSSOCredentials 目录账号或密码错误
+	ErrorSSOCredentials = errors.New("wrong username or password")
+	//ErrorSSOConflict 登录名已被其它来源的账号占用
+	ErrorSSOConflict = errors.New("login name is used by another account")
+	//ErrorSSONoGroup 目录分组未映射到任何权限分组
+	ErrorSSONoGroup = errors.New("no permission group is mapped for this user")
+)
+
+//LDAPConfig LDAP登录配置
+type LDAPConfig struct {
+	URL          string
+	BindDN       string
+	BindPassword string
+	BaseDN       string
+	// UserFilter 用户搜索过滤器，{username}替换为登录名
+	UserFilter  string
+	GroupBaseDN string
+	// GroupFilter 分组搜索过滤器，{dn}替换为用户DN，{username}替换为登录名，为空时只读取memberOf
+	GroupFilter  string
+	GroupMapping map[string]string
+	DefaultGroup string
+	Timeout      time.Duration
+}
+
+//OIDCConfig OIDC登录配置
+type OIDCConfig struct {
+	oidc.Config
+	UsernameClaim string
+	GroupsClaim   string
+	GroupMapping  map[string]string
+	DefaultGroup  string
+}
+
+//LoadLDAPConfig 读取LDAP配置，未配置ldap_url时返回nil
+func LoadLDAPConfig() *LDAPConfig {
+	url := conf.Value("ldap_url")
+	if url == "" {
+		return nil
+	}
+	baseDN := conf.Value("ldap_base_dn")
+	return &LDAPConfig{
+		URL:          url,
+		BindDN:       conf.Value("ldap_bind_dn"),
+		BindPassword: conf.Value("ldap_bind_password"),
+		BaseDN:       baseDN,
+		UserFilter:   conf.MastValue("ldap_user_filter", "(uid={username})"),
+		GroupBaseDN:  conf.MastValue("ldap_group_base_dn", baseDN),
+		GroupFilter:  conf.Value("ldap_group_filter"),
+		GroupMapping: groupMapping("ldap_group_mapping"),
+		DefaultGroup: conf.Value("sso_default_group"),
+		Timeout:      10 * time.Second,
+	}
+}
+
+//LoadOIDCConfig 读取OIDC配置，未配置oidc_issuer时返回nil
+func LoadOIDCConfig() *OIDCConfig {
+	issuer := conf.Value("oidc_issuer")
+	if issuer == "" {
+		return nil
+	}
+	return &OIDCConfig{
+		Config: oidc.Config{
+			Issuer:       issuer,
+			ClientID:     conf.Value("oidc_client_id"),
+			ClientSecret: conf.Value("oidc_client_secret"),
+			RedirectURL:  conf.Value("oidc_redirect_url"),
+			Scopes:       strings.Fields(conf.Value("oidc_scopes")),
+		},
+		UsernameClaim: conf.MastValue("oidc_username_claim", "preferred_username"),
+		GroupsClaim:   conf.MastValue("oidc_groups_claim", "groups"),
+		GroupMapping:  groupMapping("oidc_group_mapping"),
+		DefaultGroup:  conf.Value("sso_default_group"),
+	}
+}
+
+func groupMapping(name string) map[string]string {
+	mapping := make(map[string]string)
+	if v := conf.Value(name); v != "" {
+		if err := json.Unmarshal([]byte(v), &mapping); err != nil {
+			log.Warn("illegal ", name, ":", err)
+		}
+	}
+	return mapping
+}
+
+//AuthenticateLDAP 使用服务账号搜索用户并以用户身份绑定，返回用户所属的目录分组
+func AuthenticateLDAP(config *LDAPConfig, loginCall, password string) ([]string, error) {
+	if loginCall == "" || password == "" {
+		return nil, ErrorSSOCredentials
+	}
+	conn, err := ldap.Dial(config.URL, config.Timeout, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.Bind(config.BindDN, config.BindPassword); err != nil {
+		return nil, err
+	}
+	replacer := strings.NewReplacer("{username}", ldap.EscapeFilter(loginCall))
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     config.BaseDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     replacer.Replace(config.UserFilter),
+		Attributes: []string{"memberOf"},
+		SizeLimit:  2,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != 1 {
+		return nil, ErrorSSOCredentials
+	}
+	user := entries[0]
+	if err := conn.Bind(user.DN, password); err != nil {
+		if ldap.IsInvalidCredentials(err) {
+			return nil, ErrorSSOCredentials
+		}
+		return nil, err
+	}
+
+	groups := make([]string, 0)
+	for _, dn := range user.Values("memberOf") {
+		groups = append(groups, commonName(dn))
+	}
+	if config.GroupFilter == "" {
+		return groups, nil
+	}
+	// 以服务账号身份搜索分组，用户本身可能没有读取分组的权限
+	if err := conn.Bind(config.BindDN, config.BindPassword); err != nil {
+		return nil, err
+	}
+	replacer = strings.NewReplacer("{dn}", ldap.EscapeFilter(user.DN), "{username}", ldap.EscapeFilter(loginCall))
+	entries, err = conn.Search(&ldap.SearchRequest{
+		BaseDN:     config.GroupBaseDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     replacer.Replace(config.GroupFilter),
+		Attributes: []string{"cn"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if cn := e.Get("cn"); cn != "" {
+			groups = append(groups, cn)
+		} else {
+			groups = append(groups, commonName(e.DN))
+		}
+	}
+	return groups, nil
+}
+
+//commonName 取DN中第一个RDN的值，如 cn=ops,ou=groups,dc=example,dc=com 返回 ops
+func commonName(dn string) string {
+	rdn := strings.SplitN(dn, ",", 2)[0]
+	if i := strings.IndexByte(rdn, '='); i >= 0 {
+		return strings.TrimSpace(rdn[i+1:])
+	}
+	return strings.TrimSpace(rdn)
+}
+
+//LoginLDAP LDAP登录，返回用户ID及会话令牌
+func LoginLDAP(loginCall, password string) (int, string, error) {
+	config := LoadLDAPConfig()
+	if config == nil {
+		return 0, "", ErrorSSODisabled
+	}
+	groups, err := AuthenticateLDAP(config, loginCall, password)
+	if err != nil {
+		return 0, "", err
+	}
+	return provisionUser(entity.UserSourceLDAP, loginCall, MapGroups(groups, config.GroupMapping, config.DefaultGroup))
+}
+
+var (
+	providerLocker sync.Mutex
+	provider       *oidc.Provider
+	providerConfig oidc.Config
+)
+
+func oidcProvider(config *OIDCConfig) (*oidc.Provider, error) {
+	providerLocker.Lock()
+	defer providerLocker.Unlock()
+	if provider != nil && providerConfig.Issuer == config.Issuer && providerConfig.ClientID == config.ClientID &&
+		providerConfig.ClientSecret == config.ClientSecret && providerConfig.RedirectURL == config.RedirectURL {
+		return provider, nil
+	}
+	p, err := oidc.NewProvider(config.Config, nil)
+	if err != nil {
+		return nil, err
+	}
+	provider, providerConfig = p, config.Config
+	return p, nil
+}
+
+//OIDCAuthURL 获取OIDC认证地址
+func OIDCAuthURL(state, nonce string) (string, error) {
+	config := LoadOIDCConfig()
+	if config == nil {
+		return "", ErrorSSODisabled
+	}
+	p, err := oidcProvider(config)
+	if err != nil {
+		return "", err
+	}
+	return p.AuthCodeURL(state, nonce), nil
+}
+
+//LoginOIDC 使用授权码完成OIDC登录，返回用户ID及会话令牌
+func LoginOIDC(code, nonce string) (int, string, error) {
+	config := LoadOIDCConfig()
+	if config == nil {
+		return 0, "", ErrorSSODisabled
+	}
+	p, err := oidcProvider(config)
+	if err != nil {
+		return 0, "", err
+	}
+	claims, err := p.Exchange(code, nonce)
+	if err != nil {
+		return 0, "", err
+	}
+	loginCall := claims.String(config.UsernameClaim)
+	if loginCall == "" {
+		return 0, "", errors.New("id_token has no claim " + config.UsernameClaim)
+	}
+	return provisionUser(entity.UserSourceOIDC, loginCall, MapGroups(claims.Strings(config.GroupsClaim), config.GroupMapping, config.DefaultGroup))
+}
+
+//MapGroups 将目录分组映射为权限分组名称，未配置映射时按同名匹配，均未匹配时使用默认分组
+func MapGroups(groups []string, mapping map[string]string, defaultGroup string) []string {
+	names := make([]string, 0, len(groups))
+	exist := make(map[string]bool)
+	for _, g := range groups {
+		name := g
+		if len(mapping) > 0 {
+			name = mapping[g]
+		}
+		if name == "" || exist[name] {
+			continue
+		}
+		exist[name] = true
+		names = append(names, name)
+	}
+	if len(names) == 0 && defaultGroup != "" {
+		names = append(names, defaultGroup)
+	}
+	return names
+}
+
+//mergePermissions 合并多个权限分组的权限，任一分组允许即允许
+func mergePermissions(groups []*entity.PermissionGroup) (string, error) {
+	merged := make(map[string]map[string]bool)
+	for _, g := range groups {
+		if g.Permissions == "" {
+			continue
+		}
+		permissions := make(map[string]map[string]bool)
+		if err := json.Unmarshal([]byte(g.Permissions), &permissions); err != nil {
+			return "", err
+		}
+		for operationType, operations := range permissions {
+			if merged[operationType] == nil {
+				merged[operationType] = make(map[string]bool)
+			}
+			for operation, v := range operations {
+				merged[operationType][operation] = merged[operationType][operation] || v
+			}
+		}
+	}
+	b, err := json.Marshal(merged)
+	return string(b), err
+}
+
+//provisionUser 首次登录时创建用户，之后每次登录同步权限分组
+func provisionUser(source, loginCall string, groupNames []string) (int, string, error) {
+	groups, err := console_sqlite3.GetPermissionGroupByName(groupNames)
+	if err != nil {
+		return 0, "", err
+	}
+	if len(groups) == 0 {
+		return 0, "", ErrorSSONoGroup
+	}
+	permissions, err := mergePermissions(groups)
+	if err != nil {
+		return 0, "", err
+	}
+	userID, password, userSource, err := console_sqlite3.GetUserByLoginCall(loginCall)
+	if err != nil {
+		return 0, "", err
+	}
+	if userID != 0 {
+		if userSource != source {
+			return 0, "", ErrorSSOConflict
+		}
+		if err := console_sqlite3.UpdateSSOUser(userID, groups[0].GroupID, permissions); err != nil {
+			return 0, "", err
+		}
+		return userID, utils.Md5(loginCall + password), nil
+	}
+	// 目录用户不使用本地密码，随机生成仅用于计算会话令牌
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return 0, "", err
+	}
+	password = hex.EncodeToString(b)
+	userID, err = console_sqlite3.AddSSOUser(loginCall, password, source, groups[0].GroupID, permissions)
+	if err != nil {
+		return 0, "", err
+	}
+	log.Info("provision ", source, " user ", loginCall)
+	return userID, utils.Md5(loginCall + password), nil
+}
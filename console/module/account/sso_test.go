@@ -0,0 +1,76 @@
+package account
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/ldap/ldaptest"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func TestAuthenticateLDAP(t *testing.T) {
+	s := ldaptest.NewServer()
+	defer s.Close()
+	s.AddEntry("cn=admin,dc=example,dc=com", "admin", nil)
+	s.AddEntry("uid=alice,ou=people,dc=example,dc=com", "secret", map[string][]string{
+		"uid":      {"alice"},
+		"memberOf": {"cn=ops,ou=groups,dc=example,dc=com"},
+	})
+	s.AddEntry("cn=dev,ou=groups,dc=example,dc=com", "", map[string][]string{
+		"cn":     {"dev"},
+		"member": {"uid=alice,ou=people,dc=example,dc=com"},
+	})
+	config := &LDAPConfig{
+		URL:          s.URL,
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+		BaseDN:       "ou=people,dc=example,dc=com",
+		UserFilter:   "(uid={username})",
+		GroupBaseDN:  "ou=groups,dc=example,dc=com",
+		GroupFilter:  "(member={dn})",
+		Timeout:      time.Second,
+	}
+	groups, err := AuthenticateLDAP(config, "alice", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(groups, []string{"ops", "dev"}) {
+		t.Errorf("unexpected groups %v", groups)
+	}
+	for _, c := range [][2]string{{"alice", "wrong"}, {"alice", ""}, {"bob", "secret"}, {"*", "secret"}} {
+		if _, err := AuthenticateLDAP(config, c[0], c[1]); err != ErrorSSOCredentials {
+			t.Errorf("%v: expect credentials error, got %v", c, err)
+		}
+	}
+}
+
+func TestMapGroups(t *testing.T) {
+	if names := MapGroups([]string{"ops", "dev"}, nil, ""); !reflect.DeepEqual(names, []string{"ops", "dev"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	mapping := map[string]string{"ops": "admin", "sre": "admin"}
+	if names := MapGroups([]string{"ops", "sre", "dev"}, mapping, "guest"); !reflect.DeepEqual(names, []string{"admin"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	if names := MapGroups([]string{"dev"}, mapping, "guest"); !reflect.DeepEqual(names, []string{"guest"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	if names := MapGroups(nil, mapping, ""); len(names) != 0 {
+		t.Errorf("unexpected names %v", names)
+	}
+}
+
+func TestMergePermissions(t *testing.T) {
+	permissions, err := mergePermissions([]*entity.PermissionGroup{
+		{Permissions: `{"apiManagement":{"read":true,"edit":false}}`},
+		{Permissions: `{"apiManagement":{"edit":true},"nodeManagement":{"read":true}}`},
+		{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if permissions != `{"apiManagement":{"edit":true,"read":true},"nodeManagement":{"read":true}}` {
+		t.Errorf("unexpected permissions %s", permissions)
+	}
+}
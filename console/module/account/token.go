@@ -0,0 +1,138 @@
+package account
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	tokenPrefix = "gk_"
+	timeFormat  = "2006-01-02 15:04:05"
+)
+
+var (
+	//ErrorTokenInvalid 令牌不存在或已过期
+	ErrorTokenInvalid = errors.New("invalid or expired token")
+	//ErrorTokenForbidden 令牌范围或用户权限不足
+	ErrorTokenForbidden = errors.New("insufficient token scope or permission")
+
+	errorTokenType   = errors.New("illegal token type")
+	errorTokenScope  = errors.New("illegal token scope")
+	errorServiceUser = errors.New("only admin can create service token")
+)
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+//AddAPIToken 新增接口访问令牌，令牌明文仅在创建时返回
+func AddAPIToken(token *entity.APIToken, operationTypes []string) (string, error) {
+	switch token.TokenType {
+	case entity.TokenPersonal:
+	case entity.TokenService:
+		if flag, _, _ := console_sqlite3.CheckUserIsAdmin(token.UserID); !flag {
+			return "", errorServiceUser
+		}
+	default:
+		return "", errorTokenType
+	}
+	types := make(map[string]bool)
+	for _, t := range operationTypes {
+		types[t] = true
+	}
+	for _, scope := range token.Scopes {
+		i := strings.LastIndex(scope, ":")
+		if i < 0 || !types[scope[:i]] || (scope[i+1:] != "read" && scope[i+1:] != "edit") {
+			return "", errorTokenScope
+		}
+	}
+	if len(token.Scopes) == 0 {
+		return "", errorTokenScope
+	}
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	value := tokenPrefix + hex.EncodeToString(b)
+	token.CreateTime = time.Now().Format(timeFormat)
+	id, err := console_sqlite3.AddAPIToken(token, hashToken(value))
+	if err != nil {
+		return "", err
+	}
+	token.TokenID = id
+	return value, nil
+}
+
+//CheckAPIToken 校验令牌及其对指定操作的权限，返回令牌所属用户
+func CheckAPIToken(value, operationType, operation string) (int, error) {
+	if !strings.HasPrefix(value, tokenPrefix) {
+		return 0, ErrorTokenInvalid
+	}
+	has, token, err := console_sqlite3.GetAPITokenByHash(hashToken(value))
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().Format(timeFormat)
+	if !has || (token.ExpireTime != "" && token.ExpireTime < now) {
+		return 0, ErrorTokenInvalid
+	}
+	if !HasScope(token.Scopes, operationType, operation) {
+		return token.UserID, ErrorTokenForbidden
+	}
+	if token.TokenType == entity.TokenPersonal {
+		if flag, _, _ := console_sqlite3.CheckUserPermission(operationType, operation, token.UserID); !flag {
+			return token.UserID, ErrorTokenForbidden
+		}
+	}
+	if err := console_sqlite3.SetAPITokenUsedTime(token.TokenID, now); err != nil {
+		log.Warn("update token used time error:", err)
+	}
+	return token.UserID, nil
+}
+
+//GetAPITokenUserID 获取令牌所属用户，不校验令牌范围，令牌无效时返回0
+func GetAPITokenUserID(value string) int {
+	if !strings.HasPrefix(value, tokenPrefix) {
+		return 0
+	}
+	has, token, err := console_sqlite3.GetAPITokenByHash(hashToken(value))
+	if err != nil || !has {
+		return 0
+	}
+	return token.UserID
+}
+
+//HasScope 判断令牌范围是否包含指定操作，edit范围包含read
+func HasScope(scopes []string, operationType, operation string) bool {
+	for _, scope := range scopes {
+		if scope == operationType+":"+operation || (operation == "read" && scope == operationType+":edit") {
+			return true
+		}
+	}
+	return false
+}
+
+//GetAPITokenList 获取令牌列表，管理员可查看全部令牌
+func GetAPITokenList(userID int) ([]*entity.APIToken, error) {
+	if flag, _, _ := console_sqlite3.CheckUserIsAdmin(userID); flag {
+		return console_sqlite3.GetAPITokenList(0)
+	}
+	return console_sqlite3.GetAPITokenList(userID)
+}
+
+//DeleteAPIToken 删除令牌，管理员可删除任意令牌
+func DeleteAPIToken(tokenID, userID int) (bool, error) {
+	if flag, _, _ := console_sqlite3.CheckUserIsAdmin(userID); flag {
+		return console_sqlite3.DeleteAPIToken(tokenID, 0)
+	}
+	return console_sqlite3.DeleteAPIToken(tokenID, userID)
+}
@@ -0,0 +1,34 @@
+package account
+
+import (
+	"testing"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func TestHasScope(t *testing.T) {
+	scopes := []string{"apiManagement:edit", "nodeManagement:read"}
+	if !HasScope(scopes, "apiManagement", "read") || !HasScope(scopes, "apiManagement", "edit") {
+		t.Error("edit scope should allow read and edit")
+	}
+	if !HasScope(scopes, "nodeManagement", "read") || HasScope(scopes, "nodeManagement", "edit") {
+		t.Error("read scope should only allow read")
+	}
+	if HasScope(scopes, "strategyManagement", "read") {
+		t.Error("unlisted type should be denied")
+	}
+}
+
+func TestAddAPITokenScope(t *testing.T) {
+	types := []string{"apiManagement"}
+	for _, scopes := range [][]string{nil, {"apiManagement"}, {"apiManagement:write"}, {"nodeManagement:read"}} {
+		token := &entity.APIToken{TokenName: "t", TokenType: entity.TokenPersonal, Scopes: scopes}
+		if _, err := AddAPIToken(token, types); err != errorTokenScope {
+			t.Errorf("%v: expect scope error, got %v", scopes, err)
+		}
+	}
+	token := &entity.APIToken{TokenName: "t", TokenType: "other", Scopes: []string{"apiManagement:read"}}
+	if _, err := AddAPIToken(token, types); err != errorTokenType {
+		t.Errorf("expect type error, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package alert
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//RuleAPIError 接口失败次数，不在网关成功状态码内的请求视为失败
+	RuleAPIError = "apiError"
+	//RuleStatus5xxRate 接口5xx状态码比例，阈值为百分比
+	RuleStatus5xxRate = "status5xxRate"
+	//RuleProxyTimeout 转发超时次数
+	RuleProxyTimeout = "proxyTimeout"
+	//RuleNodeLost 节点心跳丢失
+	RuleNodeLost = "nodeLost"
+)
+
+var (
+	errorRuleType   = errors.New("illegal ruleType, expect apiError, status5xxRate, proxyTimeout or nodeLost")
+	errorThreshold  = errors.New("illegal threshold")
+	errorPeriod     = errors.New("illegal period, expect 1 to 60 minutes")
+	errorCooldown   = errors.New("illegal cooldown, expect at least 1 minute")
+	errorNotify     = errors.New("receivers or webhookURL is required")
+	errorWebhookURL = errors.New("illegal webhookURL")
+)
+
+var (
+	defaultEngine = newEngine(time.Now())
+	startOnce     sync.Once
+)
+
+//Start 启动告警检查
+func Start() {
+	startOnce.Do(func() {
+		go defaultEngine.run()
+	})
+}
+
+//Observe 记录节点上报的监控计数，用于告警规则的统计
+func Observe(clusterID int, items []*config.MonitorItem) {
+	defaultEngine.window.add(clusterID, items, time.Now())
+}
+
+//CheckRule 检查告警规则
+func CheckRule(rule *entity.AlertRule) error {
+	switch rule.RuleType {
+	case RuleAPIError, RuleProxyTimeout:
+		if rule.Threshold < 1 {
+			return errorThreshold
+		}
+	case RuleStatus5xxRate:
+		if rule.Threshold < 1 || rule.Threshold > 100 {
+			return errorThreshold
+		}
+	case RuleNodeLost:
+		rule.Threshold = 0
+	default:
+		return errorRuleType
+	}
+	if rule.Period < 1 || rule.Period > maxPeriod {
+		return errorPeriod
+	}
+	if rule.Cooldown < 1 {
+		return errorCooldown
+	}
+	if rule.Receivers == "" && rule.WebhookURL == "" {
+		return errorNotify
+	}
+	if rule.WebhookURL != "" {
+		u, err := url.Parse(rule.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errorWebhookURL
+		}
+	}
+	_, err := parseTemplate(rule.WebhookTemplate)
+	return err
+}
+
+//AddRule 新增告警规则
+func AddRule(rule *entity.AlertRule) (int, error) {
+	if err := CheckRule(rule); err != nil {
+		return 0, err
+	}
+	now := time.Now().Format(timeFormat)
+	rule.CreateTime, rule.UpdateTime = now, now
+	return console_sqlite3.AddAlertRule(rule)
+}
+
+//EditRule 编辑告警规则
+func EditRule(rule *entity.AlertRule) (bool, error) {
+	if err := CheckRule(rule); err != nil {
+		return false, err
+	}
+	rule.UpdateTime = time.Now().Format(timeFormat)
+	return console_sqlite3.EditAlertRule(rule)
+}
+
+//DeleteRule 删除告警规则
+func DeleteRule(ruleID int) error {
+	return console_sqlite3.DeleteAlertRule(ruleID)
+}
+
+//GetRule 获取告警规则
+func GetRule(ruleID int) (bool, *entity.AlertRule, error) {
+	return console_sqlite3.GetAlertRule(ruleID)
+}
+
+//GetRuleList 获取告警规则列表
+func GetRuleList() ([]*entity.AlertRule, error) {
+	return console_sqlite3.GetAlertRuleList(false)
+}
+
+//GetHistoryList 获取告警记录列表
+func GetHistoryList(cond *entity.AlertHistoryCondition, page, pageSize int) ([]*entity.AlertHistory, int, error) {
+	return console_sqlite3.GetAlertHistoryList(cond, page, pageSize)
+}
+
+//GetSender 获取告警邮件发送配置，不返回密码
+func GetSender() (*entity.AlertSender, error) {
+	sender, err := console_sqlite3.GetAlertSender()
+	if err != nil {
+		return nil, err
+	}
+	sender.SenderPassword = ""
+	return sender, nil
+}
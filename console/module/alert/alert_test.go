@@ -0,0 +1,83 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func TestWindow(t *testing.T) {
+	now := time.Unix(6000*60, 0)
+	w := newWindow()
+	w.add(1, []*config.MonitorItem{{StrategyID: "s1", APIID: 1, GatewayRequestCount: 10, GatewaySuccessCount: 6, GatewayStatus5xxCount: 3, ProxyTimeoutCount: 2}}, now.Add(-10*time.Minute))
+	w.add(1, []*config.MonitorItem{{StrategyID: "s1", APIID: 1, GatewayRequestCount: 4, GatewaySuccessCount: 4}}, now)
+	w.add(2, []*config.MonitorItem{{StrategyID: "s1", APIID: 2, GatewayRequestCount: 2, GatewaySuccessCount: 0, GatewayStatus5xxCount: 2}}, now)
+
+	rule := &entity.AlertRule{RuleType: RuleStatus5xxRate, Threshold: 20, Period: 15}
+	sums := w.sumByAPI(rule, now)
+	if len(sums) != 2 || sums[1].requests != 14 || sums[1].failures != 4 {
+		t.Fatalf("unexpected sums %+v", sums)
+	}
+	if value, _, ok := metric(rule, sums[1]); !ok || value != 21.43 {
+		t.Errorf("unexpected 5xx rate %v %v", value, ok)
+	}
+	rule.Period = 5
+	rule.ClusterID = 1
+	if sums = w.sumByAPI(rule, now); len(sums) != 1 || sums[1].requests != 4 {
+		t.Errorf("unexpected sums in period %+v", sums)
+	}
+	if _, _, ok := metric(rule, sums[1]); ok {
+		t.Error("5xx rate should not exceed threshold")
+	}
+
+	w.add(1, nil, now.Add((maxPeriod-1)*time.Minute))
+	if len(w.counts) != 2 {
+		t.Errorf("expired counts should be removed, got %d", len(w.counts))
+	}
+}
+
+func TestCheckRule(t *testing.T) {
+	rules := map[*entity.AlertRule]error{
+		{RuleType: "other", Period: 5, Cooldown: 5, Receivers: "a@b.c"}:                                  errorRuleType,
+		{RuleType: RuleStatus5xxRate, Threshold: 101, Period: 5, Cooldown: 5, Receivers: "a@b.c"}:        errorThreshold,
+		{RuleType: RuleAPIError, Threshold: 1, Period: 61, Cooldown: 5, Receivers: "a@b.c"}:              errorPeriod,
+		{RuleType: RuleAPIError, Threshold: 1, Period: 5, Cooldown: 5}:                                   errorNotify,
+		{RuleType: RuleProxyTimeout, Threshold: 1, Period: 5, Cooldown: 5, WebhookURL: "ftp://a"}:        errorWebhookURL,
+		{RuleType: RuleNodeLost, Threshold: 3, Period: 5, Cooldown: 5, WebhookURL: "http://example.com"}: nil,
+	}
+	for rule, expect := range rules {
+		if err := CheckRule(rule); err != expect {
+			t.Errorf("%s: expect %v, got %v", rule.RuleType, expect, err)
+		}
+	}
+	rule := &entity.AlertRule{RuleType: RuleAPIError, Threshold: 1, Period: 5, Cooldown: 5, WebhookURL: "http://example.com", WebhookTemplate: "{{.Target"}
+	if err := CheckRule(rule); err == nil {
+		t.Error("expect template error")
+	}
+}
+
+func TestSendWebhook(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer srv.Close()
+
+	h := &entity.AlertHistory{RuleID: 1, RuleName: `rule "1"`, RuleType: RuleAPIError, Target: "api:1", Value: 5, Threshold: 3, Message: "5 failed requests", AlertTime: "2019-10-17 00:00:00"}
+	if err := sendWebhook(&entity.AlertRule{WebhookURL: srv.URL}, h); err != nil {
+		t.Fatal(err)
+	}
+	if body["ruleName"] != `rule "1"` || body["value"] != float64(5) || body["target"] != "api:1" {
+		t.Errorf("unexpected body %v", body)
+	}
+
+	rule := &entity.AlertRule{WebhookURL: srv.URL, WebhookTemplate: `{"text":{{json .Message}}}`}
+	if err := sendWebhook(rule, h); err != nil || body["text"] != "5 failed requests" {
+		t.Errorf("unexpected body %v %v", body, err)
+	}
+}
@@ -0,0 +1,198 @@
+package alert
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//evaluateInterval 规则检查间隔
+const evaluateInterval = time.Minute
+
+const timeFormat = "2006-01-02 15:04:05"
+
+//engine 定时检查启用的规则，同一规则同一目标在冷却时间内只通知一次
+type engine struct {
+	window    *window
+	startTime time.Time
+	lastAlert map[string]time.Time
+}
+
+func newEngine(now time.Time) *engine {
+	return &engine{
+		window:    newWindow(),
+		startTime: now,
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+func (e *engine) run() {
+	ticker := time.NewTicker(evaluateInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		e.evaluate(now)
+	}
+}
+
+//evaluate 网关告警开关关闭时不检查
+func (e *engine) evaluate(now time.Time) {
+	sender, err := console_sqlite3.GetAlertSender()
+	if err != nil {
+		log.Warn("get alert sender error:", err)
+		return
+	}
+	if sender.AlertStatus != 1 {
+		return
+	}
+	rules, err := console_sqlite3.GetAlertRuleList(true)
+	if err != nil {
+		log.Warn("get alert rules error:", err)
+		return
+	}
+	for _, rule := range rules {
+		for _, h := range e.check(rule, now) {
+			e.fire(sender, rule, h, now)
+		}
+	}
+}
+
+//check 返回规则触发的告警，每个目标一条
+func (e *engine) check(rule *entity.AlertRule, now time.Time) []*entity.AlertHistory {
+	if rule.RuleType == RuleNodeLost {
+		return e.checkNodes(rule, now)
+	}
+	alarms := make([]*entity.AlertHistory, 0)
+	for apiID, c := range e.window.sumByAPI(rule, now) {
+		value, message, ok := metric(rule, c)
+		if !ok {
+			continue
+		}
+		target := fmt.Sprintf("api:%d", apiID)
+		alarms = append(alarms, newHistory(rule, target, value, fmt.Sprintf("%s of %s in the last %d minutes", message, target, rule.Period), now))
+	}
+	return alarms
+}
+
+func metric(rule *entity.AlertRule, c *counter) (float64, string, bool) {
+	threshold := float64(rule.Threshold)
+	switch rule.RuleType {
+	case RuleAPIError:
+		value := float64(c.failures)
+		return value, fmt.Sprintf("%d failed requests", c.failures), value >= threshold
+	case RuleStatus5xxRate:
+		if c.requests == 0 {
+			return 0, "", false
+		}
+		value := math.Round(float64(c.status5xx)*10000/float64(c.requests)) / 100
+		return value, fmt.Sprintf("5xx rate %v%% (%d/%d)", value, c.status5xx, c.requests), value >= threshold
+	case RuleProxyTimeout:
+		value := float64(c.proxyTimeouts)
+		return value, fmt.Sprintf("%d upstream timeouts", c.proxyTimeouts), value >= threshold
+	}
+	return 0, "", false
+}
+
+//checkNodes 节点超过period分钟没有心跳时告警，控制台启动后从启动时间开始计算
+func (e *engine) checkNodes(rule *entity.AlertRule, now time.Time) []*entity.AlertHistory {
+	alarms := make([]*entity.AlertHistory, 0)
+	clusters, err := console_sqlite3.GetClusters()
+	if err != nil {
+		log.Warn("get clusters error:", err)
+		return alarms
+	}
+	for _, c := range clusters {
+		if rule.ClusterID > 0 && c.ID != rule.ClusterID {
+			continue
+		}
+		_, nodes, err := console_sqlite3.GetNodeList(c.ID, -1, "")
+		if err != nil {
+			log.Warn("get node list error:", err)
+			continue
+		}
+		for _, n := range nodes {
+			last, has := node.LastHeartBeat(n.NodeIP, n.NodePort)
+			if !has || last.Before(e.startTime) {
+				last = e.startTime
+			}
+			lost := now.Sub(last)
+			if lost < time.Duration(rule.Period)*time.Minute {
+				continue
+			}
+			target := fmt.Sprintf("node:%s:%s", n.NodeIP, n.NodePort)
+			minutes := math.Floor(lost.Minutes())
+			message := fmt.Sprintf("node %s(%s:%s) of cluster %s has no heartbeat for %v minutes", n.NodeName, n.NodeIP, n.NodePort, c.Name, minutes)
+			alarms = append(alarms, newHistory(rule, target, minutes, message, now))
+		}
+	}
+	return alarms
+}
+
+func newHistory(rule *entity.AlertRule, target string, value float64, message string, now time.Time) *entity.AlertHistory {
+	return &entity.AlertHistory{
+		RuleID:    rule.RuleID,
+		RuleName:  rule.RuleName,
+		RuleType:  rule.RuleType,
+		Target:    target,
+		Value:     value,
+		Threshold: rule.Threshold,
+		Message:   message,
+		AlertTime: now.Format(timeFormat),
+	}
+}
+
+//fire 冷却时间内已通知过的目标不再通知，否则发送通知并记录
+func (e *engine) fire(sender *entity.AlertSender, rule *entity.AlertRule, h *entity.AlertHistory, now time.Time) {
+	if last, has := e.lastAlertTime(rule.RuleID, h.Target); has && now.Sub(last) < time.Duration(rule.Cooldown)*time.Minute {
+		return
+	}
+	e.lastAlert[alertKey(rule.RuleID, h.Target)] = now
+
+	errs := make([]string, 0, 2)
+	if rule.Receivers != "" {
+		if err := sendMail(sender, rule, h); err != nil {
+			errs = append(errs, "mail: "+err.Error())
+		}
+	}
+	if rule.WebhookURL != "" {
+		if err := sendWebhook(rule, h); err != nil {
+			errs = append(errs, "webhook: "+err.Error())
+		}
+	}
+	if len(errs) == 0 {
+		h.NotifyStatus = 1
+	} else {
+		h.NotifyError = strings.Join(errs, "; ")
+		log.Warn("send alert ", h.RuleName, " error:", h.NotifyError)
+	}
+	if err := console_sqlite3.AddAlertHistory(h); err != nil {
+		log.Warn("add alert history error:", err)
+	}
+}
+
+//lastAlertTime 控制台重启后从告警记录中读取最近一次告警时间
+func (e *engine) lastAlertTime(ruleID int, target string) (time.Time, bool) {
+	key := alertKey(ruleID, target)
+	if t, has := e.lastAlert[key]; has {
+		return t, true
+	}
+	alertTime, err := console_sqlite3.GetLastAlertTime(ruleID, target)
+	if err != nil || alertTime == "" {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(timeFormat, alertTime, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	e.lastAlert[key] = t
+	return t, true
+}
+
+func alertKey(ruleID int, target string) string {
+	return fmt.Sprintf("%d|%s", ruleID, target)
+}
@@ -0,0 +1,68 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"text/template"
+	"time"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"github.com/eolinker/goku-api-gateway/utils"
+)
+
+//DefaultWebhookTemplate 未设置模板时webhook的请求体
+const DefaultWebhookTemplate = `{"ruleID":{{.RuleID}},"ruleName":{{json .RuleName}},"ruleType":{{json .RuleType}},"target":{{json .Target}},"value":{{.Value}},"threshold":{{.Threshold}},"message":{{json .Message}},"alertTime":{{json .AlertTime}}}`
+
+const webhookTimeout = time.Second * 5
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+//parseTemplate 解析webhook模板，模板中可使用告警记录的字段及json函数
+func parseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultWebhookTemplate
+	}
+	return template.New("webhook").Funcs(templateFuncs).Parse(text)
+}
+
+//sendWebhook 以模板渲染的请求体POST到webhook地址
+func sendWebhook(rule *entity.AlertRule, h *entity.AlertHistory) error {
+	t, err := parseTemplate(rule.WebhookTemplate)
+	if err != nil {
+		return err
+	}
+	body := new(bytes.Buffer)
+	if err := t.Execute(body, h); err != nil {
+		return err
+	}
+	resp, err := webhookClient.Post(rule.WebhookURL, "application/json", body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+//sendMail 通过网关告警配置的SMTP发送告警邮件
+func sendMail(sender *entity.AlertSender, rule *entity.AlertRule, h *entity.AlertHistory) error {
+	if sender.Sender == "" || sender.SMTPAddress == "" {
+		return fmt.Errorf("smtp sender is not configured")
+	}
+	subject := fmt.Sprintf("[Goku Alert] %s: %s", h.RuleName, h.Target)
+	body := fmt.Sprintf("%s\r\n\r\nrule: %s\r\ntarget: %s\r\nvalue: %v\r\nthreshold: %d\r\ntime: %s", h.Message, h.RuleName, h.Target, h.Value, h.Threshold, h.AlertTime)
+	host := fmt.Sprintf("%s:%d", sender.SMTPAddress, sender.SMTPPort)
+	return utils.SendToMail(sender.Sender, sender.SenderPassword, host, rule.Receivers, subject, body, "text", strconv.Itoa(sender.SMTPProtocol))
+}
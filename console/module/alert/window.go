@@ -0,0 +1,99 @@
+package alert
+
+import (
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//maxPeriod 统计周期的最大值，单位分钟
+const maxPeriod = 60
+
+type windowKey struct {
+	minute     int64
+	clusterID  int
+	strategyID string
+	apiID      int
+}
+
+type counter struct {
+	requests      int64
+	failures      int64
+	status5xx     int64
+	proxyTimeouts int64
+}
+
+func (c *counter) add(o *counter) {
+	c.requests += o.requests
+	c.failures += o.failures
+	c.status5xx += o.status5xx
+	c.proxyTimeouts += o.proxyTimeouts
+}
+
+//window 按分钟保存节点上报的增量计数，用于计算最近若干分钟的指标
+type window struct {
+	lock   sync.Mutex
+	counts map[windowKey]*counter
+}
+
+func newWindow() *window {
+	return &window{counts: make(map[windowKey]*counter)}
+}
+
+//add 记录节点上报的计数，以收到上报的时间归入分钟
+func (w *window) add(clusterID int, items []*config.MonitorItem, now time.Time) {
+	minute := now.Unix() / 60
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, item := range items {
+		key := windowKey{minute: minute, clusterID: clusterID, strategyID: item.StrategyID, apiID: item.APIID}
+		c, has := w.counts[key]
+		if !has {
+			c = new(counter)
+			w.counts[key] = c
+		}
+		c.add(&counter{
+			requests:      item.GatewayRequestCount,
+			failures:      item.GatewayRequestCount - item.GatewaySuccessCount,
+			status5xx:     item.GatewayStatus5xxCount,
+			proxyTimeouts: item.ProxyTimeoutCount,
+		})
+	}
+	oldest := minute - maxPeriod
+	for key := range w.counts {
+		if key.minute <= oldest {
+			delete(w.counts, key)
+		}
+	}
+}
+
+//sumByAPI 按接口汇总规则范围内最近period分钟的计数
+func (w *window) sumByAPI(rule *entity.AlertRule, now time.Time) map[int]*counter {
+	since := now.Unix()/60 - int64(rule.Period)
+	result := make(map[int]*counter)
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for key, c := range w.counts {
+		if key.minute <= since {
+			continue
+		}
+		if rule.ClusterID > 0 && key.clusterID != rule.ClusterID {
+			continue
+		}
+		if rule.StrategyID != "" && key.strategyID != rule.StrategyID {
+			continue
+		}
+		if rule.APIID > 0 && key.apiID != rule.APIID {
+			continue
+		}
+		sum, has := result[key.apiID]
+		if !has {
+			sum = new(counter)
+			result[key.apiID] = sum
+		}
+		sum.add(c)
+	}
+	return result
+}
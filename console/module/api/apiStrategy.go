@@ -1,9 +1,15 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+
+	"github.com/eolinker/goku-api-gateway/config"
 	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
 )
 
+var errorCanaryAPI = errors.New("canary only supports single-step apis with origin response")
+
 //AddAPIToStrategy 将接口加入策略组
 func AddAPIToStrategy(apiList []string, strategyID string) (bool, string, error) {
 	flag, result, err := console_sqlite3.AddAPIToStrategy(apiList, strategyID)
@@ -18,6 +24,43 @@ func SetTarget(apiID int, strategyID string, target string) (bool, string, error
 	return flag, result, err
 }
 
+//SetCanary 设置灰度配置，canary为空时清除
+func SetCanary(apiID int, strategyID string, canary *config.CanaryConfig) (bool, string, error) {
+	if canary == nil {
+		return console_sqlite3.SetAPICanaryOfStrategy(apiID, strategyID, "")
+	}
+	if err := canary.Check(); err != nil {
+		return false, "[ERROR]" + err.Error(), err
+	}
+	// 节点只对单步骤、原样返回的接口按负载分流
+	flag, info, err := console_sqlite3.GetAPIInfo(apiID)
+	if !flag {
+		return false, "[ERROR]The api does not exist!", err
+	}
+	if len(info.LinkAPIs) > 1 || (info.ResponseDataType != "" && info.ResponseDataType != "origin") {
+		return false, "[ERROR]" + errorCanaryAPI.Error(), errorCanaryAPI
+	}
+	data, err := json.Marshal(canary)
+	if err != nil {
+		return false, "[ERROR]Illegal canary!", err
+	}
+	return console_sqlite3.SetAPICanaryOfStrategy(apiID, strategyID, string(data))
+}
+
+//GetCanary 获取灰度配置
+func GetCanary(apiID int, strategyID string) (bool, *config.CanaryConfig, error) {
+	flag, canary, err := console_sqlite3.GetAPICanaryOfStrategy(apiID, strategyID)
+	if !flag || canary == "" {
+		return flag, nil, err
+	}
+	c := new(config.CanaryConfig)
+	err = json.Unmarshal([]byte(canary), c)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, c, nil
+}
+
 // BatchSetTarget 批量重置目标地址
 func BatchSetTarget(apiIds []int, strategyID string, target string) (bool, string, error) {
 	flag, result, err := console_sqlite3.BatchSetAPITargetOfStrategy(apiIds, strategyID, target)
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+)
+
+//SetCORS 设置接口跨域配置，cors为空时使用策略配置
+func SetCORS(apiID int, cors *config.CORSConfig) (bool, string, error) {
+	if cors == nil {
+		return console_sqlite3.SetAPICORS(apiID, "")
+	}
+	if err := cors.Check(); err != nil {
+		return false, "[ERROR]" + err.Error(), err
+	}
+	data, err := json.Marshal(cors)
+	if err != nil {
+		return false, "[ERROR]Illegal cors!", err
+	}
+	return console_sqlite3.SetAPICORS(apiID, string(data))
+}
+
+//GetCORS 获取接口跨域配置
+func GetCORS(apiID int) (bool, *config.CORSConfig, error) {
+	flag, cors, err := console_sqlite3.GetAPICORS(apiID)
+	if !flag || cors == "" {
+		return flag, nil, err
+	}
+	c := new(config.CORSConfig)
+	err = json.Unmarshal([]byte(cors), c)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, c, nil
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+)
+
+//SetIPAccess 设置接口IP黑白名单，ipAccess为空时关闭
+func SetIPAccess(apiID int, ipAccess *config.IPAccessConfig) (bool, string, error) {
+	if ipAccess == nil {
+		return console_sqlite3.SetAPIIPAccess(apiID, "")
+	}
+	if err := ipAccess.Check(); err != nil {
+		return false, "[ERROR]" + err.Error(), err
+	}
+	data, err := json.Marshal(ipAccess)
+	if err != nil {
+		return false, "[ERROR]Illegal ip access!", err
+	}
+	return console_sqlite3.SetAPIIPAccess(apiID, string(data))
+}
+
+//GetIPAccess 获取接口IP黑白名单
+func GetIPAccess(apiID int) (bool, *config.IPAccessConfig, error) {
+	flag, ipAccess, err := console_sqlite3.GetAPIIPAccess(apiID)
+	if !flag || ipAccess == "" {
+		return flag, nil, err
+	}
+	c := new(config.IPAccessConfig)
+	err = json.Unmarshal([]byte(ipAccess), c)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, c, nil
+}
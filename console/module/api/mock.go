@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+)
+
+var errorMockNotExist = errors.New("mock config does not exist")
+
+//SetMock 设置接口mock配置，mock为空时清空
+func SetMock(apiID int, mock *config.MockConfig) (bool, string, error) {
+	if mock == nil {
+		return console_sqlite3.SetAPIMock(apiID, "")
+	}
+	if err := mock.Check(); err != nil {
+		return false, "[ERROR]" + err.Error(), err
+	}
+	data, err := json.Marshal(mock)
+	if err != nil {
+		return false, "[ERROR]Illegal mock!", err
+	}
+	return console_sqlite3.SetAPIMock(apiID, string(data))
+}
+
+//GetMock 获取接口mock配置
+func GetMock(apiID int) (bool, *config.MockConfig, error) {
+	flag, mock, err := console_sqlite3.GetAPIMock(apiID)
+	if !flag || mock == "" {
+		return flag, nil, err
+	}
+	c := new(config.MockConfig)
+	err = json.Unmarshal([]byte(mock), c)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, c, nil
+}
+
+//SwitchMock 开启或关闭接口mock，不影响链路配置
+func SwitchMock(apiID int, enable bool) (bool, string, error) {
+	flag, mock, err := GetMock(apiID)
+	if !flag {
+		return false, "[ERROR]The api does not exist!", err
+	}
+	if mock == nil {
+		return false, "[ERROR]The mock config does not exist!", errorMockNotExist
+	}
+	mock.Enable = enable
+	return SetMock(apiID, mock)
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"github.com/eolinker/goku-api-gateway/utils"
+)
+
+const (
+	openAPIBalance = "x-goku-balance"
+	openAPITarget  = "x-goku-target"
+)
+
+var (
+	errorOpenAPIVersion = errors.New("unsupported document, expect swagger 2.0 or openapi 3.x")
+
+	openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+	// 文档中的路径参数 {id}
+	openAPIParam = regexp.MustCompile(`\{([^{}/]+)\}`)
+	// 网关路由中的路径参数 :id 及 *path
+	routerParam = regexp.MustCompile(`[:*]([^/]+)`)
+)
+
+//OpenAPIOption OpenAPI导入选项
+type OpenAPIOption struct {
+	GroupID    int    // 标签分组的父分组
+	Balance    string // 默认负载
+	TargetPath string // 转发路径前缀，为空时使用文档中的basePath或servers路径
+}
+
+type openAPIDoc struct {
+	Swagger  string   `json:"swagger"`
+	OpenAPI  string   `json:"openapi"`
+	Host     string   `json:"host"`
+	BasePath string   `json:"basePath"`
+	Schemes  []string `json:"schemes"`
+	Servers  []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+type openAPIOperation struct {
+	Tags        []string `json:"tags"`
+	Summary     string   `json:"summary"`
+	OperationID string   `json:"operationId"`
+	Balance     string   `json:"x-goku-balance"`
+	Target      string   `json:"x-goku-target"`
+}
+
+//ImportOpenAPI 从OpenAPI 3或Swagger 2文档导入接口，标签映射为接口分组
+func ImportOpenAPI(projectID, userID int, data []byte, option *OpenAPIOption) (int, int, error) {
+	apis, err := ParseOpenAPI(data, option)
+	if err != nil {
+		return 0, 0, err
+	}
+	return console_sqlite3.ImportOpenAPI(projectID, option.GroupID, userID, apis)
+}
+
+//ParseOpenAPI 解析OpenAPI 3或Swagger 2文档（json/yaml）
+func ParseOpenAPI(data []byte, option *OpenAPIOption) ([]*entity.DeclarativeAPI, error) {
+	data, err := utils.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	doc := new(openAPIDoc)
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	protocol, basePath := "http", ""
+	switch {
+	case strings.HasPrefix(doc.Swagger, "2."):
+		basePath = doc.BasePath
+		if len(doc.Schemes) > 0 {
+			protocol = strings.ToLower(doc.Schemes[0])
+		}
+	case strings.HasPrefix(doc.OpenAPI, "3."):
+		if len(doc.Servers) > 0 {
+			if u, err := url.Parse(doc.Servers[0].URL); err == nil {
+				basePath = u.Path
+				if u.Scheme != "" {
+					protocol = strings.ToLower(u.Scheme)
+				}
+			}
+		}
+	default:
+		return nil, errorOpenAPIVersion
+	}
+	targetPath := option.TargetPath
+	if targetPath == "" {
+		targetPath = basePath
+	}
+	targetPath = strings.TrimSuffix(targetPath, "/")
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	apis := make([]*entity.DeclarativeAPI, 0)
+	for _, path := range paths {
+		item := doc.Paths[path]
+		for _, method := range openAPIMethods {
+			raw, has := item[method]
+			if !has {
+				continue
+			}
+			op := new(openAPIOperation)
+			if err := json.Unmarshal(raw, op); err != nil {
+				return nil, fmt.Errorf("%s %s: %s", strings.ToUpper(method), path, err)
+			}
+			api := &entity.DeclarativeAPI{
+				Name:             op.Summary,
+				RequestURL:       openAPIParam.ReplaceAllString(path, ":$1"),
+				RequestMethod:    strings.ToUpper(method),
+				Protocol:         protocol,
+				Balance:          option.Balance,
+				TargetURL:        targetPath + openAPIParam.ReplaceAllString(path, "{{restful.$1}}"),
+				TargetMethod:     strings.ToUpper(method),
+				IsFollow:         true,
+				StripPrefix:      true,
+				StripSlash:       true,
+				Timeout:          2000,
+				ResponseDataType: "origin",
+			}
+			if api.Name == "" {
+				api.Name = op.OperationID
+			}
+			if api.Name == "" {
+				api.Name = api.RequestMethod + " " + path
+			}
+			if len(op.Tags) > 0 {
+				api.Group = op.Tags[0]
+			}
+			if op.Balance != "" {
+				api.Balance = op.Balance
+			}
+			if op.Target != "" {
+				api.TargetURL = op.Target
+			}
+			apis = append(apis, api)
+		}
+	}
+	return apis, nil
+}
+
+//ExportOpenAPI 导出项目或策略下的接口为OpenAPI 3文档，strategyID不为空时导出策略
+func ExportOpenAPI(projectID int, strategyID string, format string) ([]byte, error) {
+	title, apis, err := console_sqlite3.GetOpenAPIList(projectID, strategyID)
+	if err != nil {
+		return nil, err
+	}
+	doc := buildOpenAPI(title, apis)
+	if format == "yaml" {
+		return yaml.Marshal(doc)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func buildOpenAPI(title string, apis []*entity.DeclarativeAPI) map[string]interface{} {
+	paths := make(map[string]interface{})
+	tags := make([]interface{}, 0)
+	tagSet := make(map[string]bool)
+	for _, api := range apis {
+		path := routerParam.ReplaceAllString(api.RequestURL, "{$1}")
+		item, has := paths[path].(map[string]interface{})
+		if !has {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+		params := make([]interface{}, 0)
+		for _, m := range routerParam.FindAllStringSubmatch(api.RequestURL, -1) {
+			params = append(params, map[string]interface{}{
+				"name":     m[1],
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, method := range strings.Split(api.RequestMethod, ",") {
+			method = strings.ToLower(strings.TrimSpace(method))
+			if method == "" {
+				continue
+			}
+			op := map[string]interface{}{
+				"summary": api.Name,
+				"responses": map[string]interface{}{
+					"default": map[string]interface{}{"description": "response of " + api.Name},
+				},
+			}
+			if len(params) > 0 {
+				op["parameters"] = params
+			}
+			if api.Group != "" {
+				op["tags"] = []string{api.Group}
+				if !tagSet[api.Group] {
+					tagSet[api.Group] = true
+					tags = append(tags, map[string]interface{}{"name": api.Group})
+				}
+			}
+			if api.Balance != "" {
+				op[openAPIBalance] = api.Balance
+			}
+			if api.TargetURL != "" {
+				op[openAPITarget] = api.TargetURL
+			}
+			item[method] = op
+		}
+	}
+	doc := map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": "1.0.0",
+		},
+		"paths": paths,
+	}
+	if len(tags) > 0 {
+		doc["tags"] = tags
+	}
+	return doc
+}
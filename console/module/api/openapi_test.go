@@ -0,0 +1,73 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestParseOpenAPI(t *testing.T) {
+	swagger := []byte(`
+swagger: "2.0"
+basePath: /v1
+schemes: [https]
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+    get:
+      tags: [pets]
+      summary: Get pet
+    delete:
+      operationId: deletePet
+`)
+	apis, err := ParseOpenAPI(swagger, &OpenAPIOption{Balance: "pets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 2 {
+		t.Fatalf("apis: %d", len(apis))
+	}
+	get, del := apis[0], apis[1]
+	if get.Name != "Get pet" || get.Group != "pets" || get.RequestMethod != "GET" || get.RequestURL != "/pets/:petId" {
+		t.Errorf("get: %+v", get)
+	}
+	if get.TargetURL != "/v1/pets/{{restful.petId}}" || get.Protocol != "https" || get.Balance != "pets" {
+		t.Errorf("get target: %+v", get)
+	}
+	if del.Name != "deletePet" || del.Group != "" {
+		t.Errorf("delete: %+v", del)
+	}
+
+	openapi := []byte(`{"openapi":"3.0.1","servers":[{"url":"http://example.com/api/"}],"paths":{"/users":{"post":{"x-goku-balance":"users"}}}}`)
+	apis, err = ParseOpenAPI(openapi, &OpenAPIOption{TargetPath: "/internal/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apis) != 1 || apis[0].Name != "POST /users" || apis[0].TargetURL != "/internal/users" || apis[0].Balance != "users" {
+		t.Errorf("openapi: %+v", apis[0])
+	}
+
+	if _, err := ParseOpenAPI([]byte(`{"paths":{}}`), &OpenAPIOption{}); err != errorOpenAPIVersion {
+		t.Errorf("expect version error, got %v", err)
+	}
+}
+
+func TestBuildOpenAPI(t *testing.T) {
+	apis, _ := ParseOpenAPI([]byte(`{"openapi":"3.0.0","paths":{"/pets/{id}":{"get":{"summary":"a","tags":["pets"]},"put":{"summary":"b"}}}}`), &OpenAPIOption{})
+	doc := buildOpenAPI("p", apis)
+	paths := doc["paths"].(map[string]interface{})
+	item, ok := paths["/pets/{id}"].(map[string]interface{})
+	if !ok || len(item) != 2 {
+		t.Fatalf("paths: %v", paths)
+	}
+	get := item["get"].(map[string]interface{})
+	if get["summary"] != "a" || len(get["parameters"].([]interface{})) != 1 {
+		t.Errorf("get: %v", get)
+	}
+	if get[openAPITarget] != "/pets/{{restful.id}}" {
+		t.Errorf("target: %v", get[openAPITarget])
+	}
+	if len(doc["tags"].([]interface{})) != 1 {
+		t.Errorf("tags: %v", doc["tags"])
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+)
+
+//SetTransform 设置接口转换规则，transform为空时清空
+func SetTransform(apiID int, transform *config.TransformConfig) (bool, string, error) {
+	if transform == nil {
+		return console_sqlite3.SetAPITransform(apiID, "")
+	}
+	if err := transform.Check(); err != nil {
+		return false, "[ERROR]" + err.Error(), err
+	}
+	data, err := json.Marshal(transform)
+	if err != nil {
+		return false, "[ERROR]Illegal transform!", err
+	}
+	return console_sqlite3.SetAPITransform(apiID, string(data))
+}
+
+//GetTransform 获取接口转换规则
+func GetTransform(apiID int) (bool, *config.TransformConfig, error) {
+	flag, transform, err := console_sqlite3.GetAPITransform(apiID)
+	if !flag || transform == "" {
+		return flag, nil, err
+	}
+	c := new(config.TransformConfig)
+	err = json.Unmarshal([]byte(transform), c)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, c, nil
+}
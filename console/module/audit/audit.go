@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//FormatCSV csv格式
+	FormatCSV = "csv"
+	//FormatJSON json格式
+	FormatJSON = "json"
+)
+
+var errorFormat = errors.New("illegal format, expect csv or json")
+
+//AddAuditLog 新增审计日志
+func AddAuditLog(l *entity.AuditLog) error {
+	return console_sqlite3.AddAuditLog(l)
+}
+
+//GetAuditLogList 获取审计日志列表
+func GetAuditLogList(cond *entity.AuditLogCondition, page, pageSize int) ([]*entity.AuditLog, int, error) {
+	return console_sqlite3.GetAuditLogList(cond, page, pageSize)
+}
+
+//ExportAuditLog 导出符合条件的全部审计日志
+func ExportAuditLog(cond *entity.AuditLogCondition, format string) ([]byte, error) {
+	if format != FormatCSV && format != FormatJSON {
+		return nil, errorFormat
+	}
+	logs, _, err := console_sqlite3.GetAuditLogList(cond, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatJSON {
+		return json.MarshalIndent(logs, "", "  ")
+	}
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	w.Write([]string{"logID", "operateTime", "userID", "loginCall", "remoteAddr", "forwardedFor", "method", "operation", "targetType", "targetID", "statusCode", "resultDesc", "requestData", "beforeData", "afterData"})
+	for _, l := range logs {
+		w.Write([]string{strconv.Itoa(l.LogID), l.OperateTime, strconv.Itoa(l.UserID), l.LoginCall, l.RemoteAddr, l.ForwardedFor, l.Method, l.Operation, l.TargetType, l.TargetID, l.StatusCode, l.ResultDesc, l.RequestData, l.BeforeData, l.AfterData})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
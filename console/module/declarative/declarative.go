@@ -0,0 +1,206 @@
+package declarative
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	plugin_config "github.com/eolinker/goku-api-gateway/console/module/plugin/plugin-config"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"github.com/eolinker/goku-api-gateway/utils"
+)
+
+const (
+	//FormatYAML yaml格式
+	FormatYAML = "yaml"
+	//FormatJSON json格式
+	FormatJSON = "json"
+
+	maxGroupDepth = 5
+)
+
+var errorFormat = errors.New("illegal format, expect yaml or json")
+
+//Export 导出声明式网关配置
+func Export(format string) ([]byte, error) {
+	d, err := console_sqlite3.ExportDeclarative()
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "", FormatYAML:
+		return yaml.Marshal(d)
+	case FormatJSON:
+		return json.MarshalIndent(d, "", "  ")
+	}
+	return nil, errorFormat
+}
+
+//Import 校验并导入声明式网关配置，dryRun为true时只返回变更计划
+func Import(data []byte, dryRun bool, userID int) ([]*entity.DeclarativeChange, error) {
+	d, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return console_sqlite3.ImportDeclarative(d, dryRun, userID)
+}
+
+//Parse 解析并校验声明式网关配置，内容以 { 开头时按json解析，否则按yaml解析
+func Parse(data []byte) (*entity.Declarative, error) {
+	data, err := utils.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	d := new(entity.Declarative)
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, err
+	}
+	if err := check(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+type checker struct {
+	errs []string
+}
+
+func (c *checker) fail(path, format string, args ...interface{}) {
+	c.errs = append(c.errs, path+": "+fmt.Sprintf(format, args...))
+}
+
+// unique 检查名称非空且不重复
+func (c *checker) unique(names map[string]bool, path, field, name string) {
+	if name == "" {
+		c.fail(path, "%s is required", field)
+		return
+	}
+	if names[name] {
+		c.fail(path, "duplicate %s %s", field, name)
+	}
+	names[name] = true
+}
+
+func check(d *entity.Declarative) error {
+	c := new(checker)
+	names := make(map[string]bool)
+	defaults := 0
+	for i, s := range d.Services {
+		path := fmt.Sprintf("services[%d]", i)
+		c.unique(names, path, "name", s.Name)
+		if s.Driver == "" {
+			c.fail(path, "driver is required")
+		}
+		if s.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		c.fail("services", "only one default service is allowed")
+	}
+
+	names = make(map[string]bool)
+	for i, b := range d.Balances {
+		path := fmt.Sprintf("balances[%d]", i)
+		c.unique(names, path, "name", b.Name)
+		if b.Service == "" {
+			c.fail(path, "service is required")
+		}
+	}
+
+	names = make(map[string]bool)
+	for i, p := range d.Projects {
+		path := fmt.Sprintf("projects[%d]", i)
+		c.unique(names, path, "name", p.Name)
+		for j, g := range p.Groups {
+			checkGroup(c, fmt.Sprintf("%s.groups[%d]", path, j), g)
+		}
+		apis := make(map[string]bool)
+		for j, api := range p.APIs {
+			apiPath := fmt.Sprintf("%s.apis[%d]", path, j)
+			c.unique(apis, apiPath, "name", api.Name)
+			if api.RequestURL == "" {
+				c.fail(apiPath, "requestURL is required")
+			}
+			if api.RequestMethod == "" {
+				c.fail(apiPath, "requestMethod is required")
+			}
+			if api.Group != "" {
+				checkGroup(c, apiPath+".group", api.Group)
+			}
+			api.RequestMethod = strings.ToUpper(api.RequestMethod)
+			api.TargetMethod = strings.ToUpper(api.TargetMethod)
+			if api.Protocol == "" {
+				api.Protocol = "http"
+			}
+			if api.ResponseDataType == "" {
+				api.ResponseDataType = "origin"
+			}
+		}
+	}
+
+	names = make(map[string]bool)
+	for i, s := range d.Strategies {
+		path := fmt.Sprintf("strategies[%d]", i)
+		c.unique(names, path, "id", s.ID)
+		if s.Name == "" {
+			c.fail(path, "name is required")
+		}
+		checkPlugins(c, path, s.Plugins)
+		apis := make(map[string]bool)
+		for j, a := range s.APIs {
+			apiPath := fmt.Sprintf("%s.apis[%d]", path, j)
+			if a.Project == "" || a.API == "" {
+				c.fail(apiPath, "project and api are required")
+				continue
+			}
+			c.unique(apis, apiPath, "api", a.Project+"/"+a.API)
+			checkPlugins(c, apiPath, a.Plugins)
+		}
+	}
+
+	if len(c.errs) > 0 {
+		return errors.New(strings.Join(c.errs, "; "))
+	}
+	return nil
+}
+
+func checkGroup(c *checker, path, group string) {
+	segments := strings.Split(group, "/")
+	if len(segments) > maxGroupDepth {
+		c.fail(path, "group %s exceeds the grouping level", group)
+	}
+	for _, s := range segments {
+		if s == "" {
+			c.fail(path, "illegal group %s", group)
+			return
+		}
+	}
+}
+
+func checkPlugins(c *checker, path string, plugins []*entity.DeclarativePlugin) {
+	names := make(map[string]bool)
+	for i, p := range plugins {
+		pluginPath := fmt.Sprintf("%s.plugins[%d]", path, i)
+		c.unique(names, pluginPath, "name", p.Name)
+		if p.Config == nil {
+			continue
+		}
+		config, ok := p.Config.(string)
+		if !ok {
+			data, err := json.Marshal(p.Config)
+			if err != nil {
+				c.fail(pluginPath+".config", "%s", err.Error())
+				continue
+			}
+			config = string(data)
+		}
+		if _, err := plugin_config.CheckConfig(p.Name, []byte(config)); err != nil {
+			c.fail(pluginPath+".config", "%s", err.Error())
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package declarative
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	d, err := Parse([]byte(`
+projects:
+  - name: p1
+    groups: [a, a/b]
+    apis:
+      - name: hello
+        group: a/b
+        requestURL: /hello
+        requestMethod: get
+        cors: {allowOrigin: "*"}
+strategies:
+  - id: s1
+    name: s1
+    plugins:
+      - name: goku-rate_limiting
+        enable: true
+        config: {second: 10}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := d.Projects[0].APIs[0]
+	if api.RequestMethod != "GET" || api.Protocol != "http" || api.ResponseDataType != "origin" {
+		t.Errorf("api defaults: %+v", api)
+	}
+	if cors, ok := api.CORS.(map[string]interface{}); !ok || cors["allowOrigin"] != "*" {
+		t.Errorf("cors: %#v", api.CORS)
+	}
+	if config, ok := d.Strategies[0].Plugins[0].Config.(map[string]interface{}); !ok || config["second"] != float64(10) {
+		t.Errorf("plugin config: %#v", d.Strategies[0].Plugins[0].Config)
+	}
+
+	_, err = Parse([]byte(`{"projects":[{"name":"p1","apis":[{"name":"a","requestURL":"/a","requestMethod":"GET"},{"name":"a","requestURL":"/b","requestMethod":"GET","group":"x//y"}]}],"strategies":[{"id":"s1","name":"s1","apis":[{"project":"p1"}]}]}`))
+	if err == nil {
+		t.Fatal("expect validation error")
+	}
+	for _, expect := range []string{"projects[0].apis[1]: duplicate name a", "projects[0].apis[1].group: illegal group x//y", "strategies[0].apis[0]: project and api are required"} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Errorf("missing %q in %s", expect, err)
+		}
+	}
+}
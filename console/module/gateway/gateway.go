@@ -1,8 +1,11 @@
 package gateway
 
 import (
+	"time"
+
 	v "github.com/eolinker/goku-api-gateway/common/version"
 	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
 )
 
 //BaseGatewayInfo 网关基本配置
@@ -20,7 +23,8 @@ type BaseGatewayInfo struct {
 
 //SystemInfo 系统配置
 type SystemInfo struct {
-	BaseInfo BaseGatewayInfo `json:"baseInfo"`
+	BaseInfo    BaseGatewayInfo      `json:"baseInfo"`
+	MonitorInfo *entity.MonitorCount `json:"monitorInfo"` // 最近24小时的请求计数
 }
 
 //GetGatewayConfig 获取网关配置
@@ -34,8 +38,13 @@ func EditGatewayBaseConfig(successCode string, nodeUpdatePeriod, monitorUpdatePe
 	return flag, result, err
 }
 
-//EditGatewayAlarmConfig 编辑网关告警配置
+//EditGatewayAlarmConfig 编辑网关告警配置，senderPassword为空时保留原密码
 func EditGatewayAlarmConfig(apiAlertInfo, sender, senderPassword, smtpAddress string, alertStatus, smtpPort, smtpProtocol int) (bool, string, error) {
+	if senderPassword == "" {
+		if s, err := console_sqlite3.GetAlertSender(); err == nil {
+			senderPassword = s.SenderPassword
+		}
+	}
 	flag, result, err := console_sqlite3.EditGatewayAlarmConfig(apiAlertInfo, sender, senderPassword, smtpAddress, alertStatus, smtpPort, smtpProtocol)
 
 	return flag, result, err
@@ -56,6 +65,12 @@ func GetGatewayMonitorSummaryByPeriod() (bool, *SystemInfo, error) {
 	info.BaseInfo.StrategyCount = strategyCount
 	info.BaseInfo.Version = v.Version
 	info.BaseInfo.ClusterCount = console_sqlite3.GetClusterCount()
+	// 最近24小时，含当前小时
+	hour := time.Now().Unix() / 3600 * 3600
+	info.MonitorInfo, e = console_sqlite3.GetMonitorSummary(&entity.MonitorCondition{BeginHour: hour - 23*3600, EndHour: hour})
+	if e != nil {
+		return false, nil, e
+	}
 
 	return true, info, nil
 
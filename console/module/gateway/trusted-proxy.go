@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+)
+
+//SetTrustedProxies 设置可信代理IP/CIDR列表，列表为空时不信任任何代理头部
+func SetTrustedProxies(trustedProxies []string) (bool, string, error) {
+	if len(trustedProxies) == 0 {
+		return console_sqlite3.SetTrustedProxies("")
+	}
+	if _, err := config.ParseIPNets(trustedProxies); err != nil {
+		return false, "[ERROR]" + err.Error(), err
+	}
+	data, err := json.Marshal(trustedProxies)
+	if err != nil {
+		return false, "[ERROR]Illegal trustedProxies!", err
+	}
+	return console_sqlite3.SetTrustedProxies(string(data))
+}
+
+//GetTrustedProxies 获取可信代理IP/CIDR列表
+func GetTrustedProxies() (bool, []string, error) {
+	flag, trustedProxies, err := console_sqlite3.GetTrustedProxies()
+	proxies := make([]string, 0)
+	if !flag || trustedProxies == "" {
+		return flag, proxies, err
+	}
+	err = json.Unmarshal([]byte(trustedProxies), &proxies)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, proxies, nil
+}
@@ -0,0 +1,82 @@
+package monitor
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/module/alert"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//OrderSlowest 按平均耗时排序
+	OrderSlowest = console_sqlite3.MonitorOrderSlowest
+	//OrderFailure 按失败次数排序
+	OrderFailure = console_sqlite3.MonitorOrderFailure
+)
+
+var errorOrder = errors.New("illegal order, expect slowest or failure")
+
+//ReportMonitor 记录节点上报的监控计数及插件执行计数
+func ReportMonitor(n *entity.Node, items []*config.MonitorItem, plugins []*config.PluginMonitorItem) error {
+	valid := make([]*config.MonitorItem, 0, len(items))
+	for _, item := range items {
+		if item == nil || item.StrategyID == "" || item.Hour%3600 != 0 {
+			continue
+		}
+		valid = append(valid, item)
+	}
+	validPlugins := make([]*config.PluginMonitorItem, 0, len(plugins))
+	for _, item := range plugins {
+		if item == nil || item.PluginName == "" || item.Hour%3600 != 0 {
+			continue
+		}
+		validPlugins = append(validPlugins, item)
+	}
+	if len(valid) == 0 && len(validPlugins) == 0 {
+		return nil
+	}
+	clusterID := console_sqlite3.GetClusterIDByName(n.Cluster)
+	now := time.Now().Format("2006-01-02 15:04:05")
+	// 接口计数与插件计数在同一事务中写入，写入失败时节点整体重报，不会重复计入
+	if err := console_sqlite3.AddMonitorRecords(clusterID, valid, validPlugins, now); err != nil {
+		return err
+	}
+	// 计数写入成功后再评估告警
+	if len(valid) > 0 {
+		alert.Observe(clusterID, valid)
+	}
+	return nil
+}
+
+//GetSummary 获取监控计数汇总
+func GetSummary(cond *entity.MonitorCondition) (*entity.MonitorCount, error) {
+	return console_sqlite3.GetMonitorSummary(cond)
+}
+
+//GetTrend 获取每小时的监控计数
+func GetTrend(cond *entity.MonitorCondition) ([]*entity.MonitorTrend, error) {
+	trend, err := console_sqlite3.GetMonitorTrend(cond)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range trend {
+		t.Time = time.Unix(t.Hour, 0).Format("2006-01-02 15:04")
+	}
+	return trend, nil
+}
+
+//GetAPIRank 获取最慢或失败最多的接口
+func GetAPIRank(cond *entity.MonitorCondition, orderBy string, limit int) ([]*entity.MonitorAPIRank, error) {
+	if orderBy != OrderSlowest && orderBy != OrderFailure {
+		return nil, errorOrder
+	}
+	return console_sqlite3.GetMonitorAPIRank(cond, orderBy, limit)
+}
+
+//GetPluginList 获取插件执行计数
+func GetPluginList(cond *entity.MonitorCondition) ([]*entity.MonitorPlugin, error) {
+	return console_sqlite3.GetMonitorPluginList(cond)
+}
@@ -0,0 +1,217 @@
+package node
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	SQL "database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+var (
+	//ErrorNodeUnauthorized 节点未提供凭证或凭证无效
+	ErrorNodeUnauthorized = errors.New("node unauthorized")
+	//ErrorNodeForbidden 节点凭证与节点不匹配或不允许自动注册
+	ErrorNodeForbidden = errors.New("node forbidden")
+	//ErrorNodeNotRegistered 节点未注册
+	ErrorNodeNotRegistered = errors.New("node is not registered")
+
+	errorClusterNotExist = errors.New("cluster does not exist")
+	errorGroupCluster    = errors.New("node group does not belong to the cluster")
+)
+
+//Credential 节点接入凭证
+type Credential struct {
+	Token       string // 集群或节点凭证
+	CertCluster string // 已验证的客户端证书所属集群
+}
+
+//AuthenticateNode 认证接入的节点，返回节点信息及用于配置签名的密钥（无共享密钥时为空）
+func AuthenticateNode(ip string, port int, name string, cred *Credential, requireAuth bool) (*entity.Node, string, error) {
+	var clusterAuth *entity.ClusterAuth
+	secret := ""
+	switch {
+	case cred.Token != "":
+		hash := hashToken(cred.Token)
+		has, node, err := console_sqlite3.GetNodeByToken(hash)
+		if err != nil {
+			return nil, "", err
+		}
+		if has {
+			// 节点凭证直接确定节点，不依赖来源地址
+			return node, cred.Token, nil
+		}
+		has, clusterAuth, err = console_sqlite3.GetClusterAuthByToken(hash)
+		if err != nil {
+			return nil, "", err
+		}
+		if !has {
+			return nil, "", ErrorNodeUnauthorized
+		}
+		secret = cred.Token
+	case cred.CertCluster != "":
+		has, auth, err := console_sqlite3.GetClusterAuth(cred.CertCluster)
+		if err != nil {
+			return nil, "", err
+		}
+		if !has {
+			return nil, "", ErrorNodeUnauthorized
+		}
+		clusterAuth = auth
+	}
+
+	has, node, err := getNodeByIPPort(ip, port)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if clusterAuth == nil {
+		// 未提供凭证
+		if !has {
+			return nil, "", ErrorNodeNotRegistered
+		}
+		if requireAuth {
+			return nil, "", ErrorNodeUnauthorized
+		}
+		if protected, err := isProtected(node); err != nil || protected {
+			return nil, "", ErrorNodeUnauthorized
+		}
+		return node, "", nil
+	}
+
+	if has {
+		if node.Cluster != clusterAuth.Name {
+			return nil, "", ErrorNodeForbidden
+		}
+		return node, secret, nil
+	}
+	if !clusterAuth.AutoRegister {
+		return nil, "", ErrorNodeForbidden
+	}
+	node, err = registerNode(clusterAuth, ip, port, name)
+	if err != nil {
+		return nil, "", err
+	}
+	return node, secret, nil
+}
+
+func getNodeByIPPort(ip string, port int) (bool, *entity.Node, error) {
+	has, node, err := console_sqlite3.GetNodeByIPPort(ip, port)
+	if err == SQL.ErrNoRows {
+		return false, nil, nil
+	}
+	return has, node, err
+}
+
+// isProtected 节点或节点所属集群设置了凭证时，不再允许匿名接入
+func isProtected(node *entity.Node) (bool, error) {
+	token, err := console_sqlite3.GetNodeToken(node.NodeID)
+	if err != nil {
+		return false, err
+	}
+	if token != "" {
+		return true, nil
+	}
+	has, auth, err := console_sqlite3.GetClusterAuth(node.Cluster)
+	if err != nil {
+		return false, err
+	}
+	return has && auth.Token != "", nil
+}
+
+func registerNode(clusterAuth *entity.ClusterAuth, ip string, port int, name string) (*entity.Node, error) {
+	if name == "" {
+		name = fmt.Sprintf("%s:%d", ip, port)
+	}
+	_, _, err := console_sqlite3.AddNode(clusterAuth.ClusterID, name, ip, strconv.Itoa(port), "", clusterAuth.RegisterGroupID)
+	if err != nil {
+		return nil, err
+	}
+	has, node, err := getNodeByIPPort(ip, port)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrorNodeNotRegistered
+	}
+	return node, nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken 凭证只保存摘要，明文仅在重新生成时返回一次
+func hashToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+//GetClusterAuth 获取集群节点接入认证配置
+func GetClusterAuth(cluster string) (*entity.ClusterAuth, error) {
+	has, auth, err := console_sqlite3.GetClusterAuth(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, errorClusterNotExist
+	}
+	auth.HasToken = auth.Token != ""
+	return auth, nil
+}
+
+//ResetClusterToken 重新生成集群凭证，enable为false时清除凭证
+func ResetClusterToken(cluster string, enable bool) (string, error) {
+	auth, err := GetClusterAuth(cluster)
+	if err != nil {
+		return "", err
+	}
+	token := ""
+	if enable {
+		token, err = newToken()
+		if err != nil {
+			return "", err
+		}
+	}
+	return token, console_sqlite3.SetClusterToken(auth.ClusterID, hashToken(token))
+}
+
+//SetClusterRegister 设置集群节点自动注册
+func SetClusterRegister(cluster string, autoRegister bool, groupID int) error {
+	auth, err := GetClusterAuth(cluster)
+	if err != nil {
+		return err
+	}
+	if groupID != 0 {
+		has, group, err := console_sqlite3.GetNodeGroupInfo(groupID)
+		if err != nil || !has || group["cluster"] != cluster {
+			return errorGroupCluster
+		}
+	}
+	return console_sqlite3.SetClusterRegister(auth.ClusterID, autoRegister, groupID)
+}
+
+//ResetNodeToken 重新生成节点凭证，enable为false时清除凭证
+func ResetNodeToken(nodeID int, enable bool) (string, error) {
+	token := ""
+	if enable {
+		var err error
+		token, err = newToken()
+		if err != nil {
+			return "", err
+		}
+	}
+	return token, console_sqlite3.SetNodeToken(nodeID, hashToken(token))
+}
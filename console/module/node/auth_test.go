@@ -0,0 +1,17 @@
+package node
+
+import "testing"
+
+func TestHashToken(t *testing.T) {
+	if hashToken("") != "" {
+		t.Error("expect cleared token to stay empty")
+	}
+	token, err := newToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := hashToken(token)
+	if hash == token || len(hash) != 64 || hashToken(token) != hash {
+		t.Errorf("unexpected token hash %s", hash)
+	}
+}
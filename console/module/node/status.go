@@ -47,16 +47,23 @@ func (m *_StatusManager) get(id string) (time.Time, bool) {
 	return t, b
 }
 
+func nodeKey(ip string, port string) string {
+	return fmt.Sprintf("%s:%s", ip, port)
+}
+
 //Refresh refresh
 func Refresh(ip string, port string) {
-	id := fmt.Sprintf("%s:%d", ip, port)
-	manager.refresh(id)
+	manager.refresh(nodeKey(ip, port))
+}
+
+//LastHeartBeat 获取节点最近一次心跳时间，控制台启动后未收到心跳时返回false
+func LastHeartBeat(ip string, port string) (time.Time, bool) {
+	return manager.get(nodeKey(ip, port))
 }
 
 //IsLive 通过ip和端口获取当前节点在线状态
 func IsLive(ip string, port string) bool {
-	id := fmt.Sprintf("%s:%d", ip, port)
-	t, has := manager.get(id)
+	t, has := manager.get(nodeKey(ip, port))
 	if !has {
 		return false
 	}
@@ -70,7 +77,9 @@ func IsLive(ip string, port string) bool {
 //ResetNodeStatus 重置节点状态
 func ResetNodeStatus(nodes ...*entity.Node) {
 	for _, node := range nodes {
-
+		if node == nil {
+			continue
+		}
 		if IsLive(node.NodeIP, node.NodePort) {
 			node.NodeStatus = 1
 		} else {
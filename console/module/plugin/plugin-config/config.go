@@ -15,8 +15,17 @@ func isEnd(r rune) bool {
 
 var allConfigOfPlugin map[string]interface{}
 
-//CheckConfig 检查插件配置是否有效
+//CheckConfig 检查插件配置是否有效，插件提供了配置Schema时按Schema校验
 func CheckConfig(pluginName string, config []byte) (bool, error) {
+	schema, has, err := loadSchema(pluginName)
+	if err != nil {
+		return false, err
+	}
+	if has {
+		if err := validateSchema(schema.schema, config); err != nil {
+			return false, err
+		}
+	}
 	v, has := allConfigOfPlugin[pluginName]
 	if has {
 		err := json.Unmarshal(config, v)
@@ -0,0 +1,108 @@
+package plugin_config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+//schemaDir 插件目录，插件的配置Schema为 插件名.schema.json；控制台不加载插件代码
+var schemaDir = "plugin"
+
+type schemaCache struct {
+	modTime time.Time
+	source  []byte
+	schema  *jsonschema.Schema
+}
+
+var (
+	schemas       = make(map[string]*schemaCache)
+	schemasLocker sync.Mutex
+)
+
+//GetSchema 获取插件配置的JSON Schema，插件未提供时返回false
+func GetSchema(pluginName string) ([]byte, bool, error) {
+	c, has, err := loadSchema(pluginName)
+	if !has || err != nil {
+		return nil, has, err
+	}
+	return c.source, true, nil
+}
+
+//loadSchema 读取 插件名.schema.json，文件未修改时使用缓存
+func loadSchema(pluginName string) (*schemaCache, bool, error) {
+	if pluginName == "" || strings.ContainsAny(pluginName, `/\`) {
+		return nil, false, nil
+	}
+	path := filepath.Join(schemaDir, pluginName+".schema.json")
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	schemasLocker.Lock()
+	defer schemasLocker.Unlock()
+	if c, has := schemas[pluginName]; has && c.modTime.Equal(info.ModTime()) {
+		return c, true, nil
+	}
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, true, err
+	}
+	schema, err := jsonschema.CompileString(pluginName+".schema.json", string(source))
+	if err != nil {
+		return nil, true, fmt.Errorf("插件配置Schema无效:%s", err.Error())
+	}
+	c := &schemaCache{modTime: info.ModTime(), source: source, schema: schema}
+	schemas[pluginName] = c
+	return c, true, nil
+}
+
+//validateSchema 按Schema校验插件配置，空配置视为空对象，错误信息包含配置中出错的位置
+func validateSchema(schema *jsonschema.Schema, config []byte) error {
+	if len(bytes.TrimSpace(config)) == 0 {
+		config = []byte("{}")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return fmt.Errorf("json格式错误：%s", err.Error())
+	}
+	err := schema.Validate(v)
+	if err == nil {
+		return nil
+	}
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+	messages := make([]string, 0, 1)
+	for _, leaf := range leafErrors(ve) {
+		location := leaf.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		messages = append(messages, location+": "+leaf.Message)
+	}
+	return fmt.Errorf("配置校验失败：%s", strings.Join(messages, "; "))
+}
+
+func leafErrors(ve *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(ve.Causes) == 0 {
+		return []*jsonschema.ValidationError{ve}
+	}
+	leaves := make([]*jsonschema.ValidationError, 0, len(ve.Causes))
+	for _, cause := range ve.Causes {
+		leaves = append(leaves, leafErrors(cause)...)
+	}
+	return leaves
+}
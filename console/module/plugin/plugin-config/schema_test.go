@@ -0,0 +1,53 @@
+package plugin_config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goku-schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d string) { schemaDir = d }(schemaDir)
+	schemaDir = dir
+	ioutil.WriteFile(filepath.Join(dir, "demo.schema.json"), []byte(`{
+		"type": "object",
+		"required": ["key"],
+		"properties": {
+			"key": {"type": "string"},
+			"rules": {"type": "array", "items": {"type": "object", "properties": {"limit": {"type": "integer", "minimum": 1}}}}
+		}
+	}`), 0644)
+
+	if ok, err := CheckConfig("demo", []byte(`{"key":"a","rules":[{"limit":2}]}`)); !ok {
+		t.Errorf("expect valid config, got %v", err)
+	}
+	ok, err := CheckConfig("demo", []byte(`{"key":1,"rules":[{"limit":0}]}`))
+	if ok || err == nil {
+		t.Fatal("expect invalid config")
+	}
+	for _, expect := range []string{"/key: ", "/rules/0/limit: "} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Errorf("missing %q in %s", expect, err)
+		}
+	}
+	if ok, err := CheckConfig("demo", nil); ok || !strings.Contains(err.Error(), "/: ") {
+		t.Errorf("expect missing key error, got %v", err)
+	}
+
+	if source, has, err := GetSchema("demo"); !has || err != nil || !strings.Contains(string(source), `"required"`) {
+		t.Errorf("unexpected schema %s %v %v", source, has, err)
+	}
+	if _, has, _ := GetSchema("none"); has {
+		t.Error("expect no schema")
+	}
+	if ok, _ := CheckConfig("none", []byte(`{}`)); !ok {
+		t.Error("plugin without schema should pass")
+	}
+}
@@ -1,6 +1,9 @@
 package plugin
 
 import (
+	"errors"
+
+	"github.com/eolinker/goku-api-gateway/config"
 	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
 	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
 )
@@ -25,6 +28,32 @@ func EditPlugin(pluginName, pluginConfig, pluginDesc, version string, pluginPrio
 	return console_sqlite3.EditPlugin(pluginName, pluginConfig, pluginDesc, version, pluginPriority, isStop, pluginType)
 }
 
+//CheckPolicy 检查插件执行超时时间及失败策略
+func CheckPolicy(policy *entity.PluginPolicy) error {
+	if policy.Timeout < 0 {
+		return errors.New("illegal timeout")
+	}
+	switch policy.FailPolicy {
+	case "":
+		policy.FailPolicy = config.PluginFailContinue
+	case config.PluginFailContinue, config.PluginFailStop, config.PluginFailFallback:
+	default:
+		return errors.New("illegal failPolicy, expect continue, stop or fallback")
+	}
+	if policy.FailStatus != 0 && (policy.FailStatus < 100 || policy.FailStatus > 599) {
+		return errors.New("illegal failStatus")
+	}
+	return nil
+}
+
+//EditPluginPolicy 修改插件执行超时时间及失败策略
+func EditPluginPolicy(pluginName string, policy *entity.PluginPolicy) (bool, string, error) {
+	if err := CheckPolicy(policy); err != nil {
+		return false, "[ERROR]" + err.Error(), err
+	}
+	return console_sqlite3.EditPluginPolicy(pluginName, policy)
+}
+
 //DeletePlugin 删除插件信息
 func DeletePlugin(pluginName string) (bool, string, error) {
 	return console_sqlite3.DeletePlugin(pluginName)
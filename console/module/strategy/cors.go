@@ -0,0 +1,37 @@
+package strategy
+
+import (
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+)
+
+//SetCORS 设置策略跨域配置，cors为空时关闭跨域
+func SetCORS(strategyID string, cors *config.CORSConfig) (bool, string, error) {
+	if cors == nil {
+		return console_sqlite3.SetStrategyCORS(strategyID, "")
+	}
+	if err := cors.Check(); err != nil {
+		return false, "[ERROR]" + err.Error(), err
+	}
+	data, err := json.Marshal(cors)
+	if err != nil {
+		return false, "[ERROR]Illegal cors!", err
+	}
+	return console_sqlite3.SetStrategyCORS(strategyID, string(data))
+}
+
+//GetCORS 获取策略跨域配置
+func GetCORS(strategyID string) (bool, *config.CORSConfig, error) {
+	flag, cors, err := console_sqlite3.GetStrategyCORS(strategyID)
+	if !flag || cors == "" {
+		return flag, nil, err
+	}
+	c := new(config.CORSConfig)
+	err = json.Unmarshal([]byte(cors), c)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, c, nil
+}
@@ -0,0 +1,93 @@
+package versionConfig
+
+import (
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//DriftOutdated 节点运行的版本与期望版本不一致
+	DriftOutdated = "outdated"
+	//DriftFailed 节点应用配置失败
+	DriftFailed = "failed"
+	//DriftUnknown 节点未上报应用结果
+	DriftUnknown = "unknown"
+)
+
+//ReportApplyStatus 记录节点上报的配置应用结果
+func ReportApplyStatus(n *entity.Node, report *config.ApplyReport) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	if report.Status != config.ApplyOk {
+		report.Status = config.ApplyFailed
+	}
+	err := console_sqlite3.SetNodeApplyStatus(n.NodeID, report, now)
+	if err != nil {
+		return err
+	}
+	if report.Status == config.ApplyFailed {
+		if r := vc.getRollout(); r != nil && (report.Version == r.version || report.Version == "") {
+			ReportRolloutFailure(n.NodeID, report.Error)
+		}
+	}
+	return nil
+}
+
+//GetNodeConfigStatus 获取各节点的配置应用状态及与期望版本的差异
+func GetNodeConfigStatus() ([]*entity.NodeConfigStatus, error) {
+	nodes, err := console_sqlite3.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	status, err := console_sqlite3.GetNodeApplyStatus()
+	if err != nil {
+		return nil, err
+	}
+	node.ResetNodeStatus(nodes...)
+
+	vc.lock.RLock()
+	defer vc.lock.RUnlock()
+	result := make([]*entity.NodeConfigStatus, 0, len(nodes))
+	for _, n := range nodes {
+		ns := &entity.NodeConfigStatus{
+			NodeID:          n.NodeID,
+			NodeName:        n.NodeName,
+			NodeIP:          n.NodeIP,
+			NodePort:        n.NodePort,
+			Cluster:         n.Cluster,
+			NodeStatus:      n.NodeStatus,
+			ExpectVersionID: vc.versionID,
+			Plugins:         make([]*config.PluginLoadStatus, 0),
+		}
+		expect := vc.version
+		if r := vc.rollout; r != nil && r.selected(n.NodeID, n.GroupID) {
+			ns.ExpectVersionID = r.info.VersionID
+			expect = r.version
+		}
+		s, has := status[n.NodeID]
+		if !has {
+			ns.Drift = DriftUnknown
+			result = append(result, ns)
+			continue
+		}
+		ns.Version = s.Version
+		ns.VersionID = vc.versions[s.Version]
+		ns.ApplyStatus = s.Status
+		ns.Error = s.Error
+		ns.ReportTime = s.ReportTime
+		if s.Plugins != nil {
+			ns.Plugins = s.Plugins
+		}
+		switch {
+		case s.Status == config.ApplyFailed:
+			ns.Drift = DriftFailed
+		case s.Version != expect:
+			ns.Drift = DriftOutdated
+		}
+		result = append(result, ns)
+	}
+	return result, nil
+}
@@ -0,0 +1,223 @@
+package versionConfig
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+)
+
+const (
+	//DiffAdded 新增
+	DiffAdded = "added"
+	//DiffRemoved 删除
+	DiffRemoved = "removed"
+	//DiffChanged 修改
+	DiffChanged = "changed"
+)
+
+//VersionDiff 版本差异
+type VersionDiff struct {
+	From        int         `json:"from"`
+	To          int         `json:"to"`
+	APIs        []*DiffItem `json:"apis"`
+	Strategies  []*DiffItem `json:"strategies"`
+	Plugins     []*DiffItem `json:"plugins"`
+	Balances    []*DiffItem `json:"balances"`
+	Discoveries []*DiffItem `json:"discoveries"`
+	Others      []*DiffItem `json:"others"`
+}
+
+//DiffItem 差异项
+type DiffItem struct {
+	Key    string       `json:"key"`
+	Name   string       `json:"name"`
+	Type   string       `json:"type"` // added | removed | changed
+	Fields []*FieldDiff `json:"fields,omitempty"`
+}
+
+//FieldDiff 字段差异，Field为以 . 分隔的字段路径
+type FieldDiff struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type diffEntry struct {
+	name  string
+	value interface{}
+}
+
+//GetVersionDiff 获取两个版本之间的差异
+func GetVersionDiff(from, to int) (*VersionDiff, error) {
+	fc, fb, fd, err := console_sqlite3.GetVersionConfigByID(from)
+	if err != nil {
+		return nil, err
+	}
+	tc, tb, td, err := console_sqlite3.GetVersionConfigByID(to)
+	if err != nil {
+		return nil, err
+	}
+	return diffVersion(from, to, fc, tc, fb, tb, fd, td), nil
+}
+
+func diffVersion(from, to int, fc, tc *config.GokuConfig, fb, tb map[string]map[string]*config.BalanceConfig, fd, td map[string]map[string]*config.DiscoverConfig) *VersionDiff {
+	return &VersionDiff{
+		From:        from,
+		To:          to,
+		APIs:        diffEntries(apiEntries(fc), apiEntries(tc)),
+		Strategies:  diffEntries(strategyEntries(fc), strategyEntries(tc)),
+		Plugins:     diffEntries(pluginEntries(fc), pluginEntries(tc)),
+		Balances:    diffEntries(balanceEntries(fb), balanceEntries(tb)),
+		Discoveries: diffEntries(discoverEntries(fd), discoverEntries(td)),
+		Others:      diffEntries(otherEntries(fc), otherEntries(tc)),
+	}
+}
+
+func apiEntries(c *config.GokuConfig) map[string]*diffEntry {
+	entries := make(map[string]*diffEntry)
+	for _, api := range c.APIS {
+		entries[strconv.Itoa(api.ID)] = &diffEntry{name: api.Name, value: api}
+	}
+	return entries
+}
+
+func strategyEntries(c *config.GokuConfig) map[string]*diffEntry {
+	entries := make(map[string]*diffEntry)
+	for _, s := range c.Strategy {
+		// 接口及插件按ID/名称展开，便于定位到具体的变更
+		apis := make(map[string]interface{})
+		for _, api := range s.APIS {
+			apis[strconv.Itoa(api.ID)] = api
+		}
+		plugins := make(map[string]interface{})
+		for _, p := range s.Plugins {
+			plugins[p.Name] = p
+		}
+		entries[s.ID] = &diffEntry{name: s.Name, value: map[string]interface{}{
+			"name":     s.Name,
+			"enable":   s.Enable,
+			"auth":     s.AUTH,
+			"apis":     apis,
+			"plugins":  plugins,
+			"cors":     s.CORS,
+			"ipAccess": s.IPAccess,
+		}}
+	}
+	return entries
+}
+
+func pluginEntries(c *config.GokuConfig) map[string]*diffEntry {
+	entries := make(map[string]*diffEntry)
+	if c.Plugins == nil {
+		return entries
+	}
+	for _, p := range c.Plugins.BeforePlugins {
+		entries["before:"+p.Name] = &diffEntry{name: p.Name, value: p}
+	}
+	for _, p := range c.Plugins.GlobalPlugins {
+		entries["global:"+p.Name] = &diffEntry{name: p.Name, value: p}
+	}
+	return entries
+}
+
+func balanceEntries(b map[string]map[string]*config.BalanceConfig) map[string]*diffEntry {
+	entries := make(map[string]*diffEntry)
+	for cluster, balances := range b {
+		for name, balance := range balances {
+			entries[cluster+"/"+name] = &diffEntry{name: name, value: balance}
+		}
+	}
+	return entries
+}
+
+func discoverEntries(d map[string]map[string]*config.DiscoverConfig) map[string]*diffEntry {
+	entries := make(map[string]*diffEntry)
+	for cluster, discoveries := range d {
+		for name, discovery := range discoveries {
+			entries[cluster+"/"+name] = &diffEntry{name: name, value: discovery}
+		}
+	}
+	return entries
+}
+
+func otherEntries(c *config.GokuConfig) map[string]*diffEntry {
+	return map[string]*diffEntry{
+		"anonymousStrategyID": {name: "anonymousStrategyID", value: c.AnonymousStrategyID},
+		"authPlugin":          {name: "authPlugin", value: c.AuthPlugin},
+		"trustedProxies":      {name: "trustedProxies", value: c.TrustedProxies},
+		"monitor":             {name: "monitor", value: c.Monitor},
+		"log":                 {name: "log", value: c.Log},
+		"accessLog":           {name: "accessLog", value: c.AccessLog},
+	}
+}
+
+func diffEntries(from, to map[string]*diffEntry) []*DiffItem {
+	items := make([]*DiffItem, 0)
+	for key, f := range from {
+		t, has := to[key]
+		if !has {
+			items = append(items, &DiffItem{Key: key, Name: f.name, Type: DiffRemoved})
+			continue
+		}
+		fields := diffValue("", normalize(f.value), normalize(t.value), nil)
+		if len(fields) > 0 {
+			items = append(items, &DiffItem{Key: key, Name: t.name, Type: DiffChanged, Fields: fields})
+		}
+	}
+	for key, t := range to {
+		if _, has := from[key]; !has {
+			items = append(items, &DiffItem{Key: key, Name: t.name, Type: DiffAdded})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Key < items[j].Key
+	})
+	return items
+}
+
+// normalize 转换为json通用结构，便于逐字段比较
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var n interface{}
+	if err := json.Unmarshal(data, &n); err != nil {
+		return v
+	}
+	return n
+}
+
+func diffValue(path string, from, to interface{}, fields []*FieldDiff) []*FieldDiff {
+	fm, fok := from.(map[string]interface{})
+	tm, tok := to.(map[string]interface{})
+	if !fok || !tok {
+		if !reflect.DeepEqual(from, to) {
+			fields = append(fields, &FieldDiff{Field: path, From: from, To: to})
+		}
+		return fields
+	}
+
+	keys := make([]string, 0, len(fm)+len(tm))
+	for k := range fm {
+		keys = append(keys, k)
+	}
+	for k := range tm {
+		if _, has := fm[k]; !has {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sub := k
+		if path != "" {
+			sub = path + "." + k
+		}
+		fields = diffValue(sub, fm[k], tm[k], fields)
+	}
+	return fields
+}
@@ -0,0 +1,52 @@
+package versionConfig
+
+import (
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestDiffVersion(t *testing.T) {
+	from := &config.GokuConfig{
+		APIS: []*config.APIContent{
+			{ID: 1, Name: "a", RequestURL: "/a"},
+			{ID: 2, Name: "b", RequestURL: "/b"},
+		},
+		Strategy: []*config.StrategyConfig{
+			{ID: "s1", Name: "s1", Enable: true, APIS: []*config.APIOfStrategy{{ID: 1, Balance: "b1"}}},
+		},
+	}
+	to := &config.GokuConfig{
+		APIS: []*config.APIContent{
+			{ID: 1, Name: "a", RequestURL: "/a2"},
+			{ID: 3, Name: "c", RequestURL: "/c"},
+		},
+		Strategy: []*config.StrategyConfig{
+			{ID: "s1", Name: "s1", Enable: true, APIS: []*config.APIOfStrategy{{ID: 1, Balance: "b2"}}},
+		},
+	}
+	balances := map[string]map[string]*config.BalanceConfig{
+		"default": {"b1": {Name: "b1", Config: "127.0.0.1:80"}},
+	}
+
+	diff := diffVersion(1, 2, from, to, balances, nil, nil, nil)
+
+	if len(diff.APIs) != 3 {
+		t.Fatalf("apis: %d", len(diff.APIs))
+	}
+	expect := map[string]string{"1": DiffChanged, "2": DiffRemoved, "3": DiffAdded}
+	for _, item := range diff.APIs {
+		if expect[item.Key] != item.Type {
+			t.Errorf("api %s: got %s", item.Key, item.Type)
+		}
+	}
+	if f := diff.APIs[0].Fields; len(f) != 1 || f[0].Field != "requestUrl" {
+		t.Errorf("api fields: %v", f)
+	}
+	if len(diff.Strategies) != 1 || len(diff.Strategies[0].Fields) != 1 || diff.Strategies[0].Fields[0].Field != "apis.1.balance" {
+		t.Errorf("strategies: %v", diff.Strategies)
+	}
+	if len(diff.Balances) != 1 || diff.Balances[0].Key != "default/b1" || diff.Balances[0].Type != DiffRemoved {
+		t.Errorf("balances: %v", diff.Balances)
+	}
+}
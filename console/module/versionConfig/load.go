@@ -2,12 +2,17 @@ package versionConfig
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+
 	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
 
 	"github.com/eolinker/goku-api-gateway/common/telegraph"
@@ -20,6 +25,20 @@ import (
 type versionConfig struct {
 	config map[string]*telegraph.Telegraph
 	lock   sync.RWMutex
+
+	version   string // 当前发布配置下发给节点的版本号，由发布版本ID及递增序号组成
+	versionID int
+	digest    string // 当前下发配置的摘要，配置未变化时不更新版本号
+	seq       int
+
+	rollout  *rolloutConfig
+	nodes    map[int]*nodeVersion
+	versions map[string]int // 下发的版本号与版本ID的对应关系
+}
+
+type nodeVersion struct {
+	version string
+	time    time.Time
 }
 
 var (
@@ -28,8 +47,10 @@ var (
 
 func init() {
 	vc = &versionConfig{
-		config: make(map[string]*telegraph.Telegraph),
-		lock:   sync.RWMutex{},
+		config:   make(map[string]*telegraph.Telegraph),
+		lock:     sync.RWMutex{},
+		nodes:    make(map[int]*nodeVersion),
+		versions: make(map[string]int),
 	}
 }
 
@@ -43,6 +64,10 @@ func (c *versionConfig) getConfig(cluster string, version string) []byte {
 	v, ok := c.config[cluster]
 	c.lock.RUnlock()
 
+	return c.wait(v, ok, cluster, version)
+}
+
+func (c *versionConfig) wait(v *telegraph.Telegraph, ok bool, cluster string, version string) []byte {
 	if ok {
 		ctx, _ := context.WithTimeout(context.Background(), time.Second*10)
 
@@ -69,9 +94,82 @@ func GetVersionConfig(cluster, version string) []byte {
 	return vc.getConfig(cluster, version)
 }
 
-func (c *versionConfig) reset(clusters []*entity.Cluster, gokuConfig *config.GokuConfig, balanceConfig map[string]map[string]*config.BalanceConfig, discoverConfig map[string]map[string]*config.DiscoverConfig) {
+//GetNodeVersionConfig 获取节点的版本配置，灰度发布中的节点获取灰度版本
+func GetNodeVersionConfig(node *entity.Node, version string) []byte {
+	return vc.getNodeConfig(node, version)
+}
+
+//reset 更新下发的配置，发布版本及配置内容均未变化时保持版本号，节点无需重新应用
+func (c *versionConfig) reset(clusters []*entity.Cluster, gokuConfig *config.GokuConfig, balanceConfig map[string]map[string]*config.BalanceConfig, discoverConfig map[string]map[string]*config.DiscoverConfig, versionID int) {
+	digest := configDigest(buildClusterConfig(clusters, gokuConfig, balanceConfig, discoverConfig, ""))
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.prune(time.Now())
+	if digest == c.digest && versionID == c.versionID {
+		return
+	}
+	c.seq++
+	version := fmt.Sprintf("%d-%d", versionID, c.seq)
+	newConfig := buildClusterConfig(clusters, gokuConfig, balanceConfig, discoverConfig, version)
+	c.version = version
+	c.versionID = versionID
+	c.digest = digest
+	c.versions[version] = versionID
+
+	for name, cf := range c.config {
+		if _, has := newConfig[name]; !has {
+			cf.Close()
+			delete(c.config, name)
+		}
+	}
+	for name, cs := range newConfig {
+		cf, has := c.config[name]
+		if !has {
+			cf = telegraph.NewTelegraph(version, cs)
+			c.config[name] = cf
+		} else {
+			cf.Set(version, cs)
+		}
+	}
+}
+
+// prune 需持有锁调用，清理不再下发的版本号及心跳超时的节点
+func (c *versionConfig) prune(now time.Time) {
+	for version := range c.versions {
+		if version != c.version && (c.rollout == nil || version != c.rollout.version) {
+			delete(c.versions, version)
+		}
+	}
+	for id, nv := range c.nodes {
+		if now.Sub(nv.time) > node.EXPIRE {
+			delete(c.nodes, id)
+		}
+	}
+}
+
+// configDigest 各集群配置的摘要
+func configDigest(clusterConfig map[string][]byte) string {
+	data, _ := json.Marshal(clusterConfig)
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func load() {
+	clusters, err := console_sqlite3.GetClusters()
+	if err != nil {
+		return
+	}
+	cf, bf, df, err := console_sqlite3.GetVersionConfig()
+	if err != nil {
+		log.Warn("load config error:", err)
+		return
+	}
+	vc.reset(clusters, cf, bf, df, console_sqlite3.GetPublishVersionID())
+	loadRollout(clusters)
+}
+
+func buildClusterConfig(clusters []*entity.Cluster, gokuConfig *config.GokuConfig, balanceConfig map[string]map[string]*config.BalanceConfig, discoverConfig map[string]map[string]*config.DiscoverConfig, version string) map[string][]byte {
 	newConfig := make(map[string][]byte)
-	now := time.Now().Format("20060102150405")
 	for _, cl := range clusters {
 		bf := make(map[string]*config.BalanceConfig)
 		if v, ok := balanceConfig[cl.Name]; ok {
@@ -82,7 +180,7 @@ func (c *versionConfig) reset(clusters []*entity.Cluster, gokuConfig *config.Gok
 			df = v
 		}
 		configByte, _ := json.Marshal(&config.GokuConfig{
-			Version:             now,
+			Version:             version,
 			Cluster:             cl.Name,
 			DiscoverConfig:      df,
 			Balance:             bf,
@@ -91,40 +189,12 @@ func (c *versionConfig) reset(clusters []*entity.Cluster, gokuConfig *config.Gok
 			Strategy:            gokuConfig.Strategy,
 			AuthPlugin:          gokuConfig.AuthPlugin,
 			AnonymousStrategyID: gokuConfig.AnonymousStrategyID,
+			TrustedProxies:      gokuConfig.TrustedProxies,
+			Monitor:             gokuConfig.Monitor,
 			Log:                 gokuConfig.Log,
 			AccessLog:           gokuConfig.AccessLog,
 		})
 		newConfig[cl.Name] = configByte
 	}
-	c.lock.Lock()
-
-	for name, cf := range vc.config {
-		if _, has := newConfig[name]; !has {
-			cf.Close()
-			delete(vc.config, name)
-		}
-	}
-	for name, cs := range newConfig {
-		cf, has := vc.config[name]
-		if !has {
-			cf = telegraph.NewTelegraph(now, cs)
-			vc.config[name] = cf
-		} else {
-			cf.Set(now, cs)
-		}
-	}
-	c.lock.Unlock()
-}
-
-func load() {
-	clusters, err := console_sqlite3.GetClusters()
-	if err != nil {
-		return
-	}
-	cf, bf, df, err := console_sqlite3.GetVersionConfig()
-	if err != nil {
-		log.Warn("load config error:", err)
-		return
-	}
-	vc.reset(clusters, cf, bf, df)
+	return newConfig
 }
@@ -0,0 +1,396 @@
+package versionConfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/telegraph"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const rolloutCheckPeriod = time.Second * 5
+
+var (
+	errorRolloutRunning     = errors.New("there is a running rollout")
+	errorNoRunningRollout   = errors.New("no running rollout")
+	errorRolloutSameVersion = errors.New("rollout version is the same as the published version")
+	errorRolloutTarget      = errors.New("illegal rollout target")
+)
+
+type rolloutConfig struct {
+	info    *entity.Rollout
+	version string
+	config  map[string]*telegraph.Telegraph
+	groups  map[int]bool
+	nodes   map[int]bool
+	served  map[int]time.Time // 已下发灰度配置的节点
+	failed  map[int]string    // 应用灰度配置失败的节点及原因
+	cancel  context.CancelFunc
+}
+
+func newRolloutConfig(info *entity.Rollout, version string, data map[string][]byte) *rolloutConfig {
+	r := &rolloutConfig{
+		info:    info,
+		version: version,
+		config:  make(map[string]*telegraph.Telegraph),
+		groups:  make(map[int]bool),
+		nodes:   make(map[int]bool),
+		served:  make(map[int]time.Time),
+		failed:  make(map[int]string),
+	}
+	for name, cs := range data {
+		r.config[name] = telegraph.NewTelegraph(version, cs)
+	}
+	for _, id := range info.Target.GroupIDs {
+		r.groups[id] = true
+	}
+	for _, id := range info.Target.NodeIDs {
+		r.nodes[id] = true
+	}
+	return r
+}
+
+//selected 判断节点是否在灰度范围内
+func (r *rolloutConfig) selected(nodeID, groupID int) bool {
+	switch r.info.TargetType {
+	case entity.RolloutTargetGroup:
+		return r.groups[groupID]
+	case entity.RolloutTargetNode:
+		return r.nodes[nodeID]
+	case entity.RolloutTargetPercent:
+		return nodeBucket(nodeID) < r.info.Target.Percent
+	}
+	return false
+}
+
+// nodeBucket 节点所在的百分比分桶，同一节点在不同的灰度中保持一致
+func nodeBucket(nodeID int) int {
+	h := fnv.New32a()
+	h.Write([]byte(strconv.Itoa(nodeID)))
+	return int(h.Sum32() % 100)
+}
+
+func (r *rolloutConfig) close() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	for _, cf := range r.config {
+		cf.Close()
+	}
+}
+
+func (c *versionConfig) getNodeConfig(n *entity.Node, version string) []byte {
+	now := time.Now()
+	c.lock.Lock()
+	c.nodes[n.NodeID] = &nodeVersion{version: version, time: now}
+	var v *telegraph.Telegraph
+	ok := false
+	if r := c.rollout; r != nil && r.selected(n.NodeID, n.GroupID) {
+		v, ok = r.config[n.Cluster]
+		if _, has := r.served[n.NodeID]; ok && !has {
+			r.served[n.NodeID] = now
+		}
+	}
+	if !ok {
+		v, ok = c.config[n.Cluster]
+	}
+	c.lock.Unlock()
+
+	return c.wait(v, ok, n.Cluster, version)
+}
+
+func (c *versionConfig) setRollout(r *rolloutConfig) {
+	var ctx context.Context
+	if r != nil {
+		ctx, r.cancel = context.WithCancel(context.Background())
+	}
+	c.lock.Lock()
+	old := c.rollout
+	c.rollout = r
+	if r != nil {
+		c.versions[r.version] = r.info.VersionID
+	}
+	c.prune(time.Now())
+	c.lock.Unlock()
+
+	if old != nil {
+		old.close()
+	}
+	if r != nil {
+		go c.watchRollout(ctx, r)
+	}
+}
+
+func (c *versionConfig) getRollout() *rolloutConfig {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.rollout
+}
+
+func (c *versionConfig) watchRollout(ctx context.Context, r *rolloutConfig) {
+	ticker := time.NewTicker(rolloutCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.checkRollout(r)
+		}
+	}
+}
+
+//checkRollout 检查灰度节点的配置应用情况，失败节点数达到阈值时自动终止灰度
+func (c *versionConfig) checkRollout(r *rolloutConfig) {
+	now := time.Now()
+	timeout := time.Duration(r.info.ApplyTimeout) * time.Second
+	c.lock.Lock()
+	for id, t := range r.served {
+		if _, has := r.failed[id]; has {
+			continue
+		}
+		nv, has := c.nodes[id]
+		if !has || now.Sub(nv.time) > node.EXPIRE {
+			r.failed[id] = "node lost heartbeat"
+			continue
+		}
+		if nv.version != r.version && now.Sub(t) > timeout {
+			r.failed[id] = "config not applied in time"
+		}
+	}
+	reason := failureReason(r)
+	c.lock.Unlock()
+
+	if reason != "" {
+		abortRollout(r.info.ID, reason)
+	}
+}
+
+// failureReason 需持有锁调用，未达到自动终止条件时返回空
+func failureReason(r *rolloutConfig) string {
+	if !r.info.AutoAbort || len(r.failed) < r.info.FailureThreshold {
+		return ""
+	}
+	return fmt.Sprintf("auto abort: %d node(s) failed to apply config", len(r.failed))
+}
+
+func abortRollout(id int, reason string) {
+	err := console_sqlite3.AbortRollout(id, reason, time.Now().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		log.Warn("abort rollout error:", err)
+		return
+	}
+	load()
+}
+
+func loadRollout(clusters []*entity.Cluster) {
+	has, info, err := console_sqlite3.GetRunningRollout()
+	if err != nil {
+		log.Warn("load rollout error:", err)
+		return
+	}
+	if !has {
+		vc.setRollout(nil)
+		return
+	}
+	if current := vc.getRollout(); current != nil && current.info.ID == info.ID {
+		return
+	}
+	cf, bf, df, err := console_sqlite3.GetVersionConfigByID(info.VersionID)
+	if err != nil {
+		log.Warn("load rollout config error:", err)
+		return
+	}
+	version := fmt.Sprintf("%d-rollout-%d", info.VersionID, info.ID)
+	vc.setRollout(newRolloutConfig(info, version, buildClusterConfig(clusters, cf, bf, df, version)))
+}
+
+func checkRolloutTarget(r *entity.Rollout) error {
+	switch r.TargetType {
+	case entity.RolloutTargetGroup:
+		if len(r.Target.GroupIDs) == 0 {
+			return errorRolloutTarget
+		}
+		r.Target.Percent, r.Target.NodeIDs = 0, nil
+	case entity.RolloutTargetNode:
+		if len(r.Target.NodeIDs) == 0 {
+			return errorRolloutTarget
+		}
+		r.Target.Percent, r.Target.GroupIDs = 0, nil
+	case entity.RolloutTargetPercent:
+		if r.Target.Percent < 1 || r.Target.Percent > 99 {
+			return errorRolloutTarget
+		}
+		r.Target.GroupIDs, r.Target.NodeIDs = nil, nil
+	default:
+		return errorRolloutTarget
+	}
+	return nil
+}
+
+//StartRollout 开始灰度发布
+func StartRollout(r *entity.Rollout) (int, error) {
+	if err := checkRolloutTarget(r); err != nil {
+		return 0, err
+	}
+	if _, _, _, err := console_sqlite3.GetVersionConfigByID(r.VersionID); err != nil {
+		return 0, errorVersionNotExist
+	}
+	has, _, err := console_sqlite3.GetRunningRollout()
+	if err != nil {
+		return 0, err
+	}
+	if has {
+		return 0, errorRolloutRunning
+	}
+	r.BaseVersionID = console_sqlite3.GetPublishVersionID()
+	if r.BaseVersionID == r.VersionID {
+		return 0, errorRolloutSameVersion
+	}
+	if r.FailureThreshold < 1 {
+		r.FailureThreshold = 1
+	}
+	if r.ApplyTimeout < 1 {
+		r.ApplyTimeout = 60
+	}
+	id, err := console_sqlite3.AddRollout(r)
+	if err != nil {
+		return 0, err
+	}
+	load()
+	return id, nil
+}
+
+//PromoteRollout 将灰度版本发布到全部节点
+func PromoteRollout(userID int, now string) error {
+	has, info, err := console_sqlite3.GetRunningRollout()
+	if err != nil {
+		return err
+	}
+	if !has {
+		return errorNoRunningRollout
+	}
+	err = console_sqlite3.PromoteRollout(info.ID, info.VersionID, userID, now)
+	if err != nil {
+		return err
+	}
+	load()
+	return nil
+}
+
+//AbortRollout 终止灰度发布，灰度节点恢复为当前发布的版本
+func AbortRollout(reason, now string) error {
+	has, info, err := console_sqlite3.GetRunningRollout()
+	if err != nil {
+		return err
+	}
+	if !has {
+		return errorNoRunningRollout
+	}
+	err = console_sqlite3.AbortRollout(info.ID, reason, now)
+	if err != nil {
+		return err
+	}
+	load()
+	return nil
+}
+
+// abortRunningRollout 发布/回滚版本前终止进行中的灰度
+func abortRunningRollout(reason, now string) error {
+	err := AbortRollout(reason, now)
+	if err == errorNoRunningRollout {
+		return nil
+	}
+	return err
+}
+
+//ReportRolloutFailure 节点上报灰度配置应用失败
+func ReportRolloutFailure(nodeID int, reason string) {
+	vc.reportRolloutFailure(nodeID, reason)
+}
+
+func (c *versionConfig) reportRolloutFailure(nodeID int, reason string) {
+	c.lock.Lock()
+	r := c.rollout
+	if r == nil {
+		c.lock.Unlock()
+		return
+	}
+	if _, has := r.served[nodeID]; !has {
+		c.lock.Unlock()
+		return
+	}
+	r.failed[nodeID] = reason
+	abortReason := failureReason(r)
+	c.lock.Unlock()
+
+	if abortReason != "" {
+		abortRollout(r.info.ID, abortReason)
+	}
+}
+
+//GetRolloutList 获取灰度发布记录
+func GetRolloutList() ([]*entity.Rollout, error) {
+	return console_sqlite3.GetRolloutList()
+}
+
+//GetRolloutStatus 获取进行中（或最近一次）的灰度发布及各节点运行的版本
+func GetRolloutStatus() (*entity.Rollout, []*entity.RolloutNode, error) {
+	has, info, err := console_sqlite3.GetRunningRollout()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !has {
+		list, err := console_sqlite3.GetRolloutList()
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(list) > 0 {
+			info = list[0]
+		}
+	}
+	nodes, err := console_sqlite3.GetAllNodes()
+	if err != nil {
+		return nil, nil, err
+	}
+	node.ResetNodeStatus(nodes...)
+
+	vc.lock.RLock()
+	defer vc.lock.RUnlock()
+	r := vc.rollout
+	if r != nil && (info == nil || r.info.ID != info.ID) {
+		r = nil
+	}
+	result := make([]*entity.RolloutNode, 0, len(nodes))
+	for _, n := range nodes {
+		rn := &entity.RolloutNode{
+			NodeID:          n.NodeID,
+			NodeName:        n.NodeName,
+			NodeIP:          n.NodeIP,
+			NodePort:        n.NodePort,
+			Cluster:         n.Cluster,
+			GroupID:         n.GroupID,
+			NodeStatus:      n.NodeStatus,
+			ExpectVersionID: vc.versionID,
+		}
+		if r != nil && r.selected(n.NodeID, n.GroupID) {
+			rn.InRollout = true
+			rn.ExpectVersionID = r.info.VersionID
+			rn.FailReason, rn.Failed = r.failed[n.NodeID]
+		}
+		if nv, has := vc.nodes[n.NodeID]; has {
+			rn.RunningVersionID = vc.versions[nv.version]
+			rn.LastPollTime = nv.time.Format("2006-01-02 15:04:05")
+		}
+		result = append(result, rn)
+	}
+	return info, result, nil
+}
@@ -0,0 +1,93 @@
+package versionConfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/telegraph"
+	"github.com/eolinker/goku-api-gateway/config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func TestRolloutSelected(t *testing.T) {
+	group := newRolloutConfig(&entity.Rollout{TargetType: entity.RolloutTargetGroup, Target: entity.RolloutTarget{GroupIDs: []int{2}}}, "v", nil)
+	if !group.selected(1, 2) || group.selected(1, 3) {
+		t.Error("group target")
+	}
+	nodes := newRolloutConfig(&entity.Rollout{TargetType: entity.RolloutTargetNode, Target: entity.RolloutTarget{NodeIDs: []int{5, 7}}}, "v", nil)
+	if !nodes.selected(7, 0) || nodes.selected(6, 0) {
+		t.Error("node target")
+	}
+
+	percent := newRolloutConfig(&entity.Rollout{TargetType: entity.RolloutTargetPercent, Target: entity.RolloutTarget{Percent: 30}}, "v", nil)
+	count := 0
+	for id := 1; id <= 1000; id++ {
+		if percent.selected(id, 0) {
+			count++
+		}
+		if percent.selected(id, 0) != (nodeBucket(id) < 30) {
+			t.Fatalf("node %d: unstable selection", id)
+		}
+	}
+	if count < 200 || count > 400 {
+		t.Errorf("percent target selected %d of 1000", count)
+	}
+
+	for _, r := range []*entity.Rollout{
+		{TargetType: entity.RolloutTargetGroup},
+		{TargetType: entity.RolloutTargetPercent, Target: entity.RolloutTarget{Percent: 100}},
+		{TargetType: "cluster"},
+	} {
+		if checkRolloutTarget(r) == nil {
+			t.Errorf("expect target error: %+v", r)
+		}
+	}
+}
+
+func TestReportRolloutFailure(t *testing.T) {
+	c := &versionConfig{nodes: make(map[int]*nodeVersion), versions: make(map[string]int)}
+	c.reportRolloutFailure(1, "no rollout")
+
+	r := newRolloutConfig(&entity.Rollout{TargetType: entity.RolloutTargetNode, Target: entity.RolloutTarget{NodeIDs: []int{1}}}, "v", nil)
+	r.served[1] = time.Now()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			c.reportRolloutFailure(1, "apply error")
+		}
+	}()
+	c.setRollout(r)
+	<-done
+	c.reportRolloutFailure(1, "apply error")
+	c.reportRolloutFailure(2, "not served")
+	c.setRollout(nil)
+	if len(r.failed) != 1 || r.failed[1] != "apply error" {
+		t.Errorf("failed nodes: %v", r.failed)
+	}
+}
+
+func TestVersionReset(t *testing.T) {
+	c := &versionConfig{config: make(map[string]*telegraph.Telegraph), nodes: make(map[int]*nodeVersion), versions: make(map[string]int)}
+	clusters := []*entity.Cluster{{Name: "default"}}
+	c.reset(clusters, &config.GokuConfig{}, nil, nil, 1)
+	first := c.version
+	c.nodes[1] = &nodeVersion{version: first, time: time.Now().Add(-time.Hour)}
+	c.reset(clusters, &config.GokuConfig{}, nil, nil, 1)
+	if c.version != first {
+		t.Errorf("expect unchanged config to keep version %s, got %s", first, c.version)
+	}
+	if _, has := c.nodes[1]; has {
+		t.Error("expect expired node to be pruned")
+	}
+
+	c.reset(clusters, &config.GokuConfig{AnonymousStrategyID: "s1"}, nil, nil, 1)
+	second := c.version
+	c.reset(clusters, &config.GokuConfig{}, nil, nil, 2)
+	if second == first || c.version == second || c.version == first {
+		t.Errorf("expect changed config to get a new version: %s %s %s", first, second, c.version)
+	}
+	if len(c.versions) != 1 || c.versions[c.version] != 2 {
+		t.Errorf("expect old versions to be pruned: %v", c.versions)
+	}
+}
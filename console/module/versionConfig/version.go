@@ -2,6 +2,7 @@ package versionConfig
 
 import (
 	"encoding/json"
+	"errors"
 
 	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
 	dao_version_config2 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/dao-version-config"
@@ -9,6 +10,11 @@ import (
 	"github.com/eolinker/goku-api-gateway/config"
 )
 
+var (
+	errorNoPreviousVersion = errors.New("no previous published version")
+	errorVersionNotExist   = errors.New("version does not exist")
+)
+
 var authNames = map[string]string{
 	"Oauth2": "goku-oauth2_auth",
 	"Apikey": "goku-apikey_auth",
@@ -30,18 +36,59 @@ func AddVersionConfig(name, version, remark, now string) (int, error) {
 //BatchDeleteVersionConfig 批量删除版本配置
 func BatchDeleteVersionConfig(ids []int) error {
 	publishID := console_sqlite3.GetPublishVersionID()
+	if r := vc.getRollout(); r != nil {
+		// 灰度中的版本不可删除
+		remain := make([]int, 0, len(ids))
+		for _, id := range ids {
+			if id != r.info.VersionID {
+				remain = append(remain, id)
+			}
+		}
+		ids = remain
+	}
 	return console_sqlite3.BatchDeleteVersionConfig(ids, publishID)
 }
 
 //PublishVersion 发布版本
-func PublishVersion(id int, now string) error {
-	err := console_sqlite3.PublishVersion(id, now)
+func PublishVersion(id, userID int, now string) error {
+	if err := abortRunningRollout("superseded by publish", now); err != nil {
+		return err
+	}
+	err := console_sqlite3.PublishVersion(id, userID, now)
 	if err == nil {
 		load()
 	}
 	return err
 }
 
+//RollbackVersion 回滚版本，id为0时回滚到上一个发布的版本，返回回滚后的版本ID
+func RollbackVersion(id, userID int, now string) (int, error) {
+	if id == 0 {
+		previousID, err := console_sqlite3.GetPreviousPublishVersionID(console_sqlite3.GetPublishVersionID())
+		if err != nil {
+			return 0, errorNoPreviousVersion
+		}
+		id = previousID
+	}
+	if _, _, _, err := console_sqlite3.GetVersionConfigByID(id); err != nil {
+		return 0, errorVersionNotExist
+	}
+	if err := abortRunningRollout("superseded by rollback", now); err != nil {
+		return 0, err
+	}
+	err := console_sqlite3.RollbackVersion(id, userID, now)
+	if err != nil {
+		return 0, err
+	}
+	load()
+	return id, nil
+}
+
+//GetPublishHistory 获取发布记录
+func GetPublishHistory() ([]map[string]interface{}, error) {
+	return console_sqlite3.GetPublishHistory()
+}
+
 //GetVersionConfigCount 获取版本配置数量
 func GetVersionConfigCount() int {
 	return console_sqlite3.GetVersionConfigCount()
@@ -76,6 +123,14 @@ func buildVersionConfig(v string) (string, string, string) {
 	if err != nil {
 		return "", "", ""
 	}
+	trustedProxies, err := dao_version_config2.GetTrustedProxies()
+	if err != nil {
+		return "", "", ""
+	}
+	monitor, err := dao_version_config2.GetMonitorConfig()
+	if err != nil {
+		return "", "", ""
+	}
 
 	c := config.GokuConfig{
 		Version:             v,
@@ -84,6 +139,8 @@ func buildVersionConfig(v string) (string, string, string) {
 		Strategy:            strategyConfigs,
 		AnonymousStrategyID: openStrategy,
 		AuthPlugin:          authNames,
+		TrustedProxies:      trustedProxies,
+		Monitor:             monitor,
 		Log:                 logCf,
 		AccessLog:           accessCf,
 	}
@@ -7,15 +7,20 @@ import (
 	"github.com/eolinker/goku-api-gateway/console/controller/gateway"
 
 	"github.com/eolinker/goku-api-gateway/console/controller/account"
+	"github.com/eolinker/goku-api-gateway/console/controller/alert"
 	"github.com/eolinker/goku-api-gateway/console/controller/api"
+	"github.com/eolinker/goku-api-gateway/console/controller/audit"
 	"github.com/eolinker/goku-api-gateway/console/controller/auth"
 	"github.com/eolinker/goku-api-gateway/console/controller/balance"
 	"github.com/eolinker/goku-api-gateway/console/controller/cluster"
+	"github.com/eolinker/goku-api-gateway/console/controller/declarative"
 	"github.com/eolinker/goku-api-gateway/console/controller/discovery"
+	"github.com/eolinker/goku-api-gateway/console/controller/monitor"
 
 	"github.com/eolinker/goku-api-gateway/console/controller/node"
 	"github.com/eolinker/goku-api-gateway/console/controller/plugin"
 	"github.com/eolinker/goku-api-gateway/console/controller/project"
+	"github.com/eolinker/goku-api-gateway/console/controller/rest"
 	"github.com/eolinker/goku-api-gateway/console/controller/strategy"
 )
 
@@ -24,6 +29,10 @@ func Router() {
 
 	// 游客
 	http.HandleFunc("/guest/login", account.Login)
+	http.HandleFunc("/guest/login/ldap", account.LoginLDAP)
+	http.HandleFunc("/guest/oidc/login", account.LoginOIDC)
+	http.HandleFunc("/guest/oidc/callback", account.OIDCCallback)
+	http.HandleFunc("/guest/sso/getInfo", account.GetSSOInfo)
 
 	// 用户
 	http.HandleFunc("/user/logout", account.Logout)
@@ -33,12 +42,23 @@ func Router() {
 	http.HandleFunc("/user/checkIsAdmin", account.CheckUserIsAdmin)
 	http.HandleFunc("/user/checkIsSuperAdmin", account.CheckUserIsSuperAdmin)
 	http.HandleFunc("/user/checkPermission", account.CheckUserPermission)
+	http.HandleFunc("/user/token/add", account.AddAPIToken)
+	http.HandleFunc("/user/token/getList", account.GetAPITokenList)
+	http.HandleFunc("/user/token/delete", account.DeleteAPIToken)
 
 	// 网关
 	//http.HandleFunc("/gateway/config/base/getInfo", gateway.GetGatewayConfig)
 	//http.HandleFunc("/gateway/config/base/edit", gateway.EditGatewayBaseConfig)
-	//http.HandleFunc("/gateway/config/alert/edit", gateway.EditGatewayAlarmConfig)
-	//http.HandleFunc("/gateway/config/alert/getInfo", alert.GetAlertConfig)
+	http.HandleFunc("/gateway/config/alert/edit", gateway.EditGatewayAlarmConfig)
+	http.HandleFunc("/gateway/config/alert/getInfo", alert.GetAlertConfig)
+
+	// 告警
+	http.HandleFunc("/alert/rule/add", alert.AddAlertRule)
+	http.HandleFunc("/alert/rule/edit", alert.EditAlertRule)
+	http.HandleFunc("/alert/rule/delete", alert.DeleteAlertRule)
+	http.HandleFunc("/alert/rule/getInfo", alert.GetAlertRuleInfo)
+	http.HandleFunc("/alert/rule/getList", alert.GetAlertRuleList)
+	http.HandleFunc("/alert/history/getList", alert.GetAlertHistoryList)
 
 	// 项目
 	http.HandleFunc("/project/add", project.AddProject)
@@ -67,6 +87,15 @@ func Router() {
 	http.HandleFunc("/apis/batchEditBalance", api.BatchSetBalanceAPI)
 
 	http.HandleFunc("/apis/manager/getList", api.GetAPIManagerList)
+	http.HandleFunc("/apis/cors/edit", api.EditAPICORS)
+	http.HandleFunc("/apis/cors/getInfo", api.GetAPICORS)
+	http.HandleFunc("/apis/ipAccess/edit", api.EditAPIIPAccess)
+	http.HandleFunc("/apis/ipAccess/getInfo", api.GetAPIIPAccess)
+	http.HandleFunc("/apis/transform/edit", api.EditAPITransform)
+	http.HandleFunc("/apis/transform/getInfo", api.GetAPITransform)
+	http.HandleFunc("/apis/mock/edit", api.EditAPIMock)
+	http.HandleFunc("/apis/mock/getInfo", api.GetAPIMock)
+	http.HandleFunc("/apis/mock/switch", api.SwitchAPIMock)
 
 	// API绑定插件
 	http.HandleFunc("/plugin/api/addPluginToApi", api.AddPluginToAPI)
@@ -94,12 +123,14 @@ func Router() {
 	// 插件
 	http.HandleFunc("/plugin/add", plugin.AddPlugin)
 	http.HandleFunc("/plugin/edit", plugin.EditPlugin)
+	http.HandleFunc("/plugin/policy/edit", plugin.EditPluginPolicy)
 	http.HandleFunc("/plugin/delete", plugin.DeletePlugin)
 	http.HandleFunc("/plugin/checkNameIsExist", plugin.CheckIndexIsExist)
 	http.HandleFunc("/plugin/checkIndexIsExist", plugin.CheckNameIsExist)
 	http.HandleFunc("/plugin/getList", plugin.GetPluginList)
 	http.HandleFunc("/plugin/getInfo", plugin.GetPluginInfo)
 	http.HandleFunc("/plugin/getConfig", plugin.GetPluginConfig)
+	http.HandleFunc("/plugin/getConfigSchema", plugin.GetPluginConfigSchema)
 	http.HandleFunc("/plugin/start", plugin.StartPlugin)
 	http.HandleFunc("/plugin/stop", plugin.StopPlugin)
 	http.HandleFunc("/plugin/getListByType", plugin.GetPluginListByPluginType)
@@ -119,8 +150,18 @@ func Router() {
 	http.HandleFunc("/strategy/batchStart", strategy.BatchStartStrategy)
 	http.HandleFunc("/strategy/batchStop", strategy.BatchStopStrategy)
 	http.HandleFunc("/strategy/id/getList", strategy.GetStrategyIDList)
+	http.HandleFunc("/strategy/cors/edit", strategy.EditStrategyCORS)
+	http.HandleFunc("/strategy/cors/getInfo", strategy.GetStrategyCORS)
+	http.HandleFunc("/strategy/ipAccess/edit", strategy.EditStrategyIPAccess)
+	http.HandleFunc("/strategy/ipAccess/getInfo", strategy.GetStrategyIPAccess)
 
 	http.HandleFunc("/monitor/gateway/getSummaryInfo", gateway.GetGatewayBasicInfo)
+	http.HandleFunc("/monitor/getSummary", monitor.GetMonitorSummary)
+	http.HandleFunc("/monitor/getTrend", monitor.GetMonitorTrend)
+	http.HandleFunc("/monitor/api/getRank", monitor.GetMonitorAPIRank)
+	http.HandleFunc("/monitor/plugin/getList", monitor.GetMonitorPluginList)
+	http.HandleFunc("/gateway/config/trustedProxies/edit", gateway.EditTrustedProxies)
+	http.HandleFunc("/gateway/config/trustedProxies/getInfo", gateway.GetTrustedProxies)
 	// http.HandleFunc("/strategy/openStrategy/getInfo", strategy.GetOpenStrategy)
 
 	// 策略组分组
@@ -133,6 +174,8 @@ func Router() {
 	http.HandleFunc("/strategy/api/add", strategy.AddAPIToStrategy)
 	http.HandleFunc("/strategy/api/target", strategy.ResetAPITargetOfStrategy)
 	http.HandleFunc("/strategy/api/batchEditTarget", strategy.BatchResetAPITargetOfStrategy)
+	http.HandleFunc("/strategy/api/canary/edit", strategy.SetAPICanaryOfStrategy)
+	http.HandleFunc("/strategy/api/canary/getInfo", strategy.GetAPICanaryOfStrategy)
 	http.HandleFunc("/strategy/api/getList", strategy.GetAPIListFromStrategy)
 	http.HandleFunc("/strategy/api/id/getList", strategy.GetAPIIDListFromStrategy)
 	http.HandleFunc("/strategy/api/getNotInList", strategy.GetAPIListNotInStrategy)
@@ -152,6 +195,7 @@ func Router() {
 
 	http.HandleFunc("/node/batchEditGroup", node.BatchEditNodeGroup)
 	http.HandleFunc("/node/batchDelete", node.BatchDeleteNode)
+	http.HandleFunc("/node/token/reset", node.ResetNodeToken)
 
 	// 节点分组
 	http.HandleFunc("/node/group/add", node.AddNodeGroup)
@@ -181,6 +225,8 @@ func Router() {
 	http.HandleFunc("/import/ams/api", api.ImportAPIFromAms)
 	http.HandleFunc("/import/ams/group", api.ImportAPIGroupFromAms)
 	http.HandleFunc("/import/ams/project", api.ImportProjectFromAms)
+	http.HandleFunc("/import/openapi", api.ImportOpenAPI)
+	http.HandleFunc("/export/openapi", api.ExportOpenAPI)
 
 	// 	集群
 	http.HandleFunc("/cluster/add", cluster.AddCluster)
@@ -188,15 +234,36 @@ func Router() {
 	http.HandleFunc("/cluster/delete", cluster.DeleteCluster)
 	http.HandleFunc("/cluster/list", cluster.GetClusterInfoList)
 	http.HandleFunc("/cluster/simpleList", cluster.GetClusterList)
+	http.HandleFunc("/cluster/auth/getInfo", cluster.GetClusterAuth)
+	http.HandleFunc("/cluster/token/reset", cluster.ResetClusterToken)
+	http.HandleFunc("/cluster/register/edit", cluster.EditClusterRegister)
 
 	// 配置发布
 	http.HandleFunc("/version/config/add", cluster.AddVersionConfig)
 	http.HandleFunc("/version/config/getList", cluster.GetVersionList)
 	http.HandleFunc("/version/config/delete", cluster.BatchDeleteVersionConfig)
 	http.HandleFunc("/version/config/publish", cluster.PublishVersion)
+	http.HandleFunc("/version/config/diff", cluster.GetVersionDiff)
+	http.HandleFunc("/version/config/rollback", cluster.RollbackVersion)
+	http.HandleFunc("/version/config/publish/getList", cluster.GetPublishHistory)
+	http.HandleFunc("/version/config/node/getList", cluster.GetNodeConfigStatus)
+	http.HandleFunc("/version/rollout/start", cluster.StartRollout)
+	http.HandleFunc("/version/rollout/promote", cluster.PromoteRollout)
+	http.HandleFunc("/version/rollout/abort", cluster.AbortRollout)
+	http.HandleFunc("/version/rollout/getInfo", cluster.GetRolloutStatus)
+	http.HandleFunc("/version/rollout/getList", cluster.GetRolloutList)
 
 	// 配置
 	http.Handle("/config/log/", config_log.Handle("/config/log/"))
+	http.HandleFunc("/config/export", declarative.ExportConfig)
+	http.HandleFunc("/config/import", declarative.ImportConfig)
+
+	// 审计日志
+	http.HandleFunc("/audit/log/getList", audit.GetAuditLogList)
+	http.HandleFunc("/audit/log/export", audit.ExportAuditLog)
+
+	// REST接口
+	http.Handle(rest.Prefix, rest.Handler())
 	http.HandleFunc("/", http.StripPrefix("/", http.FileServer(http.Dir("./static"))).ServeHTTP)
 
 }
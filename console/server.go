@@ -7,7 +7,9 @@ import (
 
 	"github.com/eolinker/goku-api-gateway/common/conf"
 	"github.com/eolinker/goku-api-gateway/console/admin"
+	"github.com/eolinker/goku-api-gateway/console/controller/audit"
 	"github.com/eolinker/goku-api-gateway/console/module/account"
+	"github.com/eolinker/goku-api-gateway/console/module/alert"
 )
 
 //Server 服务
@@ -24,12 +26,14 @@ func Server() {
 		log.Panic("[ERROR] Illegal admin_bind!")
 	}
 
+	alert.Start()
+
 	port, has := conf.Get("listen_port")
 	if has {
 		go func() {
 			log.Print("Listen: ", port)
 			log.Print("Start Successfully!")
-			err := http.ListenAndServe(":"+port, nil)
+			err := http.ListenAndServe(":"+port, audit.Handler(http.DefaultServeMux))
 
 			ec <- err
 		}()
@@ -25,6 +25,7 @@ type Context struct {
 	strategyID           string
 	strategyName         string
 	apiID                int
+	clientIP             string
 	requestID            string
 	finalTargetServer    string
 	retryTargetServers   string
@@ -206,6 +207,16 @@ func (ctx *Context) SetAPIID(apiId int) {
 	ctx.apiID = apiId
 }
 
+//ClientIP 获取客户端真实IP
+func (ctx *Context) ClientIP() string {
+	return ctx.clientIP
+}
+
+//SetClientIP 设置客户端真实IP
+func (ctx *Context) SetClientIP(clientIP string) {
+	ctx.clientIP = clientIP
+}
+
 //Request 获取原始请求
 func (ctx *Context) Request() goku_plugin.RequestReader {
 	return ctx.RequestOrg
@@ -0,0 +1,43 @@
+package balance
+
+import (
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-service/application"
+	"github.com/eolinker/goku-api-gateway/goku-service/discovery"
+)
+
+//Resolver 按待生效的负载配置及服务发现快照获取负载，用于新配置生效前构建应用
+type Resolver struct {
+	balances map[string]*config.BalanceConfig
+	sources  *discovery.Snapshot
+}
+
+//NewResolver 创建负载查找
+func NewResolver(balances map[string]*config.BalanceConfig, sources *discovery.Snapshot) *Resolver {
+	return &Resolver{
+		balances: balances,
+		sources:  sources,
+	}
+}
+
+//GetByName 通过名称获取负载，Resolver为nil时使用当前生效的负载配置
+func (r *Resolver) GetByName(name string) (application.IHttpApplication, bool) {
+	if r == nil {
+		return GetByName(name)
+	}
+	b, has := r.balances[name]
+	if !has {
+		return application.NewOrg(name), true
+	}
+
+	sources, has := r.sources.GetDiscoverer(b.DiscoverName)
+	if has {
+
+		service, handler, yes := sources.GetApp(b.Config)
+		if yes {
+			return application.NewApplication(service, handler), true
+		}
+	}
+
+	return nil, false
+}
@@ -1,10 +1,10 @@
 package discovery
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/eolinker/goku-api-gateway/config"
-	log "github.com/eolinker/goku-api-gateway/goku-log"
 )
 
 var manager = &Manager{
@@ -17,59 +17,93 @@ type Manager struct {
 	locker sync.RWMutex
 
 	sources map[string]ISource
+	confs   map[string]*config.DiscoverConfig
 }
 
-//ResetAllServiceConfig resetAllServiceConfig
-func ResetAllServiceConfig(confs map[string]*config.DiscoverConfig) {
+//Snapshot 待生效的服务发现配置，Commit前不影响正在使用的数据源
+type Snapshot struct {
+	confs   map[string]*config.DiscoverConfig
+	sources map[string]ISource
+	reused  map[string]bool
+	opened  []ISource
+}
 
-	sources := make(map[string]ISource)
+//Prepare 按配置准备数据源：驱动及配置未变化的数据源直接复用，其余数据源新建，Commit前不会被使用
+func Prepare(confs map[string]*config.DiscoverConfig) (*Snapshot, error) {
+	s := &Snapshot{
+		confs:   make(map[string]*config.DiscoverConfig),
+		sources: make(map[string]ISource),
+		reused:  make(map[string]bool),
+	}
 	manager.locker.RLock()
 	oldSources := manager.sources
+	oldConfs := manager.confs
 	manager.locker.RUnlock()
 	for _, conf := range confs {
 
 		name := conf.Name
-		s, has := oldSources[name]
-		if has && !s.CheckDriver(conf.Driver) {
-			// 如果驱动不一样，关闭旧的
-			has = false
-			s.Close()
-			s = nil
-			delete(oldSources, name)
-		}
-		if !has {
-			driverName := conf.Driver
-			driver, has := drivers[driverName]
-			if !has {
-				log.Error("invalid driver:", driverName)
-				continue
-			}
-			ns, err := driver.Open(name, conf.Config)
-			if err != nil {
+		s.confs[name] = conf
+		if old, has := oldSources[name]; has && old.CheckDriver(conf.Driver) {
+			if oldConf, has := oldConfs[name]; has && oldConf.Config == conf.Config {
+				s.sources[name] = old
+				s.reused[name] = true
 				continue
 			}
-			s = ns
 		}
-
-		sources[name] = s
-		s.SetHealthConfig(conf.HealthCheck)
-
-		err := s.SetDriverConfig(conf.Config)
-
+		driver, has := drivers[conf.Driver]
+		if !has {
+			s.Discard()
+			return nil, fmt.Errorf("discovery %s: invalid driver %s", name, conf.Driver)
+		}
+		ns, err := driver.Open(name, conf.Config)
 		if err != nil {
-			continue
+			s.Discard()
+			return nil, fmt.Errorf("discovery %s: %s", name, err.Error())
 		}
-	}
-
-	for name, s := range oldSources {
-		if _, has := sources[name]; !has {
-			s.Close()
+		s.opened = append(s.opened, ns)
+		ns.SetHealthConfig(conf.HealthCheck)
+		if err := ns.SetDriverConfig(conf.Config); err != nil {
+			s.Discard()
+			return nil, fmt.Errorf("discovery %s: %s", name, err.Error())
 		}
+		s.sources[name] = ns
 	}
+	return s, nil
+}
+
+//GetDiscoverer 获取快照中的数据源
+func (s *Snapshot) GetDiscoverer(discoveryName string) (ISource, bool) {
+	source, has := s.sources[discoveryName]
+	return source, has
+}
 
+//Commit 生效快照：更新复用数据源的健康检查配置，返回被替换的数据源；
+//旧配置上处理中的请求仍可能使用这些数据源，由调用方在其处理完毕后关闭
+func (s *Snapshot) Commit() []ISource {
 	manager.locker.Lock()
-	manager.sources = sources
+	oldSources := manager.sources
+	manager.sources = s.sources
+	manager.confs = s.confs
 	manager.locker.Unlock()
+
+	for name := range s.reused {
+		s.sources[name].SetHealthConfig(s.confs[name].HealthCheck)
+	}
+	replaced := make([]ISource, 0)
+	for name, old := range oldSources {
+		if source, has := s.sources[name]; !has || source != old {
+			replaced = append(replaced, old)
+		}
+	}
+	return replaced
+}
+
+//Discard 放弃快照，关闭新建的数据源
+func (s *Snapshot) Discard() {
+	for _, source := range s.opened {
+		source.Close()
+	}
+	s.opened = nil
 }
 
 //GetDiscoverer getDiscoverer
@@ -8,12 +8,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
 	listener2 "github.com/eolinker/goku-api-gateway/common/listener"
 	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 )
 
 //ConfigCallbackFunc configCallbackFunc
@@ -24,6 +24,8 @@ var (
 	once       sync.Once
 
 	listener = listener2.New()
+
+	errorSignature = errors.New("illegal config signature")
 )
 
 //GetConfig 获取节点配置
@@ -33,7 +35,7 @@ func (c *Console) GetConfig() (*config.GokuConfig, error) {
 	}
 
 	once.Do(func() {
-		listenConfig(c.ctx, c.port, c.adminHost)
+		c.listenConfig()
 	})
 
 	cn := make(chan *config.GokuConfig, 1)
@@ -61,13 +63,9 @@ func (c *Console) AddListen(callback ConfigCallbackFunc) {
 
 }
 
-func listenConfig(ctx context.Context, port int, adminHost string) {
-
-	admin := adminHost
-	admin = strings.TrimPrefix(admin, "http://")
-	admin = strings.TrimSuffix(admin, "/")
+func (c *Console) listenConfig() {
 
-	url := fmt.Sprintf("http://%s/version/config/get", admin)
+	url := adminURL(c.adminHost, "/version/config/get")
 
 	go func() {
 
@@ -75,17 +73,28 @@ func listenConfig(ctx context.Context, port int, adminHost string) {
 		lastVersion := ""
 		for {
 			select {
-			case <-ctx.Done():
+			case <-c.ctx.Done():
 				return
 			default:
 				{
 
-					gokuConfig, err := getConfig(url, port, lastVersion)
+					gokuConfig, err := c.getConfig(url, lastVersion)
 					if err != nil {
-						errNum++
-						time.After(time.Second * time.Duration(errNum))
+						log.Warn("get config error:", err)
+						if isDecodeError(err) {
+							// 配置无法解析，告知控制台
+							c.Report(&config.ApplyReport{
+								Status: config.ApplyFailed,
+								Error:  "decode config error:" + err.Error(),
+							})
+						}
+						if errNum < 10 {
+							errNum++
+						}
+						time.Sleep(time.Second * time.Duration(errNum))
 						continue
 					}
+					errNum = 0
 					if gokuConfig != nil {
 						if lastVersion != gokuConfig.Version {
 							lastVersion = gokuConfig.Version
@@ -102,7 +111,7 @@ func listenConfig(ctx context.Context, port int, adminHost string) {
 	}()
 
 }
-func getConfig(url string, port int, lastVersion string) (*config.GokuConfig, error) {
+func (c *Console) getConfig(url string, lastVersion string) (*config.GokuConfig, error) {
 	req, e := http.NewRequest(http.MethodGet, url, nil)
 
 	if e != nil {
@@ -111,10 +120,14 @@ func getConfig(url string, port int, lastVersion string) (*config.GokuConfig, er
 	}
 
 	q := req.URL.Query()
-	q.Add("port", strconv.Itoa(port))
+	q.Add("port", strconv.Itoa(c.port))
 	q.Add("version", lastVersion)
+	if c.name != "" {
+		q.Add("name", c.name)
+	}
 	req.URL.RawQuery = q.Encode()
-	resp, err := http.DefaultClient.Do(req)
+	c.setAuth(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -126,6 +139,12 @@ func getConfig(url string, port int, lastVersion string) (*config.GokuConfig, er
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d:%s", resp.StatusCode, data)
+	}
+	if c.token != "" && !config.CheckSign(c.token, data, resp.Header.Get(config.SignatureHeader)) {
+		return nil, errorSignature
+	}
 
 	gConfig := new(config.GokuConfig)
 
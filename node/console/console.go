@@ -2,6 +2,12 @@ package console
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"sync"
 )
 
@@ -13,6 +19,10 @@ type Console struct {
 	cancel      context.CancelFunc
 	lastVersion int
 	once        sync.Once
+
+	name   string // 自动注册时使用的节点名称
+	token  string // 集群或节点凭证，同时用于校验配置签名
+	client *http.Client
 }
 
 //Close close
@@ -28,5 +38,56 @@ func NewConsole(port int, adminHost string) *Console {
 		adminHost: adminHost,
 		ctx:       ctx,
 		cancel:    cancel,
+		client:    http.DefaultClient,
+	}
+}
+
+//SetName 设置节点名称
+func (c *Console) SetName(name string) {
+	c.name = name
+}
+
+//SetToken 设置接入凭证，凭证同时是配置签名的密钥，只允许通过https发送给控制台
+func (c *Console) SetToken(token string) error {
+	if token != "" && !strings.HasPrefix(c.adminHost, "https://") {
+		return errors.New("node token requires an https admin address")
+	}
+	c.token = token
+	return nil
+}
+
+//SetTLS 设置访问控制台的CA证书及客户端证书
+func (c *Console) SetTLS(caFile, certFile, keyFile string) error {
+	tlsConfig := &tls.Config{}
+	if caFile != "" {
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return errors.New("illegal ca file:" + caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	c.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	return nil
+}
+
+func (c *Console) setAuth(req *http.Request) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 }
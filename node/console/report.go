@@ -0,0 +1,98 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+)
+
+const reportTimeout = time.Second * 10
+
+func adminURL(adminHost string, path string) string {
+	admin := adminHost
+	scheme := "http"
+	if strings.HasPrefix(admin, "https://") {
+		scheme = "https"
+	}
+	admin = strings.TrimPrefix(admin, scheme+"://")
+	admin = strings.TrimSuffix(admin, "/")
+
+	return fmt.Sprintf("%s://%s%s", scheme, admin, path)
+}
+
+func isDecodeError(err error) bool {
+	switch err.(type) {
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		return true
+	}
+	return false
+}
+
+//Report 上报配置应用结果
+func (c *Console) Report(r *config.ApplyReport) {
+	if err := c.post("/version/config/report", r); err != nil {
+		log.Warn("report apply status error:", err)
+	}
+}
+
+//ReportMonitor 上报监控计数及插件执行计数
+func (c *Console) ReportMonitor(items []*config.MonitorItem, plugins []*config.PluginMonitorItem) error {
+	return c.post("/monitor/report", &config.MonitorReport{Items: items, Plugins: plugins})
+}
+
+func (c *Console) post(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	url := adminURL(c.adminHost, path+"?port="+strconv.Itoa(c.port))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(c.ctx, reportTimeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	c.setAuth(req)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return checkResult(body)
+}
+
+//result 控制台的返回，statusCode为000000表示成功
+type result struct {
+	StatusCode string `json:"statusCode"`
+	ResultDesc string `json:"resultDesc"`
+}
+
+//checkResult 控制台出错时仍返回200，需根据返回内容中的statusCode判断是否成功
+func checkResult(body []byte) error {
+	r := new(result)
+	if err := json.Unmarshal(body, r); err != nil {
+		return err
+	}
+	if r.StatusCode != "000000" {
+		return fmt.Errorf("status code %s: %s", r.StatusCode, r.ResultDesc)
+	}
+	return nil
+}
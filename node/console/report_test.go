@@ -0,0 +1,89 @@
+package console
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestReport(t *testing.T) {
+	var got config.ApplyReport
+	port := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version/config/report" {
+			t.Errorf("path: %s", r.URL.Path)
+		}
+		port = r.URL.Query().Get("port")
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	c := NewConsole(6689, srv.URL+"/")
+	c.Report(&config.ApplyReport{
+		Version: "v1",
+		Status:  config.ApplyFailed,
+		Error:   "parse error",
+		Plugins: []*config.PluginLoadStatus{{Name: "p1", Code: 1, Error: "not found"}},
+	})
+	if port != "6689" || got.Version != "v1" || got.Status != config.ApplyFailed || len(got.Plugins) != 1 {
+		t.Errorf("report: port=%s %+v", port, got)
+	}
+
+	if _, err := c.getConfig(srv.URL+"/version/config/report", ""); err == nil || !isDecodeError(err) {
+		t.Errorf("expect decode error, got %v", err)
+	}
+}
+
+func TestReportMonitorResult(t *testing.T) {
+	code := "700003"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"type":"cluster","statusCode":"` + code + `","resultDesc":"database is locked"}`))
+	}))
+	defer srv.Close()
+
+	c := NewConsole(6689, srv.URL)
+	items := []*config.MonitorItem{{StrategyID: "s1", Hour: 3600, GatewayRequestCount: 1}}
+	if err := c.ReportMonitor(items, nil); err == nil {
+		t.Error("expect error status code to fail the report")
+	}
+	code = "000000"
+	if err := c.ReportMonitor(items, nil); err != nil {
+		t.Errorf("expect report to succeed, got %v", err)
+	}
+}
+
+func TestConfigSignature(t *testing.T) {
+	body := []byte(`{"version":"v2","cluster":"default"}`)
+	signature := config.Sign("secret", body)
+	auth := ""
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Header().Set(config.SignatureHeader, signature)
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	if err := NewConsole(6689, "http://127.0.0.1:7005").SetToken("secret"); err == nil {
+		t.Error("expect token to require an https admin address")
+	}
+	c := NewConsole(6689, srv.URL)
+	c.client = srv.Client()
+	if err := c.SetToken("secret"); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := c.getConfig(srv.URL+"/version/config/get", "")
+	if err != nil || conf.Version != "v2" {
+		t.Fatalf("get config: %v %v", conf, err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("authorization: %s", auth)
+	}
+
+	c.SetToken("other")
+	if _, err := c.getConfig(srv.URL+"/version/config/get", ""); err != errorSignature {
+		t.Errorf("expect signature error, got %v", err)
+	}
+}
@@ -22,6 +22,11 @@ type API struct {
 
 	apiID   int
 	apiName string
+
+	cors      *corsPolicy
+	ipAccess  *ipAccessPolicy
+	transform *transformer
+	authorize func(ctx *common.Context) bool
 }
 
 //Router router
@@ -30,13 +35,30 @@ func (h *API) Router(ctx *common.Context) {
 	ctx.SetAPIID(h.apiID)
 	ctx.LogFields[access_field.API] = fmt.Sprintf("\"%d %s\"", h.apiID, h.apiName)
 
+	if h.cors != nil {
+		h.cors.Apply(ctx)
+	}
+
+	if !h.ipAccess.Check(ctx, "api") {
+		return
+	}
+	if !h.authorize(ctx) {
+		return
+	}
+
 	isAccess := h.accessFlow(ctx)
 	h.accessGlobalFlow(ctx)
 	if !isAccess {
 		return
 	}
 
-	h.app.Execute(ctx)
+	if h.transform != nil {
+		variables := h.transform.Request(ctx)
+		h.app.Execute(ctx)
+		h.transform.Response(ctx, variables)
+	} else {
+		h.app.Execute(ctx)
+	}
 
 	isproxy := h.proxyFlow(ctx)
 	h.proxyGlobalFlow(ctx)
@@ -51,9 +73,9 @@ func (h *API) Router(ctx *common.Context) {
 func (h *API) accessFlow(ctx *common.Context) bool {
 	for _, handler := range h.pluginAccess {
 
-		flag, _ := handler.Execute(ctx)
+		flag, err := handler.Execute(ctx)
 
-		if flag == false && handler.IsStop() {
+		if plugin_executor.ShouldStop(handler, flag, err) {
 
 			return false
 		}
@@ -71,9 +93,9 @@ func (h *API) accessGlobalFlow(ctx *common.Context) {
 func (h *API) proxyFlow(ctx *common.Context) bool {
 	for _, handler := range h.pluginProxies {
 
-		flag, _ := handler.Execute(ctx)
+		flag, err := handler.Execute(ctx)
 
-		if flag == false && handler.IsStop() {
+		if plugin_executor.ShouldStop(handler, flag, err) {
 
 			return false
 		}
@@ -84,7 +84,7 @@ func (b *Layer) Send(ctx *common.Context,variables *interpreter.Variables,deadli
 
 
 
-func NewLayer(step *config.APIStepConfig) *Layer {
+func NewLayer(step *config.APIStepConfig, balances *balance.Resolver) *Layer {
 	var b = &Layer{
 		BalanceName: step.Balance,
 		Balance:     nil,
@@ -105,7 +105,7 @@ func NewLayer(step *config.APIStepConfig) *Layer {
 		b.Group =  strings.Split(step.Group,".")
 	}
 
-	b.Balance, b.HasBalance = balance.GetByName(b.BalanceName)
+	b.Balance, b.HasBalance = balances.GetByName(b.BalanceName)
 
 	return b
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/eolinker/goku-api-gateway/goku-service/application"
 	"github.com/eolinker/goku-api-gateway/goku-service/balance"
 	"io/ioutil"
+	"net"
+	"strconv"
 	"strings"
 
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
@@ -33,7 +35,7 @@ type Proxy struct {
 	TimeOut time.Duration
 
 }
-func NewProxyBackendTarget(step *config.APIStepConfig,requestPath string,balanceTarget string) *Proxy {
+func NewProxyBackendTarget(step *config.APIStepConfig,requestPath string,balanceTarget string,balances *balance.Resolver) *Proxy {
 	b:= &Proxy{
 		BalanceName:balanceTarget,
 		Protocol:step.Proto,
@@ -50,7 +52,7 @@ func NewProxyBackendTarget(step *config.APIStepConfig,requestPath string,balance
 	}
 
 
-	b.Balance, b.HasBalance = balance.GetByName(balanceTarget)
+	b.Balance, b.HasBalance = balances.GetByName(balanceTarget)
 
 	return b
 }
@@ -95,13 +97,18 @@ func (b *Proxy) Send(ctx *common.Context,variables *interpreter.Variables)( *Bac
 		TargetUrl:          path,
 		FinalTargetServer:  finalTargetServer,
 		RetryTargetServers: retryTargetServers,
-		Header:r.Header,
 		//Cookies:r.Cookies(),
 	}
 	if err!=nil{
 		backendResponse.StatusCode,backendResponse.Status = 503,"503"
+		if e,ok:=err.(net.Error);ok&&e.Timeout(){
+			// 转发超时
+			backendResponse.StatusCode,backendResponse.Status = 504,"504"
+		}
 		return backendResponse,err
 	}
+	backendResponse.StatusCode,backendResponse.Status = r.StatusCode,strconv.Itoa(r.StatusCode)
+	backendResponse.Header = r.Header
 	defer r.Body.Close()
 	backendResponse.BodyOrg, err = ioutil.ReadAll(r.Body)
 	if err!= nil{
@@ -0,0 +1,151 @@
+package application
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/utils"
+)
+
+type canaryTarget struct {
+	app    Application
+	weight int
+}
+
+type canaryRule struct {
+	match func(ctx *common.Context) bool
+	app   Application
+}
+
+//CanaryApplication 灰度应用，按规则或权重在多个负载之间分配流量
+type CanaryApplication struct {
+	targets []*canaryTarget
+	total   int
+	rules   []*canaryRule
+	sticky  func(ctx *common.Context) string
+
+	locker sync.Mutex
+	random *rand.Rand
+}
+
+//NewCanaryApplication 创建灰度应用，gen 用于按负载名称生成对应的应用
+func NewCanaryApplication(cfg *config.CanaryConfig, gen func(balance string) Application) *CanaryApplication {
+	app := &CanaryApplication{
+		targets: make([]*canaryTarget, 0, len(cfg.Targets)),
+		rules:   make([]*canaryRule, 0, len(cfg.Rules)),
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, t := range cfg.Targets {
+		if t.Weight <= 0 {
+			continue
+		}
+		app.targets = append(app.targets, &canaryTarget{
+			app:    gen(t.Balance),
+			weight: t.Weight,
+		})
+		app.total += t.Weight
+	}
+	for _, r := range cfg.Rules {
+		match := genCanaryMatch(r)
+		if match == nil {
+			continue
+		}
+		app.rules = append(app.rules, &canaryRule{
+			match: match,
+			app:   gen(r.Balance),
+		})
+	}
+	if cfg.Sticky != nil {
+		app.sticky = genCanaryKey(cfg.Sticky.Type, cfg.Sticky.Name)
+	}
+	return app
+}
+
+//Execute 执行
+func (app *CanaryApplication) Execute(ctx *common.Context) {
+	app.pick(ctx).Execute(ctx)
+}
+
+func (app *CanaryApplication) pick(ctx *common.Context) Application {
+	for _, r := range app.rules {
+		if r.match(ctx) {
+			return r.app
+		}
+	}
+
+	if len(app.targets) == 1 {
+		return app.targets[0].app
+	}
+
+	n := -1
+	if app.sticky != nil {
+		if key := app.sticky(ctx); key != "" {
+			h := fnv.New32a()
+			h.Write([]byte(key))
+			n = int(h.Sum32() % uint32(app.total))
+		}
+	}
+	if n < 0 {
+		app.locker.Lock()
+		n = app.random.Intn(app.total)
+		app.locker.Unlock()
+	}
+
+	for _, t := range app.targets {
+		if n < t.weight {
+			return t.app
+		}
+		n -= t.weight
+	}
+	return app.targets[len(app.targets)-1].app
+}
+
+func genCanaryMatch(rule *config.CanaryRule) func(ctx *common.Context) bool {
+	read := genCanaryKey(rule.Type, rule.Name)
+	if read == nil {
+		return nil
+	}
+	if rule.Value == "" {
+		return func(ctx *common.Context) bool {
+			return read(ctx) != ""
+		}
+	}
+	value := rule.Value
+	return func(ctx *common.Context) bool {
+		return read(ctx) == value
+	}
+}
+
+func genCanaryKey(matchType, name string) func(ctx *common.Context) string {
+	switch strings.ToLower(matchType) {
+	case config.CanaryMatchHeader:
+		return func(ctx *common.Context) string {
+			return ctx.RequestOrg.GetHeader(name)
+		}
+	case config.CanaryMatchCookie:
+		return func(ctx *common.Context) string {
+			c, err := ctx.RequestOrg.Cookie(name)
+			if err != nil {
+				return ""
+			}
+			return c.Value
+		}
+	case config.CanaryMatchQuery:
+		return func(ctx *common.Context) string {
+			return ctx.RequestOrg.URL().Query().Get(name)
+		}
+	case config.CanaryMatchIP:
+		return func(ctx *common.Context) string {
+			if ip := ctx.ClientIP(); ip != "" {
+				return ip
+			}
+			return utils.Intercept(ctx.RequestOrg.RemoteAddr(), ":")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package application
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+type namedApplication string
+
+func (app namedApplication) Execute(ctx *common.Context) {
+	ctx.LogFields["app"] = string(app)
+}
+
+func newCanaryContext(header map[string]string) *common.Context {
+	req := httptest.NewRequest("GET", "/canary?version=beta", nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	return common.NewContext(req, "test", httptest.NewRecorder())
+}
+
+func TestCanaryApplication(t *testing.T) {
+	cfg := &config.CanaryConfig{
+		Targets: []*config.CanaryTarget{
+			{Balance: "stable", Weight: 90},
+			{Balance: "canary", Weight: 10},
+		},
+		Rules: []*config.CanaryRule{
+			{Type: "header", Name: "X-Canary", Value: "true", Balance: "canary"},
+			{Type: "query", Name: "version", Value: "gamma", Balance: "gamma"},
+		},
+		Sticky: &config.CanarySticky{Type: "header", Name: "X-User"},
+	}
+	if err := cfg.Check(); err != nil {
+		t.Fatal(err)
+	}
+	app := NewCanaryApplication(cfg, func(balance string) Application {
+		return namedApplication(balance)
+	})
+
+	if got := app.pick(newCanaryContext(map[string]string{"X-Canary": "true"})); got != namedApplication("canary") {
+		t.Errorf("header rule: got %v", got)
+	}
+
+	first := app.pick(newCanaryContext(map[string]string{"X-User": "u-1"}))
+	for i := 0; i < 20; i++ {
+		if got := app.pick(newCanaryContext(map[string]string{"X-User": "u-1"})); got != first {
+			t.Fatalf("sticky: got %v, want %v", got, first)
+		}
+	}
+
+	counts := make(map[Application]int)
+	for i := 0; i < 1000; i++ {
+		counts[app.pick(newCanaryContext(nil))]++
+	}
+	if counts[namedApplication("stable")] < counts[namedApplication("canary")] {
+		t.Errorf("weight: %v", counts)
+	}
+}
+
+func TestCanaryConfigCheck(t *testing.T) {
+	cases := []*config.CanaryConfig{
+		{},
+		{Targets: []*config.CanaryTarget{{Balance: "a", Weight: 0}}},
+		{Targets: []*config.CanaryTarget{{Balance: "a", Weight: 1}}, Rules: []*config.CanaryRule{{Type: "body", Name: "a", Balance: "a"}}},
+		{Targets: []*config.CanaryTarget{{Balance: "a", Weight: 1}}, Sticky: &config.CanarySticky{Type: "cookie"}},
+	}
+	for i, c := range cases {
+		if c.Check() == nil {
+			t.Errorf("case %d: expect error", i)
+		}
+	}
+}
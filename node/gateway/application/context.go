@@ -0,0 +1,18 @@
+package application
+
+import (
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+//ContextVariables 获取可在interpreter中通过 {{context.xxx}} 引用的上下文变量
+func ContextVariables(ctx *common.Context) map[string]string {
+	return map[string]string{
+		"strategyID":   ctx.StrategyId(),
+		"strategyName": ctx.StrategyName(),
+		"apiID":        strconv.Itoa(ctx.ApiID()),
+		"clientIP":     ctx.ClientIP(),
+		"requestID":    ctx.RequestId(),
+	}
+}
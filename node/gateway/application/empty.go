@@ -7,7 +7,8 @@ type EmptyApplication struct {
 }
 
 func (app *EmptyApplication) Execute(ctx *common.Context) {
-	panic("implement me")
+	ctx.SetBody([]byte(app.response))
+	ctx.SetStatus(200, "200")
 }
 
 func NewEmptyApplication(response string) *EmptyApplication {
@@ -1,11 +1,15 @@
 package application
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 
 	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-service/balance"
 )
 
 var (
@@ -15,14 +19,60 @@ var (
 type Factory struct {
 	apiContents map[int]*config.APIContent
 
-	cache map[string]Application
+	cache    map[string]Application
+	balances *balance.Resolver
+
+	salt     string
+	digests  map[int]string
+	reusable map[string]Application
 }
 
-func NewFactory(apis map[int]*config.APIContent) *Factory {
+//NewFactory 创建应用工厂，balances为nil时使用当前生效的负载配置
+func NewFactory(apis map[int]*config.APIContent, balances *balance.Resolver) *Factory {
 	return &Factory{
 		apiContents: apis,
+		balances:    balances,
 		cache:       make(map[string]Application),
+		digests:     make(map[int]string),
+	}
+}
+
+//Inherit 复用上一次配置中接口内容及负载配置未变化的应用，salt为负载及服务发现配置的摘要
+func (f *Factory) Inherit(prev *Factory, salt string) {
+	f.salt = salt
+	if prev != nil {
+		f.reusable = prev.cache
+	}
+}
+
+//Done 应用创建完毕，不再引用上一次配置的应用
+func (f *Factory) Done() {
+	f.reusable = nil
+}
+
+//digest 应用缓存键包含接口内容及负载配置的摘要，配置变化时重新创建应用
+func (f *Factory) digest(apiContent *config.APIContent) string {
+	if d, has := f.digests[apiContent.ID]; has {
+		return d
 	}
+	data, _ := json.Marshal(apiContent)
+	sum := sha1.Sum(append(data, f.salt...))
+	d := hex.EncodeToString(sum[:])
+	f.digests[apiContent.ID] = d
+	return d
+}
+
+func (f *Factory) load(key string, apiContent *config.APIContent, create func() Application) Application {
+	key = key + ":" + f.digest(apiContent)
+	if app, has := f.cache[key]; has {
+		return app
+	}
+	app, has := f.reusable[key]
+	if !has {
+		app = create()
+	}
+	f.cache[key] = app
+	return app
 }
 
 func (f *Factory) GenApplication(cfg *config.APIOfStrategy) (Application, error) {
@@ -31,49 +81,56 @@ func (f *Factory) GenApplication(cfg *config.APIOfStrategy) (Application, error)
 	if !has {
 		return nil, ErrorInvalidAPI
 	}
+	if apiContent.Mock != nil && apiContent.Mock.Enable {
+		if err := apiContent.Mock.Check(); err == nil {
+			key := fmt.Sprintf("Mock:%d", cfg.ID)
+			return f.load(key, apiContent, func() Application {
+				return NewMockApplication(apiContent.Mock)
+			}), nil
+		}
+	}
 	switch len(apiContent.Steps) {
 	case 0:
 		{
 			key := fmt.Sprintf("Empty:%d", cfg.ID)
-			app, has := f.cache[key]
-			if !has {
-				app = NewEmptyApplication(apiContent.StaticResponse)
-				f.cache[key] = app
-			}
-
-			return app, nil
+			return f.load(key, apiContent, func() Application {
+				return NewEmptyApplication(apiContent.StaticResponse)
+			}), nil
 		}
 	case 1:
 		{
 			if apiContent.OutPutEncoder == "" || apiContent.OutPutEncoder == "origin" {
+				if cfg.Canary != nil && cfg.Canary.Check() == nil {
+					return NewCanaryApplication(cfg.Canary, func(balance string) Application {
+						return f.genDefaultApplication(apiContent, balance)
+					}), nil
+				}
 				step := apiContent.Steps[0]
 				balance := step.Balance
 				if cfg.Balance != "" {
 					balance = cfg.Balance
 				}
-				balanceK, _ := url.QueryUnescape(balance)
-				key := fmt.Sprintf("StaticApp:%d:%s", cfg.ID, balanceK)
-				app, has := f.cache[key]
-				if !has {
-					app = NewDefaultApplication(apiContent, balance)
-					f.cache[key] = app
-				}
 
-				return app, nil
+				return f.genDefaultApplication(apiContent, balance), nil
 			}
 		}
 		fallthrough
 	default:
 		{
 			key := fmt.Sprintf("LayerApp:%d", cfg.ID)
-			app, has := f.cache[key]
-			if !has {
-				app = NewLayerApplication(apiContent)
-				f.cache[key] = app
-			}
-			return app, nil
+			return f.load(key, apiContent, func() Application {
+				return NewLayerApplication(apiContent, f.balances)
+			}), nil
 		}
 	}
 
 	return nil, nil
 }
+
+func (f *Factory) genDefaultApplication(apiContent *config.APIContent, balance string) Application {
+	balanceK, _ := url.QueryUnescape(balance)
+	key := fmt.Sprintf("StaticApp:%d:%s", apiContent.ID, balanceK)
+	return f.load(key, apiContent, func() Application {
+		return NewDefaultApplication(apiContent, balance, f.balances)
+	})
+}
@@ -0,0 +1,32 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestFactoryInherit(t *testing.T) {
+	gen := func(prev *Factory, response string, salt string) (*Factory, Application) {
+		f := NewFactory(map[int]*config.APIContent{1: {ID: 1, StaticResponse: response}}, nil)
+		f.Inherit(prev, salt)
+		app, err := f.GenApplication(&config.APIOfStrategy{ID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Done()
+		return f, app
+	}
+	f1, app1 := gen(nil, "ok", "b1")
+	f2, app2 := gen(f1, "ok", "b1")
+	if app1 != app2 {
+		t.Error("expect unchanged application to be reused")
+	}
+	f3, app3 := gen(f2, "changed", "b1")
+	if app3 == app2 {
+		t.Error("expect changed api to create a new application")
+	}
+	if _, app4 := gen(f3, "changed", "b2"); app4 == app3 {
+		t.Error("expect changed balances to create a new application")
+	}
+}
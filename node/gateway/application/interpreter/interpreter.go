@@ -14,6 +14,7 @@ type Variables struct {
 	Cookies []_Cookies
 	Restful map[string]string
 	Query url.Values
+	Context map[string]string // 上下文变量，如 strategyID、apiID、clientIP
 
 }
 
@@ -13,6 +13,7 @@ const (
 	Restful="restful"
 	Query = "query"
 	Cookie="cookie"
+	Context = "context"
 )
 
 var(
@@ -29,6 +30,7 @@ func init() {
 	readers[Restful[:4]]=[]byte(Restful)
 	readers[Cookie[:4]]=[]byte(Cookie)
 	readers[Query[:4]]=[]byte(Query)
+	readers[Context[:4]]=[]byte(Context)
 
 	creators[Body] = ReaderCreateFunc(func(head []byte,body[]byte)(Reader,error) {
 
@@ -80,6 +82,14 @@ func init() {
 			Key: string(body),
 		},nil
 	})
+	creators[Context] = ReaderCreateFunc(func(head []byte,body[]byte)(Reader,error) {
+		if !bytes.Equal(head,[]byte(Context)){
+			return nil,GrammarError(string(head))
+		}
+		return &_ContextReader{
+			Key: string(body),
+		},nil
+	})
 	creators[Restful] = ReaderCreateFunc(genResfult)
 	creators[Cookie] = ReaderCreateFunc(func(head []byte,body[]byte)(Reader,error) {
 		index := 0
@@ -118,6 +128,9 @@ func genReader(line []byte)(Reader ,error) {
 	}
 
 	key:= line[:kindex]
+	if len(key)<4{
+		return nil,GrammarError(string(line))
+	}
 	keyPre:=strings.ToLower(string(key[:4]))
 
 	cmd,has:=readers[keyPre]
@@ -104,6 +104,14 @@ func (r *_QueryReader) Read(variables *Variables) string {
 	return variables.Query.Get(r.Key)
 }
 
+type _ContextReader struct {
+	Key string
+}
+
+func (r *_ContextReader) Read(variables *Variables) string {
+	return variables.Context[r.Key]
+}
+
 type _CookieReader struct {
 	Index int
 	Name string
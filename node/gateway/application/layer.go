@@ -7,6 +7,7 @@ import (
 	"github.com/eolinker/goku-api-gateway/config"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/goku-service/balance"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/backend"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
@@ -114,7 +115,7 @@ func (app *LayerApplication) do(ctxDeadline context.Context, variables *interpre
 	resC <- 1
 
 }
-func NewLayerApplication(apiContent *config.APIContent) *LayerApplication {
+func NewLayerApplication(apiContent *config.APIContent, balances *balance.Resolver) *LayerApplication {
 	app := &LayerApplication{
 		output:    response.GetEncoder(apiContent.OutPutEncoder),
 		backsides: make([]*backend.Layer, 0, len(apiContent.Steps)),
@@ -123,7 +124,7 @@ func NewLayerApplication(apiContent *config.APIContent) *LayerApplication {
 	}
 
 	for _, step := range apiContent.Steps {
-		app.backsides = append(app.backsides, backend.NewLayer(step))
+		app.backsides = append(app.backsides, backend.NewLayer(step, balances))
 	}
 
 	if apiContent.StaticResponse != "" {
@@ -0,0 +1,153 @@
+package application
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
+)
+
+type mockResponse struct {
+	statusCode int
+	headers    map[string]interpreter.Interpreter
+	body       interpreter.Interpreter
+	delay      time.Duration
+}
+
+type mockExample struct {
+	name     string
+	match    []func(ctx *common.Context) bool
+	response *mockResponse
+}
+
+//MockApplication mock应用，按示例匹配返回模拟响应
+type MockApplication struct {
+	examples []*mockExample
+	response *mockResponse
+}
+
+//NewMockApplication 创建mock应用
+func NewMockApplication(cfg *config.MockConfig) *MockApplication {
+	app := &MockApplication{
+		examples: make([]*mockExample, 0, len(cfg.Examples)),
+		response: newMockResponse(&cfg.Response),
+	}
+	for _, e := range cfg.Examples {
+		example := &mockExample{
+			name:     e.Name,
+			match:    make([]func(ctx *common.Context) bool, 0, len(e.Match)),
+			response: newMockResponse(&e.Response),
+		}
+		for _, m := range e.Match {
+			example.match = append(example.match, genMockMatch(m))
+		}
+		app.examples = append(app.examples, example)
+	}
+	return app
+}
+
+func newMockResponse(cfg *config.MockResponse) *mockResponse {
+	r := &mockResponse{
+		statusCode: cfg.StatusCode,
+		headers:    make(map[string]interpreter.Interpreter),
+		body:       genMockTemplate(cfg.Body),
+		delay:      time.Duration(cfg.Delay) * time.Millisecond,
+	}
+	if r.statusCode == 0 {
+		r.statusCode = 200
+	}
+	for k, v := range cfg.Headers {
+		r.headers[k] = genMockTemplate(v)
+	}
+	return r
+}
+
+func genMockTemplate(tpl string) interpreter.Interpreter {
+	i, err := interpreter.Parse(tpl)
+	if err != nil {
+		log.Warn("illegal mock template [", tpl, "]:", err)
+		return mockText(tpl)
+	}
+	return i
+}
+
+type mockText string
+
+func (t mockText) Execution(variables *interpreter.Variables) string {
+	return string(t)
+}
+
+func genMockMatch(m *config.MockMatch) func(ctx *common.Context) bool {
+	name, value := m.Name, m.Value
+	read := func(ctx *common.Context) (string, bool) {
+		vs, has := ctx.RequestOrg.URL().Query()[name]
+		if !has || len(vs) == 0 {
+			return "", false
+		}
+		return vs[0], true
+	}
+	if strings.ToLower(m.Type) == config.MockMatchHeader {
+		name = http.CanonicalHeaderKey(name)
+		read = func(ctx *common.Context) (string, bool) {
+			vs, has := ctx.RequestOrg.Headers()[name]
+			if !has || len(vs) == 0 {
+				return "", false
+			}
+			return vs[0], true
+		}
+	}
+	return func(ctx *common.Context) bool {
+		v, has := read(ctx)
+		if !has {
+			return false
+		}
+		return value == "" || v == value
+	}
+}
+
+func (app *MockApplication) pick(ctx *common.Context) (string, *mockResponse) {
+	for _, e := range app.examples {
+		matched := true
+		for _, match := range e.match {
+			if !match(ctx) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return e.name, e.response
+		}
+	}
+	return "", app.response
+}
+
+//Execute 执行
+func (app *MockApplication) Execute(ctx *common.Context) {
+	name, r := app.pick(ctx)
+	log.Debug(ctx.RequestId(), " mock example:", name)
+
+	orgBody, _ := ctx.ProxyRequest.RawBody()
+	bodyObj, _ := ctx.ProxyRequest.BodyInterface()
+	variables := interpreter.NewVariables(orgBody, bodyObj, ctx.ProxyRequest.Headers(), ctx.ProxyRequest.Cookies(), ctx.RestfulParam, ctx.ProxyRequest.Querys(), 1)
+	variables.Context = ContextVariables(ctx)
+
+	if r.delay > 0 {
+		time.Sleep(r.delay)
+	}
+
+	header := make(http.Header)
+	for k, v := range r.headers {
+		header.Set(k, v.Execution(variables))
+	}
+	body := []byte(r.body.Execution(variables))
+
+	ctx.LogFields[access_field.Proxy] = "\"MOCK\""
+	ctx.LogFields[access_field.ProxyStatusCode] = r.statusCode
+	ctx.SetProxyResponseHandler(common.NewResponseReader(header, r.statusCode, strconv.Itoa(r.statusCode), body))
+}
@@ -0,0 +1,62 @@
+package application
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+func TestMockApplication(t *testing.T) {
+	cfg := &config.MockConfig{
+		Enable: true,
+		Response: config.MockResponse{
+			Body:    `{"user":"{{query.user}}","api":"{{context.apiID}}"}`,
+			Headers: map[string]string{"Content-Type": "application/json"},
+		},
+		Examples: []*config.MockExample{
+			{
+				Name:     "not found",
+				Match:    []*config.MockMatch{{Type: "query", Name: "user", Value: "none"}},
+				Response: config.MockResponse{StatusCode: 404, Body: "not found"},
+			},
+			{
+				Name:     "header",
+				Match:    []*config.MockMatch{{Type: "header", Name: "x-mock"}},
+				Response: config.MockResponse{StatusCode: 201, Body: "{{header.X-Mock}}"},
+			},
+		},
+	}
+	if err := cfg.Check(); err != nil {
+		t.Fatal(err)
+	}
+	app := NewMockApplication(cfg)
+
+	cases := []struct {
+		url    string
+		header map[string]string
+		status int
+		body   string
+	}{
+		{"/mock?user=u1", nil, 200, `{"user":"u1","api":"7"}`},
+		{"/mock?user=none", nil, 404, "not found"},
+		{"/mock", map[string]string{"X-Mock": "m"}, 201, "m"},
+	}
+	for i, c := range cases {
+		req := httptest.NewRequest("GET", c.url, nil)
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		ctx := common.NewContext(req, "test", httptest.NewRecorder())
+		ctx.SetAPIID(7)
+		app.Execute(ctx)
+		if ctx.StatusCode() != c.status || string(ctx.GetBody()) != c.body {
+			t.Errorf("case %d: got %d %s", i, ctx.StatusCode(), ctx.GetBody())
+		}
+	}
+
+	if (&config.MockConfig{Response: config.MockResponse{Delay: -1}}).Check() == nil {
+		t.Error("expect delay error")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/eolinker/goku-api-gateway/config"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/goku-service/balance"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/backend"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
 
@@ -21,7 +22,7 @@ type DefaultApplication struct {
 	balanceTarget string
 }
 
-func NewDefaultApplication(apiContent *config.APIContent, target string) *DefaultApplication {
+func NewDefaultApplication(apiContent *config.APIContent, target string, balances *balance.Resolver) *DefaultApplication {
 
 	app := &DefaultApplication{
 		backend:       nil,
@@ -31,7 +32,7 @@ func NewDefaultApplication(apiContent *config.APIContent, target string) *Defaul
 	}
 	if len(apiContent.Steps) == 1 {
 		step := apiContent.Steps[0]
-		app.backend = backend.NewProxyBackendTarget(step, apiContent.RequestURL, target)
+		app.backend = backend.NewProxyBackendTarget(step, apiContent.RequestURL, target, balances)
 	}
 	if apiContent.StaticResponse != "" {
 		staticResponseStrategy := config.Parse(apiContent.StaticResponseStrategy)
@@ -67,6 +68,9 @@ func (app *DefaultApplication) Execute(ctx *common.Context) {
 		if err != nil {
 
 			log.Warn(err)
+			if r != nil {
+				ctx.LogFields[access_field.ProxyStatusCode] = r.StatusCode
+			}
 			return
 		}
 
@@ -46,12 +46,10 @@ func (r *Before) BeforeMatch(ctx *common.Context) bool {
 	log.Debug(requestID, " before plugin : begin")
 	for _, handler := range r.pluginBefor {
 
-		flag, _ := handler.Execute(ctx)
+		flag, err := handler.Execute(ctx)
 
-		if flag == false {
-			if handler.IsStop() == true {
-				return false
-			}
+		if plugin_executor.ShouldStop(handler, flag, err) {
+			return false
 		}
 	}
 	log.Debug(requestID, " before plugin : end")
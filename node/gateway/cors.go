@@ -0,0 +1,186 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/router"
+)
+
+const corsPreflightKey = "cors_preflight"
+
+var defaultCORSMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+
+type corsWildcard struct {
+	scheme string // 为空时不限制协议
+	suffix string
+}
+
+//corsPolicy 跨域策略
+type corsPolicy struct {
+	anyOrigin bool
+	origins   map[string]bool
+	wildcards []corsWildcard
+
+	methods       map[string]bool
+	allowMethods  string
+	allowHeaders  string
+	exposeHeaders string
+	credentials   bool
+	maxAge        string
+}
+
+func newCORSPolicy(cfg *config.CORSConfig) *corsPolicy {
+	if cfg == nil {
+		return nil
+	}
+	if err := cfg.Check(); err != nil {
+		log.Warn("illegal cors config:", err)
+		return nil
+	}
+
+	p := &corsPolicy{
+		origins:       make(map[string]bool),
+		methods:       make(map[string]bool),
+		allowHeaders:  strings.Join(cfg.AllowHeaders, ","),
+		exposeHeaders: strings.Join(cfg.ExposeHeaders, ","),
+		credentials:   cfg.AllowCredentials,
+	}
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(cfg.MaxAge)
+	}
+
+	for _, origin := range cfg.AllowOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "":
+			continue
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.Contains(origin, "*."):
+			index := strings.Index(origin, "*.")
+			p.wildcards = append(p.wildcards, corsWildcard{
+				scheme: origin[:index],
+				suffix: origin[index+1:],
+			})
+		default:
+			p.origins[strings.TrimSuffix(origin, "/")] = true
+		}
+	}
+
+	methods := cfg.AllowMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	allowMethods := make([]string, 0, len(methods))
+	for _, m := range methods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		if m == "" || p.methods[m] {
+			continue
+		}
+		p.methods[m] = true
+		allowMethods = append(allowMethods, m)
+	}
+	p.allowMethods = strings.Join(allowMethods, ",")
+	return p
+}
+
+func (p *corsPolicy) allowOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	if p.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if p.origins[origin] {
+		return true
+	}
+	for _, w := range p.wildcards {
+		if w.scheme != "" && !strings.HasPrefix(origin, w.scheme) {
+			continue
+		}
+		if strings.HasSuffix(origin, w.suffix) && len(origin) > len(w.scheme)+len(w.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *corsPolicy) setOrigin(ctx *common.Context, origin string) {
+	if p.anyOrigin && !p.credentials {
+		ctx.Set().SetHeader("Access-Control-Allow-Origin", "*")
+	} else {
+		ctx.Set().SetHeader("Access-Control-Allow-Origin", origin)
+		ctx.Append().AddHeader("Vary", "Origin")
+	}
+	if p.credentials {
+		ctx.Set().SetHeader("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+//Preflight 应答预检请求
+func (p *corsPolicy) Preflight(ctx *common.Context) {
+	ctx.SetCache(corsPreflightKey, true)
+
+	origin := ctx.RequestOrg.GetHeader("Origin")
+	method := strings.ToUpper(ctx.RequestOrg.GetHeader("Access-Control-Request-Method"))
+	if !p.allowOrigin(origin) || !p.methods[method] {
+		log.Info(ctx.RequestId(), " cors preflight refuse, origin:", origin, " method:", method)
+		ctx.SetStatus(403, "403")
+		ctx.SetBody([]byte("[ERROR]CORS request is not allowed!"))
+		return
+	}
+
+	p.setOrigin(ctx, origin)
+	ctx.Set().SetHeader("Access-Control-Allow-Methods", p.allowMethods)
+	allowHeaders := p.allowHeaders
+	if allowHeaders == "" {
+		allowHeaders = ctx.RequestOrg.GetHeader("Access-Control-Request-Headers")
+	}
+	if allowHeaders != "" {
+		ctx.Set().SetHeader("Access-Control-Allow-Headers", allowHeaders)
+	}
+	if p.maxAge != "" {
+		ctx.Set().SetHeader("Access-Control-Max-Age", p.maxAge)
+	}
+	ctx.SetStatus(204, "204")
+	ctx.SetBody(nil)
+}
+
+//Apply 为跨域请求的响应加上跨域头部
+func (p *corsPolicy) Apply(ctx *common.Context) {
+	origin := ctx.RequestOrg.GetHeader("Origin")
+	if !p.allowOrigin(origin) {
+		return
+	}
+	p.setOrigin(ctx, origin)
+	if p.exposeHeaders != "" {
+		ctx.Set().SetHeader("Access-Control-Expose-Headers", p.exposeHeaders)
+	}
+}
+
+//Router 预检路由
+func (p *corsPolicy) Router(ctx *common.Context) {
+	p.Preflight(ctx)
+}
+
+func isPreflight(ctx *common.Context) bool {
+	return ctx.RequestOrg.Method() == http.MethodOptions &&
+		ctx.RequestOrg.GetHeader("Origin") != "" &&
+		ctx.RequestOrg.GetHeader("Access-Control-Request-Method") != ""
+}
+
+//addPreflightRouter 注册预检路由，路径冲突时忽略
+func addPreflightRouter(r router.APIRouter, path string, policy *corsPolicy) {
+	defer func() {
+		if e := recover(); e != nil {
+			log.Warn("add cors preflight router [", path, "] error:", e)
+		}
+	}()
+	r.AddRouter(http.MethodOptions, path, policy)
+}
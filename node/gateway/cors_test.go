@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+func TestCORSAllowOrigin(t *testing.T) {
+	p := newCORSPolicy(&config.CORSConfig{
+		AllowOrigins: []string{"https://app.example.com", "https://*.example.org", "*.example.net"},
+	})
+	cases := map[string]bool{
+		"https://app.example.com":  true,
+		"https://APP.example.com":  true,
+		"http://app.example.com":   false,
+		"https://a.example.org":    true,
+		"https://a.b.example.org":  true,
+		"https://example.org":      false,
+		"http://a.example.org":     false,
+		"http://a.example.net":     true,
+		"https://evil-example.org": false,
+		"":                         false,
+	}
+	for origin, want := range cases {
+		if got := p.allowOrigin(origin); got != want {
+			t.Errorf("origin %q: got %v, want %v", origin, got, want)
+		}
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	p := newCORSPolicy(&config.CORSConfig{
+		AllowOrigins:     []string{"*"},
+		AllowMethods:     []string{"get", "post"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/api", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "X-Token")
+	w := httptest.NewRecorder()
+	ctx := common.NewContext(req, "test", w)
+	if !isPreflight(ctx) {
+		t.Fatal("expect preflight request")
+	}
+	p.Preflight(ctx)
+	ctx.Finish()
+
+	if w.Code != 204 {
+		t.Fatalf("status: %d", w.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "GET,POST",
+		"Access-Control-Allow-Headers":     "X-Token",
+		"Access-Control-Max-Age":           "600",
+		"Vary":                             "Origin",
+	}
+	for k, v := range want {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("%s: got %q, want %q", k, got, v)
+		}
+	}
+
+	req.Header.Set("Access-Control-Request-Method", "DELETE")
+	w = httptest.NewRecorder()
+	ctx = common.NewContext(req, "test", w)
+	p.Preflight(ctx)
+	ctx.Finish()
+	if w.Code != 403 {
+		t.Fatalf("status: %d", w.Code)
+	}
+}
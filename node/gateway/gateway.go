@@ -0,0 +1,196 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-service/balance"
+	"github.com/eolinker/goku-api-gateway/goku-service/discovery"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application"
+	plugin_loader "github.com/eolinker/goku-api-gateway/node/plugin-loader"
+	"github.com/eolinker/goku-api-gateway/node/router"
+)
+
+const (
+	drainInterval = 50 * time.Millisecond
+	drainTimeout  = time.Minute
+)
+
+//Gateway 网关入口，更新配置时复用未变化的插件对象及应用，构建完成后原子切换，旧配置上的请求处理完毕后释放不再使用的插件对象
+type Gateway struct {
+	routerFactory router.Factory
+
+	locker  sync.Mutex
+	current atomic.Value
+}
+
+//generation 一次配置构建出的处理器及其创建的插件对象、应用
+type generation struct {
+	// active 处理中的请求数，首字段保证64位对齐
+	active int64
+
+	handler *HTTPHandler
+	plugins map[string]*pluginInstance
+	apps    *application.Factory
+
+	prevDrained chan struct{}
+	drained     chan struct{}
+}
+
+//NewGateway 创建网关入口
+func NewGateway(factory router.Factory) *Gateway {
+	return &Gateway{
+		routerFactory: factory,
+	}
+}
+
+func (g *Gateway) load() *generation {
+	gen, _ := g.current.Load().(*generation)
+	return gen
+}
+
+//Apply 应用新配置：在不影响当前配置的情况下构建并校验新的处理器，成功后原子切换；失败时继续使用当前配置并返回错误。
+//同时返回本次配置中使用的插件的加载状态，配置未进入构建阶段时为空
+func (g *Gateway) Apply(cfg *config.GokuConfig) ([]*config.PluginLoadStatus, error) {
+	if err := checkConfig(cfg); err != nil {
+		return nil, err
+	}
+	g.locker.Lock()
+	defer g.locker.Unlock()
+
+	old := g.load()
+	plugins := newPluginCache(nil)
+	var prevApps *application.Factory
+	if old != nil {
+		plugins = newPluginCache(old.plugins)
+		prevApps = old.apps
+	}
+	sources, err := discovery.Prepare(cfg.DiscoverConfig)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := g.build(cfg, plugins, prevApps, balance.NewResolver(cfg.Balance, sources))
+	if err != nil {
+		sources.Discard()
+		for _, p := range plugins.created() {
+			plugin_loader.ReleasePlugin(p.name, p.obj)
+		}
+		return plugins.loadStatus(), err
+	}
+	if old != nil {
+		gen.prevDrained = old.drained
+	}
+
+	replaced := sources.Commit()
+	balance.ResetBalances(cfg.Balance)
+	g.current.Store(gen)
+	if old != nil {
+		go old.retire(plugins.dropped(), replaced)
+	} else {
+		closeSources(replaced)
+	}
+	return plugins.loadStatus(), nil
+}
+
+//build 构建处理器，插件加载或创建失败时返回错误，路由冲突等导致的panic作为错误返回
+func (g *Gateway) build(cfg *config.GokuConfig, plugins *pluginCache, prevApps *application.Factory, balances *balance.Resolver) (gen *generation, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			gen, err = nil, fmt.Errorf("build router error: %v", r)
+		}
+	}()
+	f := genFactory(cfg, g.routerFactory, plugins, prevApps, balances)
+	handler := f.handler()
+	f.appFactory.Done()
+	if err := plugins.err(); err != nil {
+		return nil, err
+	}
+	return &generation{
+		handler: handler,
+		plugins: plugins.used,
+		apps:    f.appFactory,
+		drained: make(chan struct{}),
+	}, nil
+}
+
+//checkConfig 校验策略及接口配置
+func checkConfig(cfg *config.GokuConfig) error {
+	if cfg == nil {
+		return errorConfig
+	}
+	apis := make(map[int]bool)
+	for _, api := range cfg.APIS {
+		if api == nil {
+			return errorConfig
+		}
+		if apis[api.ID] {
+			return fmt.Errorf("duplicate api id: %d", api.ID)
+		}
+		apis[api.ID] = true
+	}
+	strategies := make(map[string]bool)
+	for _, s := range cfg.Strategy {
+		if s == nil {
+			return errorConfig
+		}
+		if strategies[s.ID] {
+			return fmt.Errorf("duplicate strategy id: %s", s.ID)
+		}
+		strategies[s.ID] = true
+		for _, api := range s.APIS {
+			if api == nil {
+				return fmt.Errorf("strategy %s: %s", s.ID, errorConfig.Error())
+			}
+		}
+	}
+	return nil
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	for {
+		gen := g.load()
+		if gen == nil {
+			w.WriteHeader(404)
+			return
+		}
+		atomic.AddInt64(&gen.active, 1)
+		// 计数后再次确认未被切换，避免旧配置已判定处理完毕后仍有请求使用
+		if gen == g.load() {
+			defer atomic.AddInt64(&gen.active, -1)
+			gen.handler.ServeHTTP(w, req)
+			return
+		}
+		atomic.AddInt64(&gen.active, -1)
+	}
+}
+
+//retire 等待更早的配置及本配置上的请求处理完毕后，释放不再使用的插件对象，关闭被替换的服务发现数据源
+func (gen *generation) retire(dropped []*pluginInstance, sources []discovery.ISource) {
+	if gen.prevDrained != nil {
+		<-gen.prevDrained
+	}
+	deadline := time.Now().Add(drainTimeout)
+	for atomic.LoadInt64(&gen.active) > 0 {
+		if time.Now().After(deadline) {
+			log.Warn("wait for requests of old config timeout, active:", atomic.LoadInt64(&gen.active))
+			break
+		}
+		time.Sleep(drainInterval)
+	}
+	close(gen.drained)
+	for _, p := range dropped {
+		plugin_loader.ReleasePlugin(p.name, p.obj)
+	}
+	closeSources(sources)
+}
+
+func closeSources(sources []discovery.ISource) {
+	for _, s := range sources {
+		s.Close()
+	}
+}
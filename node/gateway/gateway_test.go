@@ -0,0 +1,203 @@
+package gateway
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-service/discovery"
+	plugin_loader "github.com/eolinker/goku-api-gateway/node/plugin-loader"
+	"github.com/eolinker/goku-api-gateway/node/router/httprouter"
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+type reloadPlugin struct {
+	closed chan string
+	config string
+}
+
+func (p *reloadPlugin) Access(ctx goku_plugin.ContextAccess) (bool, error) {
+	return true, nil
+}
+
+func (p *reloadPlugin) Proxy(ctx goku_plugin.ContextProxy) (bool, error) {
+	return true, nil
+}
+
+func (p *reloadPlugin) Close() error {
+	p.closed <- p.config
+	return nil
+}
+
+type reloadFactory struct {
+	created int
+	closed  chan string
+}
+
+func (f *reloadFactory) Create(config string, clusterName string, updateTag string, strategyID string, apiID int) (*goku_plugin.PluginObj, error) {
+	f.created++
+	p := &reloadPlugin{closed: f.closed, config: config}
+	return &goku_plugin.PluginObj{Access: p, Proxy: p}, nil
+}
+
+func TestGatewayReload(t *testing.T) {
+	factory := &reloadFactory{closed: make(chan string, 4)}
+	plugin_loader.Register("reload-test", func() goku_plugin.PluginFactory { return factory })
+	genConfig := func(pluginConfig string) *config.GokuConfig {
+		return &config.GokuConfig{
+			Cluster: "c",
+			Plugins: &config.GatewayPluginConfig{},
+			Strategy: []*config.StrategyConfig{
+				{ID: "s1", Name: "s1", Enable: true, Plugins: []*config.PluginConfig{{Name: "reload-test", Config: pluginConfig, UpdateTag: "1"}}},
+			},
+		}
+	}
+
+	g := NewGateway(httprouter.Factory())
+	for _, c := range []string{"a", "a", "b"} {
+		if _, err := g.Apply(genConfig(c)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if factory.created != 2 {
+		t.Errorf("expect unchanged plugin to be reused, created %d", factory.created)
+	}
+	select {
+	case c := <-factory.closed:
+		if c != "a" {
+			t.Errorf("expect dropped plugin a to be closed, got %s", c)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("dropped plugin was not closed")
+	}
+	select {
+	case c := <-factory.closed:
+		t.Errorf("plugin %s should be closed once", c)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestGatewayApplyFailure(t *testing.T) {
+	factory := &reloadFactory{closed: make(chan string, 4)}
+	plugin_loader.Register("apply-test", func() goku_plugin.PluginFactory { return factory })
+	genConfig := func(pluginConfig string, urls ...string) *config.GokuConfig {
+		cfg := &config.GokuConfig{
+			Cluster: "c",
+			Strategy: []*config.StrategyConfig{
+				{ID: "s1", Name: "s1", Enable: true, Plugins: []*config.PluginConfig{{Name: "apply-test", Config: pluginConfig}}},
+			},
+		}
+		for i, url := range urls {
+			cfg.APIS = append(cfg.APIS, &config.APIContent{ID: i + 1, RequestURL: url, Methods: []string{"GET"}})
+			cfg.Strategy[0].APIS = append(cfg.Strategy[0].APIS, &config.APIOfStrategy{ID: i + 1})
+		}
+		return cfg
+	}
+
+	g := NewGateway(httprouter.Factory())
+	status, err := g.Apply(genConfig("a", "/a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 1 || status[0].Name != "apply-test" || status[0].Code != plugin_loader.LoadOk {
+		t.Errorf("load status: %+v", status)
+	}
+	current := g.load()
+
+	if _, err := g.Apply(genConfig("b", "/b", "/b")); err == nil {
+		t.Error("expect conflict routes to fail")
+	}
+	badDiscovery := genConfig("a", "/a")
+	badDiscovery.DiscoverConfig = map[string]*config.DiscoverConfig{"d": {Name: "d", Driver: "unknown"}}
+	if _, err := g.Apply(badDiscovery); err == nil {
+		t.Error("expect invalid discovery driver to fail")
+	}
+	if _, err := g.Apply(nil); err == nil {
+		t.Error("expect nil config to fail")
+	}
+	missingPlugin := genConfig("a", "/a")
+	missingPlugin.Strategy[0].Plugins = append(missingPlugin.Strategy[0].Plugins, &config.PluginConfig{Name: "apply-test-missing"})
+	status, err = g.Apply(missingPlugin)
+	if err == nil || !strings.Contains(err.Error(), "apply-test-missing") {
+		t.Errorf("expect missing plugin to fail, got %v", err)
+	}
+	if len(status) != 2 || status[1].Name != "apply-test-missing" || status[1].Code != plugin_loader.LoadFileError {
+		t.Errorf("load status of missing plugin: %+v", status)
+	}
+	if g.load() != current {
+		t.Error("expect current config to be kept after failure")
+	}
+	select {
+	case c := <-factory.closed:
+		if c != "b" {
+			t.Errorf("expect plugin created by failed config to be closed, got %s", c)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("plugin created by failed config was not closed")
+	}
+}
+
+//retireSource 记录关闭的服务发现数据源
+type retireSource struct {
+	discovery.ISource
+	config string
+	closed chan string
+}
+
+func (s *retireSource) SetHealthConfig(conf *config.HealthCheckConfig) {}
+
+func (s *retireSource) SetDriverConfig(config string) error {
+	return nil
+}
+
+func (s *retireSource) CheckDriver(driverName string) bool {
+	return driverName == "retire-test"
+}
+
+func (s *retireSource) Close() {
+	s.closed <- s.config
+}
+
+type retireDriver chan string
+
+func (d retireDriver) Open(name string, config string) (discovery.ISource, error) {
+	return &retireSource{config: config, closed: d}, nil
+}
+
+func TestGatewayRetireSources(t *testing.T) {
+	closed := make(chan string, 4)
+	discovery.RegisteredDiscovery("retire-test", retireDriver(closed))
+	genConfig := func(sourceConfig string) *config.GokuConfig {
+		return &config.GokuConfig{
+			Cluster:        "c",
+			DiscoverConfig: map[string]*config.DiscoverConfig{"d": {Name: "d", Driver: "retire-test", Config: sourceConfig}},
+		}
+	}
+
+	g := NewGateway(httprouter.Factory())
+	if _, err := g.Apply(genConfig("a")); err != nil {
+		t.Fatal(err)
+	}
+	// 模拟旧配置上处理中的请求
+	old := g.load()
+	atomic.AddInt64(&old.active, 1)
+	if _, err := g.Apply(genConfig("b")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-closed:
+		t.Fatalf("source %s closed while old config is draining", c)
+	case <-time.After(3 * drainInterval):
+	}
+	atomic.AddInt64(&old.active, -1)
+	select {
+	case c := <-closed:
+		if c != "a" {
+			t.Errorf("expect replaced source a to be closed, got %s", c)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("replaced source was not closed")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	access_log "github.com/eolinker/goku-api-gateway/goku-node/access-log"
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
 	"github.com/eolinker/goku-api-gateway/node/utils"
 	fields "github.com/eolinker/goku-api-gateway/server/access-field"
 )
@@ -16,7 +17,8 @@ var systemRequestPath = []string{"/oauth2/token", "/oauth2/authorize", "/oauth2/
 
 //HTTPHandler httpHandler
 type HTTPHandler struct {
-	router *Before
+	router         *Before
+	trustedProxies ipList
 }
 
 func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -38,17 +40,19 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	remoteAddr := utils.Intercept(req.RemoteAddr, ":")
 	ctx.LogFields[fields.RemoteAddr] = remoteAddr
 
-	if realIP := ctx.GetHeader("X-Real-Ip"); realIP == "" {
-		ctx.ProxyRequest.SetHeader("X-Real-Ip", remoteAddr)
-		ctx.LogFields[fields.HTTPXForwardedFor] = remoteAddr
-	} else {
-		ctx.LogFields[fields.HTTPXForwardedFor] = realIP
-	}
+	// 只有来自可信代理的请求才使用X-Forwarded-For/X-Real-Ip
+	realIP := clientIP(req.RemoteAddr, req.Header.Get("X-Forwarded-For"), req.Header.Get("X-Real-Ip"), h.trustedProxies)
+	ctx.SetClientIP(realIP)
+	ctx.ProxyRequest.SetHeader("X-Real-Ip", realIP)
+	ctx.LogFields[fields.HTTPXForwardedFor] = realIP
 
 	h.router.Router(w, req, ctx)
 
 	n, status := ctx.Finish()
 
+	proxyStatus, _ := ctx.LogFields[fields.ProxyStatusCode].(int)
+	monitor.Record(ctx.StrategyId(), ctx.ApiID(), status, proxyStatus, time.Since(timeStart))
+
 	//proxyStatusCode := 0
 	//if ctx.ProxyResponseHandler != nil {
 	//	proxyStatusCode = ctx.ProxyResponseHandler.StatusCode()
@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"net"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/utils"
+	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
+)
+
+//ipList IP/CIDR列表
+type ipList []*net.IPNet
+
+func newIPList(vs []string) ipList {
+	nets, err := config.ParseIPNets(vs)
+	if err != nil {
+		log.Warn("illegal ip list:", err)
+		return nil
+	}
+	return nets
+}
+
+func (l ipList) contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range l {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+//ipAccessPolicy IP黑白名单
+type ipAccessPolicy struct {
+	allow ipList
+	deny  ipList
+}
+
+func newIPAccessPolicy(cfg *config.IPAccessConfig) *ipAccessPolicy {
+	if cfg == nil {
+		return nil
+	}
+	if err := cfg.Check(); err != nil {
+		log.Warn("illegal ip access config:", err)
+		return nil
+	}
+	p := &ipAccessPolicy{
+		allow: newIPList(cfg.Allow),
+		deny:  newIPList(cfg.Deny),
+	}
+	if len(p.allow) == 0 && len(p.deny) == 0 {
+		return nil
+	}
+	return p
+}
+
+func (p *ipAccessPolicy) isAllow(ip net.IP) bool {
+	if ip == nil {
+		return len(p.allow) == 0 && len(p.deny) == 0
+	}
+	if p.deny.contains(ip) {
+		return false
+	}
+	if len(p.allow) > 0 && !p.allow.contains(ip) {
+		return false
+	}
+	return true
+}
+
+//Check 校验客户端IP，拒绝时返回403并记录到access日志
+func (p *ipAccessPolicy) Check(ctx *common.Context, scope string) bool {
+	if p == nil {
+		return true
+	}
+	clientIP := ctx.ClientIP()
+	if p.isAllow(net.ParseIP(clientIP)) {
+		return true
+	}
+	log.Info(ctx.RequestId(), " ip access [", scope, "] refuse:", clientIP)
+	ctx.LogFields[access_field.IPAccess] = scope + "_deny"
+	ctx.SetStatus(403, "403")
+	ctx.SetBody([]byte("[ERROR]IP address is not allowed!"))
+	return false
+}
+
+//clientIP 计算客户端真实IP，只有直连地址为可信代理时才读取X-Forwarded-For/X-Real-Ip；
+//存在X-Forwarded-For时不再读取X-Real-Ip，无法解析的地址作为客户端地址，不会被IP黑白名单放行
+func clientIP(remoteAddr string, xForwardedFor string, xRealIP string, trusted ipList) string {
+	peer := remoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		peer = host
+	} else if strings.Count(remoteAddr, ":") == 1 {
+		peer = utils.Intercept(remoteAddr, ":")
+	}
+
+	if !trusted.contains(net.ParseIP(peer)) {
+		return peer
+	}
+
+	if xForwardedFor != "" {
+		// 从右往左跳过可信代理，第一个不可信的地址即为客户端地址
+		ips := strings.Split(xForwardedFor, ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			entry := strings.TrimSpace(ips[i])
+			ip := parseForwardedIP(entry)
+			if ip == nil {
+				return entry
+			}
+			if !trusted.contains(ip) || i == 0 {
+				return ip.String()
+			}
+		}
+	}
+	if ip := net.ParseIP(strings.TrimSpace(xRealIP)); ip != nil {
+		return ip.String()
+	}
+	return peer
+}
+
+// parseForwardedIP 解析X-Forwarded-For中的地址，兼容带端口的写法
+func parseForwardedIP(entry string) net.IP {
+	if ip := net.ParseIP(entry); ip != nil {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(entry); err == nil {
+		return net.ParseIP(host)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"net"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := newIPList([]string{"10.0.0.0/8", "fd00::/8"})
+	cases := []struct {
+		remoteAddr, xff, xRealIP, want string
+	}{
+		{"1.2.3.4:5678", "9.9.9.9", "8.8.8.8", "1.2.3.4"},
+		{"10.0.0.1:5678", "9.9.9.9, 10.0.0.2", "", "9.9.9.9"},
+		{"10.0.0.1:5678", "6.6.6.6, 9.9.9.9, 10.0.0.2", "", "9.9.9.9"},
+		{"10.0.0.1:5678", "", "8.8.8.8", "8.8.8.8"},
+		{"10.0.0.1:5678", "10.0.0.3", "", "10.0.0.3"},
+		{"[fd00::1]:5678", "2001:db8::1", "", "2001:db8::1"},
+		{"[2001:db8::2]:5678", "9.9.9.9", "", "2001:db8::2"},
+		{"10.0.0.1:5678", "9.9.9.9:1234, 10.0.0.2", "", "9.9.9.9"},
+		// 无法解析的地址不回退到客户端可伪造的X-Real-Ip
+		{"10.0.0.1:5678", "unknown, 10.0.0.2", "192.168.0.1", "unknown"},
+		{"10.0.0.1:5678", "", "", "10.0.0.1"},
+	}
+	for i, c := range cases {
+		if got := clientIP(c.remoteAddr, c.xff, c.xRealIP, trusted); got != c.want {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+	}
+}
+
+func TestIPAccessPolicy(t *testing.T) {
+	p := newIPAccessPolicy(&config.IPAccessConfig{
+		Allow: []string{"192.168.0.0/16", "2001:db8::/32"},
+		Deny:  []string{"192.168.1.1"},
+	})
+	cases := map[string]bool{
+		"192.168.2.1":   true,
+		"192.168.1.1":   false,
+		"10.0.0.1":      false,
+		"2001:db8::abc": true,
+		"2001:db9::1":   false,
+	}
+	for ip, want := range cases {
+		if got := p.isAllow(net.ParseIP(ip)); got != want {
+			t.Errorf("%s: got %v, want %v", ip, got, want)
+		}
+	}
+	if p.isAllow(net.ParseIP("unknown")) {
+		t.Error("expect unparseable client address to be refused")
+	}
+
+	if (&config.IPAccessConfig{Deny: []string{"300.1.1.1"}}).Check() == nil {
+		t.Error("expect illegal ip error")
+	}
+}
@@ -1,84 +1,222 @@
 package plugin_executor
 
 import (
-	goku_plugin "github.com/eolinker/goku-plugin"
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
 	"github.com/eolinker/goku-api-gateway/config"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
-	"time"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+	plugin_loader "github.com/eolinker/goku-api-gateway/node/plugin-loader"
+	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
+	goku_plugin "github.com/eolinker/goku-plugin"
 )
 
 type Executor interface {
-	Execute(ctx *common.Context)(isContinue bool, e error)
-	IsStop()bool
+	Execute(ctx *common.Context) (isContinue bool, e error)
+	IsStop() bool
 }
-type executorInfo struct {
-	Name string
-	isStop bool
+
+//abortError 插件执行失败且失败策略为stop、fallback时返回，调用方据此中断插件流程
+type abortError struct {
+	err error
 }
 
+func (e *abortError) Error() string {
+	return e.err.Error()
+}
 
+//ShouldStop 插件返回false且配置了中断，或插件执行失败且失败策略要求中断时，不再执行后续插件
+func ShouldStop(ex Executor, isContinue bool, err error) bool {
+	if isContinue {
+		return false
+	}
+	if ex.IsStop() {
+		return true
+	}
+	_, ok := err.(*abortError)
+	return ok
+}
 
-func (ex*executorInfo) IsStop() bool {
-	return  ex.isStop
+type executorInfo struct {
+	Name       string
+	isStop     bool
+	timeout    time.Duration
+	cancelable plugin_loader.Cancelable
+	failPolicy string
+	onError    bool // 配置了失败策略时，插件返回错误也按失败策略处理
+	failStatus int
+	failBody   string
 }
 
-func genExecutor(cfg *config.PluginConfig) executorInfo {
-	return executorInfo{
-		Name:cfg.Name,
-		isStop:cfg.IsStop,
-	}
+func (ex *executorInfo) IsStop() bool {
+	return ex.isStop
 }
-type beforeExecutor struct {
-	executorInfo
-	plugin goku_plugin.PluginBeforeMatch
 
+//genExecutor 可中断的插件（进程外插件、Wasm插件）通过context中断，本地插件在独立协程中执行并在超时后放弃等待
+func genExecutor(cfg *config.PluginConfig, p interface{}) executorInfo {
+	cancelable, _ := p.(plugin_loader.Cancelable)
+	ex := executorInfo{
+		cancelable: cancelable,
+		Name:       cfg.Name,
+		isStop:     cfg.IsStop,
+		timeout:    time.Duration(cfg.Timeout) * time.Millisecond,
+		failPolicy: cfg.FailPolicy,
+		onError:    cfg.FailPolicy != "",
+		failStatus: cfg.FailStatus,
+		failBody:   cfg.FailBody,
+	}
+	if ex.failPolicy == "" {
+		ex.failPolicy = config.PluginFailContinue
+	}
+	if ex.failStatus == 0 {
+		ex.failStatus = http.StatusInternalServerError
+		if ex.failPolicy == config.PluginFailFallback {
+			ex.failStatus = http.StatusOK
+		}
+	}
+	return ex
 }
 
-func (ex*beforeExecutor) Execute(ctx *common.Context) (isContinue bool, e error) {
-	requestId:=ctx.RequestId()
+//run 执行插件并记录耗时、错误，插件panic或超时时按失败策略处理；
+//插件返回错误时，仅在配置了失败策略时按失败策略处理，未配置时保持插件返回的结果，兼容通过返回错误中断请求的插件
+func (ex *executorInfo) run(ctx *common.Context, phase string, call func() (bool, error)) (bool, error) {
+	requestID := ctx.RequestId()
 	ctx.SetPlugin(ex.Name)
-	log.Debug(requestId, " before plugin :", ex.Name, " start")
+	log.Debug(requestID, " ", phase, " plugin :", ex.Name, " start")
 	now := time.Now()
-	isContinue, err := ex.plugin.BeforeMatch(ctx)
-	log.Debug(requestId, " before plugin :", ex.Name, " Duration:", time.Since(now))
-	log.Debug(requestId, " before plugin :", ex.Name, " end")
+	isContinue, result, err := ex.call(ctx, phase, call)
+	cost := time.Since(now)
+	log.Debug(requestID, " ", phase, " plugin :", ex.Name, " Duration:", cost)
+	log.Debug(requestID, " ", phase, " plugin :", ex.Name, " end")
+
+	monitor.RecordPlugin(ex.Name, result, cost)
+	appendLogField(ctx, access_field.PluginTime, ex.Name+":"+cost.String())
 	if err != nil {
-		log.Warn(requestId, " before plugin:", ex.Name, " error:", err)
+		log.Warn(requestID, " ", phase, " plugin:", ex.Name, " error:", err)
+		appendLogField(ctx, access_field.PluginError, ex.Name+":"+err.Error())
+	}
+	if result == monitor.PluginPanic || result == monitor.PluginTimeout || (result == monitor.PluginError && ex.onError) {
+		return ex.fail(ctx, err)
+	}
+	return isContinue, err
+}
+
+//call 未设置超时时在当前协程执行插件；可中断的插件通过context在超时后中断调用，插件返回后才继续使用请求上下文
+func (ex *executorInfo) call(ctx *common.Context, phase string, call func() (bool, error)) (bool, int, error) {
+	if ex.timeout <= 0 {
+		return safeCall(call)
+	}
+	if ex.cancelable == nil {
+		return ex.wait(call)
+	}
+	c, cancel := context.WithTimeout(context.Background(), ex.timeout)
+	defer cancel()
+	isContinue, result, err := safeCall(func() (bool, error) {
+		return ex.cancelable.CallPhase(c, phase, ctx)
+	})
+	if result != monitor.PluginPanic && c.Err() == context.DeadlineExceeded {
+		return false, monitor.PluginTimeout, fmt.Errorf("timeout after %s", ex.timeout)
+	}
+	return isContinue, result, err
+}
+
+//wait 本地插件无法中断，在独立协程中执行并等待至超时；超时后按失败策略继续处理请求，插件调用在后台执行至结束
+func (ex *executorInfo) wait(call func() (bool, error)) (bool, int, error) {
+	type reply struct {
+		isContinue bool
+		result     int
+		err        error
+	}
+	done := make(chan reply, 1)
+	go func() {
+		isContinue, result, err := safeCall(call)
+		done <- reply{isContinue: isContinue, result: result, err: err}
+	}()
+	timer := time.NewTimer(ex.timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.isContinue, r.result, r.err
+	case <-timer.C:
+		return false, monitor.PluginTimeout, fmt.Errorf("timeout after %s", ex.timeout)
 	}
-	return isContinue,err
 }
 
-func NewBeforeExecutor(cfg  *config.PluginConfig,p goku_plugin.PluginBeforeMatch ) *beforeExecutor {
+func safeCall(call func() (bool, error)) (isContinue bool, result int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("plugin panic:", r, "\n", string(debug.Stack()))
+			isContinue, result, err = false, monitor.PluginPanic, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	isContinue, err = call()
+	if err != nil {
+		return isContinue, monitor.PluginError, err
+	}
+	return isContinue, monitor.PluginOK, nil
+}
+
+//fail 按失败策略处理：continue跳过插件，stop、fallback设置响应并中断插件流程
+func (ex *executorInfo) fail(ctx *common.Context, err error) (bool, error) {
+	switch ex.failPolicy {
+	case config.PluginFailStop:
+		ctx.SetStatus(ex.failStatus, strconv.Itoa(ex.failStatus))
+		ctx.SetBody([]byte("[ERROR]Plugin " + ex.Name + " failed!"))
+	case config.PluginFailFallback:
+		ctx.SetStatus(ex.failStatus, strconv.Itoa(ex.failStatus))
+		ctx.SetBody([]byte(ex.failBody))
+	default:
+		return true, err
+	}
+	return false, &abortError{err: err}
+}
+
+func appendLogField(ctx *common.Context, key, value string) {
+	if v, ok := ctx.LogFields[key].(string); ok && v != "" {
+		value = v + "," + value
+	}
+	ctx.LogFields[key] = value
+}
+
+type beforeExecutor struct {
+	executorInfo
+	plugin goku_plugin.PluginBeforeMatch
+}
+
+func (ex *beforeExecutor) Execute(ctx *common.Context) (isContinue bool, e error) {
+	return ex.run(ctx, plugin_loader.PhaseBefore, func() (bool, error) {
+		return ex.plugin.BeforeMatch(ctx)
+	})
+}
+
+func NewBeforeExecutor(cfg *config.PluginConfig, p goku_plugin.PluginBeforeMatch) *beforeExecutor {
 	return &beforeExecutor{
-		executorInfo: genExecutor(cfg),
+		executorInfo: genExecutor(cfg, p),
 		plugin:       p,
 	}
 
 }
+
 type accessExecutor struct {
 	executorInfo
 	plugin goku_plugin.PluginAccess
 }
 
 func (ex *accessExecutor) Execute(ctx *common.Context) (isContinue bool, e error) {
-	requestId:=ctx.RequestId()
-	ctx.SetPlugin(ex.Name)
-
-	log.Debug(requestId, " access plugin :", ex.Name, " start")
-	now := time.Now()
-	isContinue, err := ex.plugin.Access(ctx)
-	log.Debug(requestId, " access plugin :", ex.Name, " Duration:", time.Since(now))
-	log.Debug(requestId, " access plugin :", ex.Name, " end")
-	if err != nil {
-		log.Warn(requestId, " access plugin:", ex.Name, " error:", err)
-	}
-	return isContinue,err
+	return ex.run(ctx, plugin_loader.PhaseAccess, func() (bool, error) {
+		return ex.plugin.Access(ctx)
+	})
 }
 
-func NewAccessExecutor(cfg  *config.PluginConfig,p goku_plugin.PluginAccess ) *accessExecutor {
+func NewAccessExecutor(cfg *config.PluginConfig, p goku_plugin.PluginAccess) *accessExecutor {
 	return &accessExecutor{
-		executorInfo: genExecutor(cfg),
+		executorInfo: genExecutor(cfg, p),
 		plugin:       p,
 	}
 }
@@ -88,24 +226,15 @@ type proxyExecutor struct {
 	plugin goku_plugin.PluginProxy
 }
 
-func (ex*proxyExecutor) Execute(ctx *common.Context) (isContinue bool, e error) {
-	requestId:=ctx.RequestId()
-	ctx.SetPlugin(ex.Name)
-
-	log.Debug(requestId, " proxy plugin :", ex.Name, " start")
-	now := time.Now()
-	isContinue, err := ex.plugin.Proxy(ctx)
-	log.Debug(requestId, " proxy plugin :", ex.Name, " Duration:", time.Since(now))
-	log.Debug(requestId, " proxy plugin :", ex.Name, " end")
-	if err != nil {
-		log.Warn(requestId, " proxy plugin:", ex.Name, " error:", err)
-	}
-	return isContinue,err
+func (ex *proxyExecutor) Execute(ctx *common.Context) (isContinue bool, e error) {
+	return ex.run(ctx, plugin_loader.PhaseProxy, func() (bool, error) {
+		return ex.plugin.Proxy(ctx)
+	})
 }
 
-func NewProxyExecutor(cfg  *config.PluginConfig,p goku_plugin.PluginProxy ) *proxyExecutor {
+func NewProxyExecutor(cfg *config.PluginConfig, p goku_plugin.PluginProxy) *proxyExecutor {
 	return &proxyExecutor{
-		executorInfo: genExecutor(cfg),
+		executorInfo: genExecutor(cfg, p),
 		plugin:       p,
 	}
-}
\ No newline at end of file
+}
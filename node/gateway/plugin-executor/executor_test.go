@@ -0,0 +1,129 @@
+package plugin_executor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+type testAccess func(ctx goku_plugin.ContextAccess) (bool, error)
+
+func (f testAccess) Access(ctx goku_plugin.ContextAccess) (bool, error) {
+	return f(ctx)
+}
+
+//testCancelable 可中断的插件，调用在context到期或执行完毕时返回
+type testCancelable time.Duration
+
+func (d testCancelable) Access(ctx goku_plugin.ContextAccess) (bool, error) {
+	return d.CallPhase(context.Background(), "access", ctx)
+}
+
+func (d testCancelable) CallPhase(c context.Context, phase string, ctx goku_plugin.Context) (bool, error) {
+	select {
+	case <-time.After(time.Duration(d)):
+		return true, nil
+	case <-c.Done():
+		return true, c.Err()
+	}
+}
+
+func newTestContext() *common.Context {
+	return common.NewContext(httptest.NewRequest(http.MethodGet, "http://localhost/a", nil), "r1", httptest.NewRecorder())
+}
+
+func TestExecutorFailPolicy(t *testing.T) {
+	panicPlugin := testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		panic("boom")
+	})
+	slowPlugin := testCancelable(time.Second)
+	cases := []struct {
+		name       string
+		cfg        *config.PluginConfig
+		plugin     goku_plugin.PluginAccess
+		isContinue bool
+		stop       bool
+		status     int
+		body       string
+	}{
+		{"panic continue", &config.PluginConfig{Name: "p"}, panicPlugin, true, false, 0, ""},
+		{"panic stop", &config.PluginConfig{Name: "p", FailPolicy: config.PluginFailStop}, panicPlugin, false, true, 500, "[ERROR]Plugin p failed!"},
+		{"timeout fallback", &config.PluginConfig{Name: "p", Timeout: 20, FailPolicy: config.PluginFailFallback, FailBody: "{}"}, slowPlugin, false, true, 200, "{}"},
+		{"timeout stop with status", &config.PluginConfig{Name: "p", Timeout: 20, FailPolicy: config.PluginFailStop, FailStatus: 503}, slowPlugin, false, true, 503, "[ERROR]Plugin p failed!"},
+	}
+	for _, c := range cases {
+		ex := NewAccessExecutor(c.cfg, c.plugin)
+		ctx := newTestContext()
+		flag, err := ex.Execute(ctx)
+		if flag != c.isContinue || err == nil || ShouldStop(ex, flag, err) != c.stop {
+			t.Errorf("%s: unexpected result %v %v", c.name, flag, err)
+			continue
+		}
+		if c.status != 0 && (ctx.StatusCode() != c.status || string(ctx.GetBody()) != c.body) {
+			t.Errorf("%s: unexpected response %d %s", c.name, ctx.StatusCode(), ctx.GetBody())
+		}
+		if v, _ := ctx.LogFields[access_field.PluginError].(string); !strings.HasPrefix(v, "p:") {
+			t.Errorf("%s: unexpected plugin error field %q", c.name, v)
+		}
+	}
+}
+
+func TestExecutorNativeTimeout(t *testing.T) {
+	// 本地插件无法中断，超时后放弃等待并按失败策略处理
+	release := make(chan struct{})
+	defer close(release)
+	ex := NewAccessExecutor(&config.PluginConfig{Name: "p", Timeout: 10, FailPolicy: config.PluginFailStop, FailStatus: 504}, testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		<-release
+		return true, nil
+	}))
+	ctx := newTestContext()
+	flag, err := ex.Execute(ctx)
+	if flag || err == nil || !ShouldStop(ex, flag, err) || ctx.StatusCode() != 504 {
+		t.Errorf("expect native plugin to time out, got %v %v %d", flag, err, ctx.StatusCode())
+	}
+
+	fast := NewAccessExecutor(&config.PluginConfig{Name: "p", Timeout: 1000}, testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		ctx.SetStatus(201, "201")
+		return true, nil
+	}))
+	ctx = newTestContext()
+	if flag, err := fast.Execute(ctx); !flag || err != nil || ctx.StatusCode() != 201 {
+		t.Errorf("expect native plugin to finish in time, got %v %v %d", flag, err, ctx.StatusCode())
+	}
+}
+
+func TestExecutorResult(t *testing.T) {
+	refuse := NewAccessExecutor(&config.PluginConfig{Name: "auth", IsStop: true, Timeout: 100}, testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		return false, errors.New("refuse")
+	}))
+	pass := NewAccessExecutor(&config.PluginConfig{Name: "pass"}, testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		return false, errors.New("ignored")
+	}))
+	ctx := newTestContext()
+	if flag, err := refuse.Execute(ctx); flag || err == nil || err.Error() != "refuse" || !ShouldStop(refuse, flag, err) {
+		t.Errorf("expect plugin result to be kept, got %v %v", flag, err)
+	}
+	if flag, err := pass.Execute(ctx); ShouldStop(pass, flag, err) {
+		t.Error("plugin without stop should not stop the flow")
+	}
+	if v, _ := ctx.LogFields[access_field.PluginTime].(string); !strings.HasPrefix(v, "auth:") || !strings.Contains(v, ",pass:") {
+		t.Errorf("unexpected plugin time field %q", v)
+	}
+
+	fallback := NewAccessExecutor(&config.PluginConfig{Name: "fallback", FailPolicy: config.PluginFailFallback, FailBody: "{}"}, testAccess(func(ctx goku_plugin.ContextAccess) (bool, error) {
+		return true, errors.New("upstream error")
+	}))
+	ctx = newTestContext()
+	if flag, err := fallback.Execute(ctx); flag || !ShouldStop(fallback, flag, err) || ctx.StatusCode() != 200 || string(ctx.GetBody()) != "{}" {
+		t.Errorf("expect fail policy to apply to returned error, got %v %v %d %s", flag, err, ctx.StatusCode(), ctx.GetBody())
+	}
+}
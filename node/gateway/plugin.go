@@ -1,22 +1,134 @@
 package gateway
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/eolinker/goku-api-gateway/config"
 	plugin_executor "github.com/eolinker/goku-api-gateway/node/gateway/plugin-executor"
 	plugin "github.com/eolinker/goku-api-gateway/node/plugin-loader"
+	goku_plugin "github.com/eolinker/goku-plugin"
 )
 
-func genBeforPlugin(cfgs []*config.PluginConfig, cluster string) []plugin_executor.Executor {
+type pluginInstance struct {
+	name string
+	obj  *goku_plugin.PluginObj
+}
+
+//pluginCache 按作用域、插件名、UpdateTag及配置摘要复用上一次配置创建的插件对象，并记录本次配置中各插件的加载状态
+type pluginCache struct {
+	prev   map[string]*pluginInstance
+	used   map[string]*pluginInstance
+	status map[string]*config.PluginLoadStatus
+	errors []string
+}
+
+func newPluginCache(prev map[string]*pluginInstance) *pluginCache {
+	return &pluginCache{
+		prev:   prev,
+		used:   make(map[string]*pluginInstance),
+		status: make(map[string]*config.PluginLoadStatus),
+	}
+}
+
+func (c *pluginCache) create(scope string, name string, conf string, updateTag string, cluster string, strategyID string, apiID int) (*goku_plugin.PluginObj, error) {
+	sum := sha1.Sum([]byte(conf))
+	key := fmt.Sprintf("%s|%s|%s|%s|%d|%s|%s", scope, name, cluster, strategyID, apiID, updateTag, hex.EncodeToString(sum[:]))
+	if p, has := c.used[key]; has {
+		return p.obj, nil
+	}
+	if p, has := c.prev[key]; has {
+		c.used[key] = p
+		c.record(name, plugin.LoadOk, nil)
+		return p.obj, nil
+	}
+	factory, code, err := plugin.LoadPluginStatus(name)
+	if err != nil {
+		c.fail(name, code, err)
+		return nil, err
+	}
+	obj, err := factory.Create(conf, cluster, updateTag, strategyID, apiID)
+	if err != nil {
+		c.fail(name, plugin.LoadCreateError, err)
+		return nil, err
+	}
+	c.used[key] = &pluginInstance{name: name, obj: obj}
+	c.record(name, plugin.LoadOk, nil)
+	return obj, nil
+}
+
+func (c *pluginCache) fail(name string, code int, err error) {
+	c.errors = append(c.errors, fmt.Sprintf("plugin %s: %s", name, err.Error()))
+	c.record(name, code, err)
+}
+
+//record 记录插件的加载状态，同一插件在多处使用时保留失败的状态
+func (c *pluginCache) record(name string, code int, err error) {
+	if s, has := c.status[name]; has && s.Code != plugin.LoadOk {
+		return
+	}
+	s := &config.PluginLoadStatus{
+		Name: name,
+		Code: code,
+	}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	c.status[name] = s
+}
+
+//loadStatus 本次配置中使用的插件的加载状态
+func (c *pluginCache) loadStatus() []*config.PluginLoadStatus {
+	status := make([]*config.PluginLoadStatus, 0, len(c.status))
+	for _, s := range c.status {
+		status = append(status, s)
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Name < status[j].Name
+	})
+	return status
+}
+
+//err 构建过程中加载或创建失败的插件，配置中的插件均需可用，否则不应用该配置
+func (c *pluginCache) err() error {
+	if len(c.errors) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(c.errors, "; "))
+}
+
+//dropped 上一次配置中创建、本次配置不再使用的插件对象
+func (c *pluginCache) dropped() []*pluginInstance {
+	ps := make([]*pluginInstance, 0)
+	for key, p := range c.prev {
+		if _, has := c.used[key]; !has {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+//created 本次配置新建的插件对象，配置构建失败时释放
+func (c *pluginCache) created() []*pluginInstance {
+	ps := make([]*pluginInstance, 0)
+	for key, p := range c.used {
+		if _, has := c.prev[key]; !has {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+func genBeforPlugin(plugins *pluginCache, cfgs []*config.PluginConfig, cluster string) []plugin_executor.Executor {
 	ps := make([]plugin_executor.Executor, 0, len(cfgs))
 	for _, cfg := range cfgs {
 
-		factory, e := plugin.LoadPlugin(cfg.Name)
-		if e != nil {
-			continue
-		}
-		obj, err := factory.Create(cfg.Config, cluster, cfg.UpdateTag, "", 0)
+		obj, err := plugins.create("before", cfg.Name, cfg.Config, cfg.UpdateTag, cluster, "", 0)
 		if err != nil {
 			continue
 		}
@@ -28,18 +140,14 @@ func genBeforPlugin(cfgs []*config.PluginConfig, cluster string) []plugin_execut
 	return ps
 }
 
-func genPlugins(cfgs []*config.PluginConfig, cluster string, strategyID string, apiID int) ([]plugin_executor.Executor, []plugin_executor.Executor, []plugin_executor.Executor) {
+func genPlugins(plugins *pluginCache, cfgs []*config.PluginConfig, cluster string, strategyID string, apiID int) ([]plugin_executor.Executor, []plugin_executor.Executor, []plugin_executor.Executor) {
 	psBefor := make([]plugin_executor.Executor, 0, len(cfgs))
 	psAccess := make([]plugin_executor.Executor, 0, len(cfgs))
 	psProxy := make([]plugin_executor.Executor, 0, len(cfgs))
 
 	for _, cfg := range cfgs {
 
-		factory, e := plugin.LoadPlugin(cfg.Name)
-		if e != nil {
-			continue
-		}
-		obj, err := factory.Create(cfg.Config, cluster, cfg.UpdateTag, strategyID, apiID)
+		obj, err := plugins.create("plugin", cfg.Name, cfg.Config, cfg.UpdateTag, cluster, strategyID, apiID)
 		if err != nil {
 			continue
 		}
@@ -1,17 +1,19 @@
 package gateway
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"reflect"
 	"strings"
 
 	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
 	"github.com/eolinker/goku-api-gateway/goku-service/balance"
-	"github.com/eolinker/goku-api-gateway/goku-service/discovery"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application"
 	plugin_executor "github.com/eolinker/goku-api-gateway/node/gateway/plugin-executor"
-	plugin_loader "github.com/eolinker/goku-api-gateway/node/plugin-loader"
 	"github.com/eolinker/goku-api-gateway/node/router"
 )
 
@@ -22,13 +24,11 @@ var (
 //Parse 解析
 func Parse(config *config.GokuConfig, factory router.Factory) (http.Handler, error) {
 
-	if config == nil {
-		return nil, errorConfig
+	g := NewGateway(factory)
+	if _, err := g.Apply(config); err != nil {
+		return nil, err
 	}
-
-	f := genFactory(config, factory)
-
-	return &HTTPHandler{router: f.create()}, nil
+	return g, nil
 }
 
 type _RootFactory struct {
@@ -41,10 +41,18 @@ type _RootFactory struct {
 	appFactory    *application.Factory
 	routerFactory router.Factory
 	cluster       string
+	plugins       *pluginCache
 
 	authPlugin map[string]string
 }
 
+func (f *_RootFactory) handler() *HTTPHandler {
+	return &HTTPHandler{
+		router:         f.create(),
+		trustedProxies: newIPList(f.orgCfg.TrustedProxies),
+	}
+}
+
 func (f *_RootFactory) create() *Before {
 	beforeRouter := &Before{
 		pluginBefor:       f.beforePlugin,
@@ -69,7 +77,7 @@ func (f *_RootFactory) createStrategy() map[string]*Strategy {
 
 // 构造策略
 func (f *_RootFactory) genStrategy(cfg *config.StrategyConfig) *Strategy {
-	_, accesses, _ := genPlugins(cfg.Plugins, f.cluster, cfg.ID, 0)
+	_, accesses, _ := genPlugins(f.plugins, cfg.Plugins, f.cluster, cfg.ID, 0)
 
 	s := &Strategy{
 		ID:     cfg.ID,
@@ -80,6 +88,8 @@ func (f *_RootFactory) genStrategy(cfg *config.StrategyConfig) *Strategy {
 		globalAccessPlugin: f.gAccesses,
 		authPlugin:         make(map[string]plugin_executor.Executor),
 		isNeedAuth:         false,
+		cors:               newCORSPolicy(cfg.CORS),
+		ipAccess:           newIPAccessPolicy(cfg.IPAccess),
 	}
 	if !s.Enable {
 		return s
@@ -90,11 +100,7 @@ func (f *_RootFactory) genStrategy(cfg *config.StrategyConfig) *Strategy {
 		s.isNeedAuth = true
 		pluginName, has := f.authPlugin[authKey]
 		if has {
-			pluginFactory, e := plugin_loader.LoadPlugin(pluginName)
-			if e != nil {
-				continue
-			}
-			pluginObj, err := pluginFactory.Create(authCfg, f.cluster, "", s.ID, 0)
+			pluginObj, err := f.plugins.create("auth:"+authKey, pluginName, authCfg, "", f.cluster, s.ID, 0)
 			if err != nil {
 				continue
 			}
@@ -109,8 +115,9 @@ func (f *_RootFactory) genStrategy(cfg *config.StrategyConfig) *Strategy {
 		}
 	}
 
-	factory := newAPIFactory(f, s.ID)
+	factory := newAPIFactory(f, s)
 
+	preflightPaths := make(map[string]*corsPolicy)
 	for _, apiCfg := range cfg.APIS {
 
 		iRouter, apiContent := factory.genAPIRouter(apiCfg)
@@ -121,6 +128,19 @@ func (f *_RootFactory) genStrategy(cfg *config.StrategyConfig) *Strategy {
 
 			s.apiRouter.AddRouter(strings.ToUpper(method), apiContent.RequestURL, iRouter)
 		}
+		if apiCors := iRouter.cors; apiCors != nil && apiCors != s.cors {
+			if _, has := preflightPaths[apiContent.RequestURL]; !has {
+				preflightPaths[apiContent.RequestURL] = apiCors
+			}
+		}
+	}
+
+	if s.cors != nil || len(preflightPaths) > 0 {
+		s.preflightRouter = f.routerFactory.New()
+		s.preflightRouter.AddNotFound(s.HandlerPreflightNotFound)
+		for path, policy := range preflightPaths {
+			addPreflightRouter(s.preflightRouter, path, policy)
+		}
 	}
 
 	return s
@@ -129,15 +149,19 @@ func (f *_RootFactory) genStrategy(cfg *config.StrategyConfig) *Strategy {
 type _ApiFactory struct {
 	root       *_RootFactory
 	strategyID string
+	cors       *corsPolicy
+	authorize  func(ctx *common.Context) bool
 }
 
-func newAPIFactory(root *_RootFactory, strategyID string) *_ApiFactory {
+func newAPIFactory(root *_RootFactory, s *Strategy) *_ApiFactory {
 	return &_ApiFactory{
 		root:       root,
-		strategyID: strategyID,
+		strategyID: s.ID,
+		cors:       s.cors,
+		authorize:  s.authorize,
 	}
 }
-func (f *_ApiFactory) genAPIRouter(cfg *config.APIOfStrategy) (router.IRouter, *config.APIContent) {
+func (f *_ApiFactory) genAPIRouter(cfg *config.APIOfStrategy) (*API, *config.APIContent) {
 
 	apiContend, has := f.root.apis[cfg.ID]
 	if !has {
@@ -148,10 +172,21 @@ func (f *_ApiFactory) genAPIRouter(cfg *config.APIOfStrategy) (router.IRouter, *
 	if err != nil {
 		return nil, nil
 	}
-	_, pluginAccesses, pluginProxies := genPlugins(cfg.Plugins, f.root.cluster, f.strategyID, cfg.ID)
+	_, pluginAccesses, pluginProxies := genPlugins(f.root.plugins, cfg.Plugins, f.root.cluster, f.strategyID, cfg.ID)
+
+	cors := f.cors
+	if apiCors := newCORSPolicy(apiContend.CORS); apiCors != nil {
+		cors = apiCors
+	}
 
 	return &API{
+		cors:                cors,
+		ipAccess:            newIPAccessPolicy(apiContend.IPAccess),
+		transform:           newTransformer(apiContend.Transform),
+		authorize:           f.authorize,
 		strategyID:          f.strategyID,
+		apiID:               apiContend.ID,
+		apiName:             apiContend.Name,
 		app:                 app,
 		pluginAccess:        pluginAccesses,
 		pluginProxies:       pluginProxies,
@@ -160,25 +195,30 @@ func (f *_ApiFactory) genAPIRouter(cfg *config.APIOfStrategy) (router.IRouter, *
 	}, apiContend
 }
 
-func genFactory(cfg *config.GokuConfig, factory router.Factory) *_RootFactory {
+//genFactory 构造根工厂，plugins、prevApps为上一次配置创建的插件对象及应用，未变化时复用；应用通过balances获取待生效的负载
+func genFactory(cfg *config.GokuConfig, factory router.Factory, plugins *pluginCache, prevApps *application.Factory, balances *balance.Resolver) *_RootFactory {
 
-	discovery.ResetAllServiceConfig(cfg.DiscoverConfig)
-	balance.ResetBalances(cfg.Balance)
-
-	beforePlugin := genBeforPlugin(cfg.Plugins.BeforePlugins, cfg.Cluster)
+	gatewayPlugins := cfg.Plugins
+	if gatewayPlugins == nil {
+		gatewayPlugins = new(config.GatewayPluginConfig)
+	}
+	beforePlugin := genBeforPlugin(plugins, gatewayPlugins.BeforePlugins, cfg.Cluster)
 
-	gBefores, gAccesses, gProxies := genPlugins(cfg.Plugins.GlobalPlugins, cfg.Cluster, "", 0)
+	gBefores, gAccesses, gProxies := genPlugins(plugins, gatewayPlugins.GlobalPlugins, cfg.Cluster, "", 0)
 
 	apis := toMap(cfg.APIS)
+	appFactory := application.NewFactory(apis, balances)
+	appFactory.Inherit(prevApps, balanceDigest(cfg))
 	return &_RootFactory{
 		beforePlugin:  beforePlugin,
 		gBefores:      gBefores,
 		gAccesses:     gAccesses,
 		gProxies:      gProxies,
-		appFactory:    application.NewFactory(apis),
+		appFactory:    appFactory,
 		apis:          apis,
 		routerFactory: factory,
 		cluster:       cfg.Cluster,
+		plugins:       plugins,
 		orgCfg:        cfg,
 		authPlugin:    cfg.AuthPlugin,
 	}
@@ -191,3 +231,10 @@ func toMap(cfgs []*config.APIContent) map[int]*config.APIContent {
 	}
 	return m
 }
+
+//balanceDigest 负载及服务发现配置的摘要，变化时不复用已创建的应用
+func balanceDigest(cfg *config.GokuConfig) string {
+	data, _ := json.Marshal([]interface{}{cfg.Balance, cfg.DiscoverConfig})
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -25,6 +25,11 @@ type Strategy struct {
 	authPlugin map[string]plugin_executor.Executor
 
 	isNeedAuth bool
+
+	cors            *corsPolicy
+	preflightRouter router.APIRouter
+
+	ipAccess *ipAccessPolicy
 }
 
 //Router router
@@ -43,19 +48,38 @@ func (r *Strategy) Router(w http.ResponseWriter, req *http.Request, ctx *common.
 	ctx.SetStrategyId(r.ID)
 	ctx.LogFields[access_field.Strategy] = fmt.Sprintf("\"%s %s\"", r.ID, r.Name)
 
-	if r.isNeedAuth {
-		// 需要校验
-		if !r.auth(ctx) {
-			// 校验失败
-			ctx.SetStatus(403, "403")
-			ctx.SetBody([]byte("[ERROR]Illegal authorization type!"))
+	// IP黑白名单在预检及鉴权前校验
+	if !r.ipAccess.Check(ctx, "strategy") {
+		return
+	}
+
+	if r.preflightRouter != nil && isPreflight(ctx) {
+		// 预检请求不携带鉴权信息，在鉴权前应答
+		r.preflightRouter.ServeHTTP(w, req, ctx)
+		if _, handled := ctx.GetCache(corsPreflightKey); handled {
 			return
 		}
 	}
 
+	// 鉴权在匹配接口后执行，保证接口IP黑白名单先于鉴权校验
 	r.apiRouter.ServeHTTP(w, req, ctx)
 }
 
+//authorize 鉴权，失败时返回403
+func (r *Strategy) authorize(ctx *common.Context) bool {
+	if !r.isNeedAuth {
+		return true
+	}
+	// 需要校验
+	if !r.auth(ctx) {
+		// 校验失败
+		ctx.SetStatus(403, "403")
+		ctx.SetBody([]byte("[ERROR]Illegal authorization type!"))
+		return false
+	}
+	return true
+}
+
 func (r *Strategy) auth(ctx *common.Context) bool {
 	requestID := ctx.RequestId()
 	authType := ctx.Request().GetHeader("Authorization-Type")
@@ -82,9 +106,9 @@ func (r *Strategy) auth(ctx *common.Context) bool {
 func (r *Strategy) accessFlow(ctx *common.Context) {
 	for _, handler := range r.accessPlugin {
 
-		flag, _ := handler.Execute(ctx)
+		flag, err := handler.Execute(ctx)
 
-		if flag == false && handler.IsStop() {
+		if plugin_executor.ShouldStop(handler, flag, err) {
 
 			return
 		}
@@ -99,8 +123,18 @@ func (r *Strategy) accessGlobalFlow(ctx *common.Context) {
 	}
 }
 
+//HandlerPreflightNotFound 预检请求未匹配到接口跨域配置时调用
+func (r *Strategy) HandlerPreflightNotFound(ctx *common.Context) {
+	if r.cors != nil {
+		r.cors.Preflight(ctx)
+	}
+}
+
 //HandlerAPINotFound 当接口不存在时调用
 func (r *Strategy) HandlerAPINotFound(ctx *common.Context) {
+	if !r.authorize(ctx) {
+		return
+	}
 	// 未匹配到api
 	// 执行策略access 插件
 	r.accessFlow(ctx)
@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+)
+
+type transformText string
+
+func (t transformText) Execution(variables *interpreter.Variables) string {
+	return string(t)
+}
+
+type transformRule struct {
+	action string
+	name   string
+	value  interpreter.Interpreter
+	target string
+}
+
+//transformer 请求/响应转换
+type transformer struct {
+	requestHeaders  []*transformRule
+	requestQuery    []*transformRule
+	responseHeaders []*transformRule
+}
+
+func newTransformer(cfg *config.TransformConfig) *transformer {
+	if cfg == nil {
+		return nil
+	}
+	if err := cfg.Check(); err != nil {
+		log.Warn("illegal transform config:", err)
+		return nil
+	}
+	t := &transformer{
+		requestHeaders:  genTransformRules(cfg.RequestHeaders, true),
+		requestQuery:    genTransformRules(cfg.RequestQuery, false),
+		responseHeaders: genTransformRules(cfg.ResponseHeaders, true),
+	}
+	if len(t.requestHeaders) == 0 && len(t.requestQuery) == 0 && len(t.responseHeaders) == 0 {
+		return nil
+	}
+	return t
+}
+
+func genTransformRules(rules []*config.TransformRule, isHeader bool) []*transformRule {
+	rs := make([]*transformRule, 0, len(rules))
+	for _, r := range rules {
+		rule := &transformRule{
+			action: strings.ToLower(r.Action),
+			name:   strings.TrimSpace(r.Name),
+		}
+		if isHeader {
+			rule.name = http.CanonicalHeaderKey(rule.name)
+		}
+		switch rule.action {
+		case config.TransformAdd, config.TransformSet:
+			value, err := interpreter.Parse(r.Value)
+			if err != nil {
+				log.Warn("illegal transform value [", r.Value, "]:", err)
+				value = transformText(r.Value)
+			}
+			rule.value = value
+		case config.TransformRename:
+			rule.target = strings.TrimSpace(r.Value)
+			if isHeader {
+				rule.target = http.CanonicalHeaderKey(rule.target)
+			}
+		}
+		rs = append(rs, rule)
+	}
+	return rs
+}
+
+func (t *transformer) variables(ctx *common.Context) *interpreter.Variables {
+	orgBody, _ := ctx.RequestOrg.RawBody()
+	variables := interpreter.NewVariables(orgBody, nil, ctx.RequestOrg.Headers(), ctx.RequestOrg.Cookies(), ctx.RestfulParam, ctx.RequestOrg.URL().Query(), 1)
+	variables.Context = application.ContextVariables(ctx)
+	return variables
+}
+
+//Request 转换转发请求的头部及参数，返回的变量用于响应转换
+func (t *transformer) Request(ctx *common.Context) *interpreter.Variables {
+	if len(t.requestHeaders) == 0 && len(t.requestQuery) == 0 {
+		return nil
+	}
+	variables := t.variables(ctx)
+	header := ctx.ProxyRequest
+	for _, r := range t.requestHeaders {
+		switch r.action {
+		case config.TransformAdd:
+			header.AddHeader(r.name, r.value.Execution(variables))
+		case config.TransformSet:
+			header.SetHeader(r.name, r.value.Execution(variables))
+		case config.TransformRemove:
+			header.DelHeader(r.name)
+		case config.TransformRename:
+			vs := header.Headers()[r.name]
+			header.DelHeader(r.name)
+			for _, v := range vs {
+				header.AddHeader(r.target, v)
+			}
+		}
+	}
+	transformQuery(ctx.ProxyRequest.Querys(), t.requestQuery, variables)
+	return variables
+}
+
+//Response 转换响应头部
+func (t *transformer) Response(ctx *common.Context, variables *interpreter.Variables) {
+	if len(t.responseHeaders) == 0 {
+		return
+	}
+	if variables == nil {
+		variables = t.variables(ctx)
+	}
+	for _, r := range t.responseHeaders {
+		switch r.action {
+		case config.TransformAdd:
+			ctx.AddHeader(r.name, r.value.Execution(variables))
+		case config.TransformSet:
+			ctx.SetHeader(r.name, r.value.Execution(variables))
+		case config.TransformRemove:
+			ctx.DelHeader(r.name)
+		case config.TransformRename:
+			vs := ctx.Headers()[r.name]
+			ctx.DelHeader(r.name)
+			for _, v := range vs {
+				ctx.AddHeader(r.target, v)
+			}
+		}
+	}
+}
+
+func transformQuery(query url.Values, rules []*transformRule, variables *interpreter.Variables) {
+	if query == nil {
+		return
+	}
+	for _, r := range rules {
+		switch r.action {
+		case config.TransformAdd:
+			query.Add(r.name, r.value.Execution(variables))
+		case config.TransformSet:
+			query.Set(r.name, r.value.Execution(variables))
+		case config.TransformRemove:
+			query.Del(r.name)
+		case config.TransformRename:
+			vs, has := query[r.name]
+			if !has {
+				continue
+			}
+			query.Del(r.name)
+			query[r.target] = append(query[r.target], vs...)
+		}
+	}
+}
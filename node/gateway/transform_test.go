@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+func TestTransformer(t *testing.T) {
+	tf := newTransformer(&config.TransformConfig{
+		RequestHeaders: []*config.TransformRule{
+			{Action: "set", Name: "X-Strategy", Value: "{{context.strategyID}}"},
+			{Action: "add", Name: "X-Client", Value: "ip={{context.clientIP}}"},
+			{Action: "remove", Name: "X-Secret"},
+			{Action: "rename", Name: "X-Old", Value: "X-New"},
+			{Action: "set", Name: "X-User", Value: "{{query.user}}"},
+		},
+		RequestQuery: []*config.TransformRule{
+			{Action: "set", Name: "token", Value: "{{header.X-Token}}"},
+			{Action: "remove", Name: "debug"},
+			{Action: "rename", Name: "user", Value: "uid"},
+		},
+		ResponseHeaders: []*config.TransformRule{
+			{Action: "remove", Name: "Server"},
+			{Action: "add", Name: "X-Api", Value: "{{context.apiID}}"},
+		},
+	})
+	if tf == nil {
+		t.Fatal("transformer is nil")
+	}
+
+	req := httptest.NewRequest("GET", "/test?user=u1&debug=1", nil)
+	req.Header.Set("X-Secret", "s")
+	req.Header.Set("X-Old", "o")
+	req.Header.Set("X-Token", "t1")
+	ctx := common.NewContext(req, "test", httptest.NewRecorder())
+	ctx.SetStrategyId("st1")
+	ctx.SetAPIID(12)
+	ctx.SetClientIP("1.2.3.4")
+
+	variables := tf.Request(ctx)
+	headers := map[string]string{
+		"X-Strategy": "st1",
+		"X-Client":   "ip=1.2.3.4",
+		"X-Secret":   "",
+		"X-Old":      "",
+		"X-New":      "o",
+		"X-User":     "u1",
+	}
+	for k, want := range headers {
+		if got := ctx.ProxyRequest.GetHeader(k); got != want {
+			t.Errorf("header %s: got %q, want %q", k, got, want)
+		}
+	}
+	query := ctx.ProxyRequest.Querys()
+	if query.Get("token") != "t1" || query.Get("debug") != "" || query.Get("user") != "" || query.Get("uid") != "u1" {
+		t.Errorf("query: %v", query)
+	}
+
+	ctx.SetHeader("Server", "backend")
+	tf.Response(ctx, variables)
+	if ctx.GetHeader("Server") != "" || ctx.GetHeader("X-Api") != "12" {
+		t.Errorf("response header: %v", ctx.Headers())
+	}
+
+	if (&config.TransformConfig{RequestHeaders: []*config.TransformRule{{Action: "rename", Name: "a"}}}).Check() == nil {
+		t.Error("expect rename target error")
+	}
+}
@@ -0,0 +1,173 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+//DefaultPeriod 默认上报周期
+const DefaultPeriod = time.Second * 30
+
+//maxKeepHours 上报失败时最多保留的小时数
+const maxKeepHours = 24
+
+type itemKey struct {
+	strategyID string
+	apiID      int
+	hour       int64
+}
+
+//Collector 在内存中按策略、接口、小时聚合请求计数
+type Collector struct {
+	lock        sync.Mutex
+	successCode map[int]bool
+	items       map[itemKey]*config.MonitorItem
+}
+
+//NewCollector 创建聚合器
+func NewCollector() *Collector {
+	return &Collector{
+		successCode: map[int]bool{200: true},
+		items:       make(map[itemKey]*config.MonitorItem),
+	}
+}
+
+//SetSuccessCode 设置视为成功的状态码，为空时使用200
+func (c *Collector) SetSuccessCode(codes []int) {
+	successCode := make(map[int]bool)
+	for _, code := range codes {
+		successCode[code] = true
+	}
+	if len(successCode) == 0 {
+		successCode[200] = true
+	}
+	c.lock.Lock()
+	c.successCode = successCode
+	c.lock.Unlock()
+}
+
+//Record 记录一次请求，proxyStatus为0表示未转发，转发超时时为504
+func (c *Collector) Record(strategyID string, apiID int, status, proxyStatus int, cost time.Duration, now time.Time) {
+	if strategyID == "" {
+		return
+	}
+	key := itemKey{strategyID: strategyID, apiID: apiID, hour: now.Unix() / 3600 * 3600}
+	requestTime := int64(cost / time.Millisecond)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	item, has := c.items[key]
+	if !has {
+		item = &config.MonitorItem{StrategyID: strategyID, APIID: apiID, Hour: key.hour}
+		c.items[key] = item
+	}
+	item.GatewayRequestCount++
+	if c.successCode[status] {
+		item.GatewaySuccessCount++
+	}
+	switch status / 100 {
+	case 2:
+		item.GatewayStatus2xxCount++
+	case 4:
+		item.GatewayStatus4xxCount++
+	case 5:
+		item.GatewayStatus5xxCount++
+	}
+	item.RequestTime += requestTime
+	if requestTime > item.MaxRequestTime {
+		item.MaxRequestTime = requestTime
+	}
+	if proxyStatus == 0 {
+		return
+	}
+	item.ProxyRequestCount++
+	if c.successCode[proxyStatus] {
+		item.ProxySuccessCount++
+	}
+	switch proxyStatus / 100 {
+	case 2:
+		item.ProxyStatus2xxCount++
+	case 4:
+		item.ProxyStatus4xxCount++
+	case 5:
+		item.ProxyStatus5xxCount++
+	}
+	if proxyStatus == 504 {
+		item.ProxyTimeoutCount++
+	}
+}
+
+//Collect 取出当前聚合的计数并清空
+func (c *Collector) Collect() []*config.MonitorItem {
+	c.lock.Lock()
+	items := c.items
+	c.items = make(map[itemKey]*config.MonitorItem)
+	c.lock.Unlock()
+
+	result := make([]*config.MonitorItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, item)
+	}
+	return result
+}
+
+//Restore 上报失败时将计数合并回聚合器，超过保留时长的计数被丢弃
+func (c *Collector) Restore(items []*config.MonitorItem, now time.Time) {
+	oldest := now.Unix()/3600*3600 - maxKeepHours*3600
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, item := range items {
+		if item.Hour < oldest {
+			continue
+		}
+		key := itemKey{strategyID: item.StrategyID, apiID: item.APIID, hour: item.Hour}
+		current, has := c.items[key]
+		if !has {
+			c.items[key] = item
+			continue
+		}
+		merge(current, item)
+	}
+}
+
+func merge(to, from *config.MonitorItem) {
+	to.GatewayRequestCount += from.GatewayRequestCount
+	to.GatewaySuccessCount += from.GatewaySuccessCount
+	to.GatewayStatus2xxCount += from.GatewayStatus2xxCount
+	to.GatewayStatus4xxCount += from.GatewayStatus4xxCount
+	to.GatewayStatus5xxCount += from.GatewayStatus5xxCount
+	to.ProxyRequestCount += from.ProxyRequestCount
+	to.ProxySuccessCount += from.ProxySuccessCount
+	to.ProxyStatus2xxCount += from.ProxyStatus2xxCount
+	to.ProxyStatus4xxCount += from.ProxyStatus4xxCount
+	to.ProxyStatus5xxCount += from.ProxyStatus5xxCount
+	to.ProxyTimeoutCount += from.ProxyTimeoutCount
+	to.RequestTime += from.RequestTime
+	if from.MaxRequestTime > to.MaxRequestTime {
+		to.MaxRequestTime = from.MaxRequestTime
+	}
+}
+
+var defaultCollector = NewCollector()
+
+//SetSuccessCode 设置视为成功的状态码
+func SetSuccessCode(codes []int) {
+	defaultCollector.SetSuccessCode(codes)
+}
+
+//Record 记录一次请求
+func Record(strategyID string, apiID int, status, proxyStatus int, cost time.Duration) {
+	defaultCollector.Record(strategyID, apiID, status, proxyStatus, cost, time.Now())
+}
+
+//Collect 取出当前聚合的计数
+func Collect() []*config.MonitorItem {
+	return defaultCollector.Collect()
+}
+
+//Restore 将上报失败的计数合并回聚合器
+func Restore(items []*config.MonitorItem) {
+	defaultCollector.Restore(items, time.Now())
+}
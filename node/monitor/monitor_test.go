@@ -0,0 +1,42 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCollector(t *testing.T) {
+	now := time.Unix(7200+10, 0)
+	c := NewCollector()
+	c.SetSuccessCode([]int{200, 201})
+	c.Record("s1", 1, 200, 200, 10*time.Millisecond, now)
+	c.Record("s1", 1, 201, 0, 30*time.Millisecond, now)
+	c.Record("s1", 1, 504, 504, 5*time.Millisecond, now)
+	c.Record("", 1, 200, 200, time.Millisecond, now)
+
+	items := c.Collect()
+	if len(items) != 1 {
+		t.Fatalf("expect 1 item, got %d", len(items))
+	}
+	item := items[0]
+	if item.Hour != 7200 || item.GatewayRequestCount != 3 || item.GatewaySuccessCount != 2 || item.GatewayStatus5xxCount != 1 {
+		t.Errorf("unexpected gateway count %+v", item)
+	}
+	if item.ProxyRequestCount != 2 || item.ProxySuccessCount != 1 || item.ProxyTimeoutCount != 1 {
+		t.Errorf("unexpected proxy count %+v", item)
+	}
+	if item.RequestTime != 45 || item.MaxRequestTime != 30 {
+		t.Errorf("unexpected request time %+v", item)
+	}
+	if len(c.Collect()) != 0 {
+		t.Error("collect should reset the counts")
+	}
+
+	c.Record("s1", 1, 200, 200, 50*time.Millisecond, now)
+	c.Restore(items, now)
+	c.Restore(items, now.Add(maxKeepHours*time.Hour+time.Hour))
+	items = c.Collect()
+	if len(items) != 1 || items[0].GatewayRequestCount != 4 || items[0].MaxRequestTime != 50 {
+		t.Errorf("unexpected restored items %+v", items[0])
+	}
+}
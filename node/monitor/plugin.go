@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+const (
+	//PluginOK 插件执行完成
+	PluginOK = iota
+	//PluginError 插件返回错误
+	PluginError
+	//PluginPanic 插件panic
+	PluginPanic
+	//PluginTimeout 插件执行超时
+	PluginTimeout
+)
+
+type pluginKey struct {
+	name string
+	hour int64
+}
+
+//PluginCollector 在内存中按插件、小时聚合插件执行计数
+type PluginCollector struct {
+	lock  sync.Mutex
+	items map[pluginKey]*config.PluginMonitorItem
+}
+
+//NewPluginCollector 创建插件计数聚合器
+func NewPluginCollector() *PluginCollector {
+	return &PluginCollector{
+		items: make(map[pluginKey]*config.PluginMonitorItem),
+	}
+}
+
+//Record 记录一次插件执行
+func (c *PluginCollector) Record(name string, result int, cost time.Duration, now time.Time) {
+	key := pluginKey{name: name, hour: now.Unix() / 3600 * 3600}
+	callTime := int64(cost / time.Microsecond)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	item, has := c.items[key]
+	if !has {
+		item = &config.PluginMonitorItem{PluginName: name, Hour: key.hour}
+		c.items[key] = item
+	}
+	item.CallCount++
+	switch result {
+	case PluginError:
+		item.ErrorCount++
+	case PluginPanic:
+		item.PanicCount++
+	case PluginTimeout:
+		item.TimeoutCount++
+	}
+	item.CallTime += callTime
+	if callTime > item.MaxCallTime {
+		item.MaxCallTime = callTime
+	}
+}
+
+//Collect 取出当前聚合的计数并清空
+func (c *PluginCollector) Collect() []*config.PluginMonitorItem {
+	c.lock.Lock()
+	items := c.items
+	c.items = make(map[pluginKey]*config.PluginMonitorItem)
+	c.lock.Unlock()
+
+	result := make([]*config.PluginMonitorItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, item)
+	}
+	return result
+}
+
+//Restore 上报失败时将计数合并回聚合器，超过保留时长的计数被丢弃
+func (c *PluginCollector) Restore(items []*config.PluginMonitorItem, now time.Time) {
+	oldest := now.Unix()/3600*3600 - maxKeepHours*3600
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, item := range items {
+		if item.Hour < oldest {
+			continue
+		}
+		key := pluginKey{name: item.PluginName, hour: item.Hour}
+		current, has := c.items[key]
+		if !has {
+			c.items[key] = item
+			continue
+		}
+		current.CallCount += item.CallCount
+		current.ErrorCount += item.ErrorCount
+		current.PanicCount += item.PanicCount
+		current.TimeoutCount += item.TimeoutCount
+		current.CallTime += item.CallTime
+		if item.MaxCallTime > current.MaxCallTime {
+			current.MaxCallTime = item.MaxCallTime
+		}
+	}
+}
+
+var defaultPluginCollector = NewPluginCollector()
+
+//RecordPlugin 记录一次插件执行
+func RecordPlugin(name string, result int, cost time.Duration) {
+	defaultPluginCollector.Record(name, result, cost, time.Now())
+}
+
+//CollectPlugins 取出当前聚合的插件计数
+func CollectPlugins() []*config.PluginMonitorItem {
+	return defaultPluginCollector.Collect()
+}
+
+//RestorePlugins 将上报失败的插件计数合并回聚合器
+func RestorePlugins(items []*config.PluginMonitorItem) {
+	defaultPluginCollector.Restore(items, time.Now())
+}
@@ -0,0 +1,288 @@
+package plugin_loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+const (
+	//PhaseBefore 匹配接口前
+	PhaseBefore = "before"
+	//PhaseAccess 转发前
+	PhaseAccess = "access"
+	//PhaseProxy 转发后
+	PhaseProxy = "proxy"
+
+	//FailOpen 调用插件失败时继续处理请求
+	FailOpen = "open"
+	//FailClosed 调用插件失败时中断请求
+	FailClosed = "closed"
+)
+
+const defaultFailStatus = http.StatusServiceUnavailable
+
+//CallConfig 进程外插件及Wasm插件的公共配置
+type CallConfig struct {
+	//FailPolicy 调用失败时的处理方式：open继续处理请求，closed中断请求
+	FailPolicy string `json:"failPolicy"`
+	//FailStatus 中断请求时返回的状态码
+	FailStatus int `json:"failStatus"`
+	//Body 是否向插件发送请求及响应内容
+	Body bool `json:"body"`
+}
+
+func (c *CallConfig) check() error {
+	if c.FailStatus == 0 {
+		c.FailStatus = defaultFailStatus
+	}
+	switch c.FailPolicy {
+	case "":
+		c.FailPolicy = FailOpen
+	case FailOpen, FailClosed:
+	default:
+		return fmt.Errorf("illegal failPolicy %s, expect open or closed", c.FailPolicy)
+	}
+	return nil
+}
+
+//caller 调用插件的一个阶段，c到期时中断调用
+type caller interface {
+	call(c context.Context, req *remoteRequest) (*remoteReply, error)
+}
+
+//Cancelable 可按context中断调用的插件，进程外插件及Wasm插件实现，插件执行器据此实现插件超时
+type Cancelable interface {
+	//CallPhase 执行插件的phase阶段，c到期时中断调用
+	CallPhase(c context.Context, phase string, ctx goku_plugin.Context) (bool, error)
+}
+
+//remoteRequest 发送给插件的请求
+type remoteRequest struct {
+	Plugin     string `json:"plugin"`
+	Phase      string `json:"phase"`
+	Config     string `json:"config"`
+	Cluster    string `json:"cluster"`
+	UpdateTag  string `json:"updateTag"`
+	StrategyID string `json:"strategyID"`
+	APIID      int    `json:"apiID"`
+	RequestID  string `json:"requestID"`
+	//Request 原始请求，before、access阶段
+	Request *remoteHTTPRequest `json:"request,omitempty"`
+	//Proxy 转发请求，before、access阶段
+	Proxy *remoteProxyRequest `json:"proxy,omitempty"`
+	//Response 转发后的响应，proxy阶段
+	Response *remoteResponse `json:"response,omitempty"`
+}
+
+type remoteHTTPRequest struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Host       string      `json:"host"`
+	RemoteAddr string      `json:"remoteAddr"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+type remoteProxyRequest struct {
+	Header       http.Header `json:"header"`
+	Query        url.Values  `json:"query"`
+	TargetServer string      `json:"targetServer"`
+	TargetURL    string      `json:"targetURL"`
+	Body         []byte      `json:"body,omitempty"`
+}
+
+type remoteResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+//remoteReply 插件的返回，body字段为base64编码
+type remoteReply struct {
+	Continue bool   `json:"continue"`
+	Error    string `json:"error"`
+	//ProxyHeader、ProxyDelHeader、ProxyQuery、ProxyBody 修改转发请求，before、access阶段有效
+	ProxyHeader    map[string]string `json:"proxyHeader"`
+	ProxyDelHeader []string          `json:"proxyDelHeader"`
+	ProxyQuery     map[string]string `json:"proxyQuery"`
+	ProxyBody      []byte            `json:"proxyBody"`
+	//StatusCode、Header、Body 设置返回给客户端的内容
+	StatusCode int               `json:"statusCode"`
+	Header     map[string]string `json:"header"`
+	Body       []byte            `json:"body"`
+}
+
+//remotePlugin 将插件的各阶段转换为对caller的调用
+type remotePlugin struct {
+	name   string
+	config *CallConfig
+	caller caller
+	info   remoteRequest
+}
+
+//newPluginObj 按phases创建插件对象
+func newPluginObj(p *remotePlugin, phases map[string]bool) *goku_plugin.PluginObj {
+	obj := new(goku_plugin.PluginObj)
+	if phases[PhaseBefore] {
+		obj.BeforeMatch = p
+	}
+	if phases[PhaseAccess] {
+		obj.Access = p
+	}
+	if phases[PhaseProxy] {
+		obj.Proxy = p
+	}
+	return obj
+}
+
+//BeforeMatch 匹配接口前调用插件
+func (p *remotePlugin) BeforeMatch(ctx goku_plugin.ContextBeforeMatch) (bool, error) {
+	return p.before(context.Background(), ctx)
+}
+
+//Access 转发前调用插件
+func (p *remotePlugin) Access(ctx goku_plugin.ContextAccess) (bool, error) {
+	return p.access(context.Background(), ctx)
+}
+
+//Proxy 转发后调用插件
+func (p *remotePlugin) Proxy(ctx goku_plugin.ContextProxy) (bool, error) {
+	return p.proxy(context.Background(), ctx)
+}
+
+//CallPhase 执行插件的phase阶段，c到期时中断调用
+func (p *remotePlugin) CallPhase(c context.Context, phase string, ctx goku_plugin.Context) (bool, error) {
+	switch phase {
+	case PhaseBefore:
+		if v, ok := ctx.(goku_plugin.ContextBeforeMatch); ok {
+			return p.before(c, v)
+		}
+	case PhaseAccess:
+		if v, ok := ctx.(goku_plugin.ContextAccess); ok {
+			return p.access(c, v)
+		}
+	case PhaseProxy:
+		if v, ok := ctx.(goku_plugin.ContextProxy); ok {
+			return p.proxy(c, v)
+		}
+	}
+	return true, fmt.Errorf("plugin %s does not support phase %s", p.name, phase)
+}
+
+func (p *remotePlugin) before(c context.Context, ctx goku_plugin.ContextBeforeMatch) (bool, error) {
+	req := p.newRequest(PhaseBefore, ctx)
+	req.Request = p.readRequest(ctx.Request())
+	req.Proxy = p.readProxy(ctx.Proxy())
+	return p.call(c, ctx, req, ctx.Proxy())
+}
+
+func (p *remotePlugin) access(c context.Context, ctx goku_plugin.ContextAccess) (bool, error) {
+	req := p.newRequest(PhaseAccess, ctx)
+	req.Request = p.readRequest(ctx.Request())
+	req.Proxy = p.readProxy(ctx.Proxy())
+	return p.call(c, ctx, req, ctx.Proxy())
+}
+
+func (p *remotePlugin) proxy(c context.Context, ctx goku_plugin.ContextProxy) (bool, error) {
+	req := p.newRequest(PhaseProxy, ctx)
+	if r := ctx.ProxyResponse(); r != nil && !reflect.ValueOf(r).IsNil() {
+		req.Response = &remoteResponse{StatusCode: r.StatusCode(), Header: r.Headers()}
+		if p.config.Body {
+			req.Response.Body = r.GetBody()
+		}
+	}
+	return p.call(c, ctx, req, nil)
+}
+
+func (p *remotePlugin) newRequest(phase string, ctx goku_plugin.Context) *remoteRequest {
+	req := p.info
+	req.Phase = phase
+	req.RequestID = ctx.RequestId()
+	return &req
+}
+
+func (p *remotePlugin) readRequest(r goku_plugin.RequestReader) *remoteHTTPRequest {
+	req := &remoteHTTPRequest{
+		Method:     r.Method(),
+		Host:       r.Host(),
+		RemoteAddr: r.RemoteAddr(),
+		Header:     r.Headers(),
+	}
+	if u := r.URL(); u != nil {
+		req.URL = u.String()
+	}
+	if p.config.Body {
+		req.Body, _ = r.RawBody()
+	}
+	return req
+}
+
+func (p *remotePlugin) readProxy(r goku_plugin.Request) *remoteProxyRequest {
+	req := &remoteProxyRequest{
+		Header:       r.Headers(),
+		Query:        r.Querys(),
+		TargetServer: r.TargetServer(),
+		TargetURL:    r.TargetURL(),
+	}
+	if p.config.Body {
+		req.Body, _ = r.RawBody()
+	}
+	return req
+}
+
+//call 调用插件并应用返回，proxy为nil时忽略对转发请求的修改
+func (p *remotePlugin) call(c context.Context, ctx goku_plugin.Context, req *remoteRequest, proxy goku_plugin.Request) (bool, error) {
+	reply, err := p.caller.call(c, req)
+	if err != nil {
+		return p.fail(ctx, err)
+	}
+	if proxy != nil {
+		for key, value := range reply.ProxyHeader {
+			proxy.SetHeader(key, value)
+		}
+		for _, key := range reply.ProxyDelHeader {
+			proxy.DelHeader(key)
+		}
+		if len(reply.ProxyQuery) > 0 {
+			query := proxy.Querys()
+			for key, value := range reply.ProxyQuery {
+				query.Set(key, value)
+			}
+		}
+		if reply.ProxyBody != nil {
+			proxy.SetRaw(proxy.ContentType(), reply.ProxyBody)
+		}
+	}
+	for key, value := range reply.Header {
+		ctx.SetHeader(key, value)
+	}
+	if reply.StatusCode > 0 {
+		ctx.SetStatus(reply.StatusCode, strconv.Itoa(reply.StatusCode))
+	}
+	if reply.Body != nil {
+		ctx.SetBody(reply.Body)
+	}
+	if reply.Error != "" {
+		return reply.Continue, errors.New(reply.Error)
+	}
+	return reply.Continue, nil
+}
+
+//fail 调用失败时按插件的失败策略处理
+func (p *remotePlugin) fail(ctx goku_plugin.Context, err error) (bool, error) {
+	err = fmt.Errorf("call plugin %s error: %s", p.name, err)
+	if p.config.FailPolicy != FailClosed {
+		return true, err
+	}
+	status := p.config.FailStatus
+	ctx.SetStatus(status, strconv.Itoa(status))
+	ctx.SetBody([]byte("[ERROR]Plugin " + p.name + " is unavailable!"))
+	return false, err
+}
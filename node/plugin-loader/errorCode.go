@@ -9,4 +9,10 @@ const (
 	LoadLookupError
 	//LoadInterFaceError interfaceError
 	LoadInterFaceError
+	//LoadRemoteConfigError 进程外插件配置错误
+	LoadRemoteConfigError
+	//LoadWasmError Wasm插件加载错误
+	LoadWasmError
+	//LoadCreateError 插件加载成功但按配置创建插件对象失败
+	LoadCreateError
 )
@@ -0,0 +1,50 @@
+package plugin_loader
+
+import (
+	"io"
+	"reflect"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+//ReleasePlugin 释放配置更新后不再使用的插件对象，BeforeMatch、Access、Proxy实现了io.Closer时调用Close，同一对象只调用一次
+func ReleasePlugin(name string, obj *goku_plugin.PluginObj) {
+	if obj == nil {
+		return
+	}
+	closers := make([]io.Closer, 0, 3)
+	for _, p := range []interface{}{obj.BeforeMatch, obj.Access, obj.Proxy} {
+		c, ok := p.(io.Closer)
+		if !ok || reflect.ValueOf(c).IsNil() || containsCloser(closers, c) {
+			continue
+		}
+		closers = append(closers, c)
+	}
+	for _, c := range closers {
+		closePlugin(name, c)
+	}
+}
+
+func containsCloser(closers []io.Closer, c io.Closer) bool {
+	if !reflect.TypeOf(c).Comparable() {
+		return false
+	}
+	for _, v := range closers {
+		if reflect.TypeOf(v) == reflect.TypeOf(c) && v == c {
+			return true
+		}
+	}
+	return false
+}
+
+func closePlugin(name string, c io.Closer) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("close plugin ", name, " panic:", r)
+		}
+	}()
+	if err := c.Close(); err != nil {
+		log.Warn("close plugin ", name, " error:", err)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"path/filepath"
 	"plugin"
 	"reflect"
+	"sort"
 	"sync"
 
+	"github.com/eolinker/goku-api-gateway/config"
 	goku_plugin "github.com/eolinker/goku-plugin"
 )
 
@@ -63,6 +65,12 @@ func LoadPlugin(name string)(goku_plugin.PluginFactory ,error) {
 
 }
 
+//LoadPluginStatus 加载插件，同时返回加载状态码（LoadOk等）
+func LoadPluginStatus(name string) (goku_plugin.PluginFactory, int, error) {
+	factory, err, code := globalPluginManager.loadPlugin(name)
+	return factory, code, err
+}
+
 // 加载动态库
 func (m *_GlodPluginManager) loadPlugin(name string) (goku_plugin.PluginFactory, error, int) {
 	handle, has := m.getPluginHandle(name)
@@ -77,6 +85,58 @@ func (m *_GlodPluginManager) loadPlugin(name string) (goku_plugin.PluginFactory,
 		return handle, nil, LoadOk
 	}
 
+	// 编译进节点的插件优先加载
+	builtin, isBuiltin, err := loadBuiltin(name)
+	if isBuiltin {
+		if err != nil {
+			m.errors[name] = err
+			m.errorCodes[name] = LoadInterFaceError
+			return nil, err, LoadInterFaceError
+		}
+		m.gloadPlugin[name] = builtin
+		m.errorCodes[name] = LoadOk
+		m.errors[name] = nil
+		return builtin, nil, LoadOk
+	}
+
+	// 存在 plugin/插件名.json 时作为进程外插件加载
+	remoteConfig, isRemote, err := loadRemoteConfig(fmt.Sprintf("plugin/%s.json", name))
+	if isRemote {
+		var factory *remoteFactory
+		if err == nil {
+			factory, err = newRemoteFactory(name, remoteConfig)
+		}
+		if err != nil {
+			e := fmt.Errorf("The remote plugin config 'plugin/%s.json' is illegal:%s ", name, err.Error())
+			m.errors[name] = e
+			m.errorCodes[name] = LoadRemoteConfigError
+			return nil, e, LoadRemoteConfigError
+		}
+		m.gloadPlugin[name] = factory
+		m.errorCodes[name] = LoadOk
+		m.errors[name] = nil
+		return factory, nil, LoadOk
+	}
+
+	// 存在 plugin/插件名.wasm 时作为Wasm插件加载
+	binary, wasmConfig, isWasm, err := loadWasmConfig(fmt.Sprintf("plugin/%s.wasm", name))
+	if isWasm {
+		var factory *wasmFactory
+		if err == nil {
+			factory, err = newWasmFactory(name, binary, wasmConfig)
+		}
+		if err != nil {
+			e := fmt.Errorf("The wasm plugin 'plugin/%s.wasm' can not be loaded:%s ", name, err.Error())
+			m.errors[name] = e
+			m.errorCodes[name] = LoadWasmError
+			return nil, e, LoadWasmError
+		}
+		m.gloadPlugin[name] = factory
+		m.errorCodes[name] = LoadOk
+		m.errors[name] = nil
+		return factory, nil, LoadOk
+	}
+
 	path, _ := filepath.Abs(fmt.Sprintf("plugin/%s.so", name))
 	pdll, err := plugin.Open(path)
 	if err != nil {
@@ -120,3 +180,29 @@ func (m *_GlodPluginManager) loadPlugin(name string) (goku_plugin.PluginFactory,
 	return factory, nil, LoadOk
 
 }
+
+//GetLoadStatus 获取已加载插件的状态
+func GetLoadStatus() []*config.PluginLoadStatus {
+	return globalPluginManager.loadStatus()
+}
+
+func (m *_GlodPluginManager) loadStatus() []*config.PluginLoadStatus {
+	m.gloadPluginLocker.RLock()
+	defer m.gloadPluginLocker.RUnlock()
+
+	status := make([]*config.PluginLoadStatus, 0, len(m.errorCodes))
+	for name, code := range m.errorCodes {
+		s := &config.PluginLoadStatus{
+			Name: name,
+			Code: code,
+		}
+		if err := m.errors[name]; err != nil {
+			s.Error = err.Error()
+		}
+		status = append(status, s)
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Name < status[j].Name
+	})
+	return status
+}
@@ -0,0 +1,58 @@
+package plugin_loader
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+//Builder 创建插件工厂，与.so插件导出的Builder相同
+type Builder func() goku_plugin.PluginFactory
+
+var (
+	builtinPlugins       = make(map[string]Builder)
+	builtinPluginsLocker sync.RWMutex
+)
+
+//Register 注册编译进节点的插件，通常在插件包的init中调用；同名插件优先于plugin目录下的插件加载
+func Register(name string, builder Builder) {
+	builtinPluginsLocker.Lock()
+	defer builtinPluginsLocker.Unlock()
+	if builder == nil {
+		panic("plugin_loader: Register builder is nil for plugin " + name)
+	}
+	if _, has := builtinPlugins[name]; has {
+		panic("plugin_loader: Register called twice for plugin " + name)
+	}
+	builtinPlugins[name] = builder
+}
+
+//Registered 获取编译进节点的插件名称
+func Registered() []string {
+	builtinPluginsLocker.RLock()
+	defer builtinPluginsLocker.RUnlock()
+	names := make([]string, 0, len(builtinPlugins))
+	for name := range builtinPlugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//loadBuiltin 创建编译进节点的插件工厂，未注册时返回false
+func loadBuiltin(name string) (goku_plugin.PluginFactory, bool, error) {
+	builtinPluginsLocker.RLock()
+	builder, has := builtinPlugins[name]
+	builtinPluginsLocker.RUnlock()
+	if !has {
+		return nil, false, nil
+	}
+	factory := builder()
+	if factory == nil || reflect.ValueOf(factory).IsNil() {
+		return nil, true, fmt.Errorf("The builder result is nil:%s ", name)
+	}
+	return factory, true, nil
+}
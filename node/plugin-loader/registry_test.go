@@ -0,0 +1,52 @@
+package plugin_loader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+type testFactory struct{}
+
+func (f *testFactory) Create(config string, clusterName string, updateTag string, strategyID string, apiID int) (*goku_plugin.PluginObj, error) {
+	return new(goku_plugin.PluginObj), nil
+}
+
+func TestRegister(t *testing.T) {
+	factory := new(testFactory)
+	Register("builtin-ok", func() goku_plugin.PluginFactory { return factory })
+	Register("builtin-nil", func() goku_plugin.PluginFactory { return (*testFactory)(nil) })
+
+	// 同名的进程外插件配置不会被使用
+	dir, err := ioutil.TempDir("", "goku-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(filepath.Join(dir, "plugin"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "plugin", "builtin-ok.json"), []byte(`{"endpoint":"ftp://a"}`), 0644)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	if f, err := LoadPlugin("builtin-ok"); err != nil || f != factory {
+		t.Errorf("expect builtin factory, got %v %v", f, err)
+	}
+	if code, err := globalPluginManager.check("builtin-nil"); code != LoadInterFaceError || err == nil {
+		t.Errorf("expect interface error, got %d %v", code, err)
+	}
+	names := Registered()
+	if len(names) != 2 || names[0] != "builtin-nil" || names[1] != "builtin-ok" {
+		t.Errorf("unexpected registered plugins %v", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expect duplicate register to panic")
+		}
+	}()
+	Register("builtin-ok", func() goku_plugin.PluginFactory { return factory })
+}
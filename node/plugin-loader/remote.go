@@ -0,0 +1,185 @@
+package plugin_loader
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+const (
+	defaultRemoteTimeout      = 200
+	defaultRemoteMaxIdleConns = 16
+)
+
+//RemoteConfig 进程外插件配置，保存在plugin/插件名.json；插件以独立进程或sidecar运行，网关通过HTTP调用
+type RemoteConfig struct {
+	CallConfig
+	//Endpoint 插件地址，如 http://127.0.0.1:9000 或 unix:///var/run/plugin.sock
+	Endpoint string `json:"endpoint"`
+	//Timeout 每次调用的超时时间，单位毫秒
+	Timeout int `json:"timeout"`
+	//MaxIdleConns 保持的空闲连接数
+	MaxIdleConns int `json:"maxIdleConns"`
+	//Phases 插件处理的阶段：before、access、proxy，为空时处理全部阶段
+	Phases []string `json:"phases"`
+}
+
+func (c *RemoteConfig) check() error {
+	if err := c.CallConfig.check(); err != nil {
+		return err
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultRemoteTimeout
+	}
+	if c.MaxIdleConns <= 0 {
+		c.MaxIdleConns = defaultRemoteMaxIdleConns
+	}
+	if len(c.Phases) == 0 {
+		c.Phases = []string{PhaseBefore, PhaseAccess, PhaseProxy}
+	}
+	for _, phase := range c.Phases {
+		if phase != PhaseBefore && phase != PhaseAccess && phase != PhaseProxy {
+			return fmt.Errorf("illegal phase %s", phase)
+		}
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "unix" {
+		if u.Path == "" {
+			return errors.New("illegal endpoint, unix socket path is empty")
+		}
+		return nil
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("illegal endpoint %s", c.Endpoint)
+	}
+	return nil
+}
+
+//remoteFactory 进程外插件，同一插件的全部实例共用连接池
+type remoteFactory struct {
+	name    string
+	config  *RemoteConfig
+	phases  map[string]bool
+	baseURL string
+	client  *http.Client
+}
+
+func newRemoteFactory(name string, config *RemoteConfig) (*remoteFactory, error) {
+	if err := config.check(); err != nil {
+		return nil, err
+	}
+	phases := make(map[string]bool)
+	for _, phase := range config.Phases {
+		phases[phase] = true
+	}
+	transport := &http.Transport{
+		MaxIdleConns:        config.MaxIdleConns,
+		MaxIdleConnsPerHost: config.MaxIdleConns,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	baseURL := strings.TrimSuffix(config.Endpoint, "/")
+	if strings.HasPrefix(config.Endpoint, "unix://") {
+		socket := strings.TrimPrefix(config.Endpoint, "unix://")
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+		baseURL = "http://" + name
+	}
+	return &remoteFactory{
+		name:    name,
+		config:  config,
+		phases:  phases,
+		baseURL: baseURL,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(config.Timeout) * time.Millisecond,
+		},
+	}, nil
+}
+
+//Create 创建插件实例，插件配置随每次调用发送给插件
+func (f *remoteFactory) Create(config string, clusterName string, updateTag string, strategyID string, apiID int) (*goku_plugin.PluginObj, error) {
+	return newPluginObj(&remotePlugin{
+		name:   f.name,
+		config: &f.config.CallConfig,
+		caller: f,
+		info: remoteRequest{
+			Plugin:     f.name,
+			Config:     config,
+			Cluster:    clusterName,
+			UpdateTag:  updateTag,
+			StrategyID: strategyID,
+			APIID:      apiID,
+		},
+	}, f.phases), nil
+}
+
+//call 以JSON POST到 插件地址/阶段名
+func (f *remoteFactory) call(c context.Context, req *remoteRequest) (*remoteReply, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest(http.MethodPost, f.baseURL+"/"+req.Phase, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	resp, err := f.client.Do(request.WithContext(c))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response status %d", resp.StatusCode)
+	}
+	reply := new(remoteReply)
+	if err := json.Unmarshal(data, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+//loadRemoteConfig 读取进程外插件配置，文件不存在时返回false
+func loadRemoteConfig(path string) (*RemoteConfig, bool, error) {
+	data, has, err := readOptionalFile(path)
+	if !has || err != nil {
+		return nil, has, err
+	}
+	c := new(RemoteConfig)
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, true, err
+	}
+	return c, true, nil
+}
+
+//readOptionalFile 读取文件，文件不存在时返回false
+func readOptionalFile(path string) ([]byte, bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	return data, true, nil
+}
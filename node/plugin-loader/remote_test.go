@@ -0,0 +1,124 @@
+package plugin_loader
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+func TestRemotePlugin(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := new(remoteRequest)
+		json.NewDecoder(r.Body).Decode(req)
+		if r.URL.Path != "/access" || req.Phase != PhaseAccess || req.Config != "{}" || req.StrategyID != "s1" {
+			t.Errorf("unexpected request %s %+v", r.URL.Path, req)
+		}
+		reply := &remoteReply{Continue: true, ProxyHeader: map[string]string{"X-User": "u1"}}
+		if req.Request.Header.Get("Authorization") == "" {
+			reply = &remoteReply{Continue: false, StatusCode: 401, Body: []byte("unauthorized")}
+		}
+		json.NewEncoder(w).Encode(reply)
+	}))
+	defer srv.Close()
+
+	factory, err := newRemoteFactory("auth", &RemoteConfig{Endpoint: srv.URL, Phases: []string{PhaseAccess}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, _ := factory.Create("{}", "default", "", "s1", 1)
+	if obj.BeforeMatch != nil || obj.Proxy != nil || obj.Access == nil {
+		t.Fatalf("unexpected phases %+v", obj)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/a", nil)
+	r.Header.Set("Authorization", "token")
+	ctx := common.NewContext(r, "r1", httptest.NewRecorder())
+	if flag, err := obj.Access.Access(ctx); !flag || err != nil || ctx.ProxyRequest.GetHeader("X-User") != "u1" {
+		t.Errorf("expect continue with header, got %v %v", flag, err)
+	}
+
+	r.Header.Del("Authorization")
+	ctx = common.NewContext(r, "r2", httptest.NewRecorder())
+	if flag, _ := obj.Access.Access(ctx); flag || ctx.StatusCode() != 401 || string(ctx.GetBody()) != "unauthorized" {
+		t.Errorf("expect stop with 401, got %v %d", flag, ctx.StatusCode())
+	}
+}
+
+func TestRemoteFailPolicy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/a", nil)
+	for policy, expect := range map[string]bool{FailOpen: true, FailClosed: false} {
+		factory, err := newRemoteFactory("p", &RemoteConfig{Endpoint: srv.URL, CallConfig: CallConfig{FailPolicy: policy, FailStatus: 502}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj, _ := factory.Create("", "default", "", "", 0)
+		ctx := common.NewContext(r, "r1", httptest.NewRecorder())
+		flag, err := obj.BeforeMatch.BeforeMatch(ctx)
+		if flag != expect || err == nil {
+			t.Errorf("%s: expect %v with error, got %v %v", policy, expect, flag, err)
+		}
+		if !expect && ctx.StatusCode() != 502 {
+			t.Errorf("%s: expect status 502, got %d", policy, ctx.StatusCode())
+		}
+	}
+
+	if _, err := newRemoteFactory("p", &RemoteConfig{Endpoint: srv.URL, CallConfig: CallConfig{FailPolicy: "other"}}); err == nil {
+		t.Error("expect fail policy error")
+	}
+}
+
+func TestRemoteCancel(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	factory, err := newRemoteFactory("p", &RemoteConfig{Endpoint: srv.URL, Timeout: 5000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, _ := factory.Create("", "default", "", "", 0)
+	c, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	now := time.Now()
+	ctx := common.NewContext(httptest.NewRequest(http.MethodGet, "http://localhost/a", nil), "r1", httptest.NewRecorder())
+	if _, err := obj.Access.(Cancelable).CallPhase(c, PhaseAccess, ctx); err == nil || time.Since(now) > time.Second {
+		t.Errorf("expect call to be interrupted, got %v after %s", err, time.Since(now))
+	}
+}
+
+func TestLoadRemotePlugin(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goku-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(filepath.Join(dir, "plugin"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "plugin", "remote-ok.json"), []byte(`{"endpoint":"unix:///tmp/remote-ok.sock"}`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "plugin", "remote-bad.json"), []byte(`{"endpoint":"ftp://a"}`), 0644)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	if _, err := LoadPlugin("remote-ok"); err != nil {
+		t.Error(err)
+	}
+	if code, err := globalPluginManager.check("remote-bad"); code != LoadRemoteConfigError || err == nil {
+		t.Errorf("expect remote config error, got %d %v", code, err)
+	}
+}
@@ -0,0 +1,278 @@
+package plugin_loader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	goku_plugin "github.com/eolinker/goku-plugin"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+const (
+	defaultWasmMemoryPages = 256
+	defaultWasmTimeout     = 100
+	defaultWasmPoolSize    = 4
+
+	wasmHostModule  = "goku"
+	wasmMalloc      = "goku_malloc"
+	wasmFree        = "goku_free"
+	wasmPhasePrefix = "goku_"
+)
+
+//WasmConfig Wasm插件配置，保存在plugin/插件名.wasm.json，文件不存在时使用默认配置
+type WasmConfig struct {
+	CallConfig
+	//MemoryPages 每个实例可使用的内存上限，单位为页（64KB）
+	MemoryPages uint32 `json:"memoryPages"`
+	//Timeout 每次调用的执行时间上限，单位毫秒，超时的实例会被销毁
+	Timeout int `json:"timeout"`
+	//PoolSize 每份插件配置保留的空闲实例数
+	PoolSize int `json:"poolSize"`
+}
+
+func (c *WasmConfig) check() error {
+	if err := c.CallConfig.check(); err != nil {
+		return err
+	}
+	if c.MemoryPages == 0 {
+		c.MemoryPages = defaultWasmMemoryPages
+	}
+	if c.MemoryPages > 65536 {
+		return fmt.Errorf("illegal memoryPages %d, expect at most 65536", c.MemoryPages)
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultWasmTimeout
+	}
+	if c.PoolSize <= 0 {
+		c.PoolSize = defaultWasmPoolSize
+	}
+	return nil
+}
+
+//wasmFactory Wasm插件，插件通过Goku host ABI与网关交互：
+//插件导出内存、goku_malloc(size i32) i32，以及需要处理的阶段函数goku_before、goku_access、goku_proxy(ptr i32, len i32) i64；
+//网关将请求JSON写入goku_malloc分配的内存后调用阶段函数，返回值高32位为返回JSON的地址，低32位为长度，长度为0表示继续处理且不作修改；
+//插件可导出goku_free(ptr i32, len i32)释放内存，可导入goku.log(level i32, ptr i32, len i32)输出日志
+type wasmFactory struct {
+	name     string
+	config   *WasmConfig
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	phases   map[string]bool
+
+	locker sync.Mutex
+	pools  map[string]*wasmPool
+}
+
+func newWasmFactory(name string, binary []byte, config *WasmConfig) (*wasmFactory, error) {
+	if err := config.check(); err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(config.MemoryPages).
+		WithCloseOnContextDone(true))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, err
+	}
+	_, err := runtime.NewHostModuleBuilder(wasmHostModule).
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, level, ptr, size uint32) {
+		wasmLog(name, m, level, ptr, size)
+	}).Export("log").
+		Instantiate(ctx)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, err
+	}
+	compiled, err := runtime.CompileModule(ctx, binary)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, err
+	}
+	exports := compiled.ExportedFunctions()
+	if _, has := exports[wasmMalloc]; !has {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("function %s is not exported", wasmMalloc)
+	}
+	phases := make(map[string]bool)
+	for _, phase := range []string{PhaseBefore, PhaseAccess, PhaseProxy} {
+		if _, has := exports[wasmPhasePrefix+phase]; has {
+			phases[phase] = true
+		}
+	}
+	if len(phases) == 0 {
+		runtime.Close(ctx)
+		return nil, errors.New("none of goku_before, goku_access and goku_proxy is exported")
+	}
+	return &wasmFactory{
+		name:     name,
+		config:   config,
+		runtime:  runtime,
+		compiled: compiled,
+		phases:   phases,
+		pools:    make(map[string]*wasmPool),
+	}, nil
+}
+
+//Create 创建插件实例，相同插件配置的实例共用一个实例池
+func (f *wasmFactory) Create(config string, clusterName string, updateTag string, strategyID string, apiID int) (*goku_plugin.PluginObj, error) {
+	return newPluginObj(&remotePlugin{
+		name:   f.name,
+		config: &f.config.CallConfig,
+		caller: f.pool(config),
+		info: remoteRequest{
+			Plugin:     f.name,
+			Config:     config,
+			Cluster:    clusterName,
+			UpdateTag:  updateTag,
+			StrategyID: strategyID,
+			APIID:      apiID,
+		},
+	}, f.phases), nil
+}
+
+func (f *wasmFactory) pool(config string) *wasmPool {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	p, has := f.pools[config]
+	if !has {
+		p = &wasmPool{
+			factory: f,
+			idle:    make(chan api.Module, f.config.PoolSize),
+		}
+		f.pools[config] = p
+	}
+	return p
+}
+
+//wasmPool 空闲实例池，池为空时创建新实例，池满时销毁归还的实例
+type wasmPool struct {
+	factory *wasmFactory
+	idle    chan api.Module
+}
+
+func (p *wasmPool) get(ctx context.Context) (api.Module, error) {
+	select {
+	case m := <-p.idle:
+		return m, nil
+	default:
+	}
+	config := wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize")
+	return p.factory.runtime.InstantiateModule(ctx, p.factory.compiled, config)
+}
+
+func (p *wasmPool) put(m api.Module) {
+	select {
+	case p.idle <- m:
+	default:
+		m.Close(context.Background())
+	}
+}
+
+//call 从实例池取出实例执行阶段函数，执行出错或超时的实例不再放回
+func (p *wasmPool) call(c context.Context, req *remoteRequest) (*remoteReply, error) {
+	ctx, cancel := context.WithTimeout(c, time.Duration(p.factory.config.Timeout)*time.Millisecond)
+	defer cancel()
+	m, err := p.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := invokeWasm(ctx, m, req)
+	if err != nil || m.IsClosed() {
+		m.Close(context.Background())
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timeout after %dms", p.factory.config.Timeout)
+		}
+		return nil, err
+	}
+	p.put(m)
+	return reply, nil
+}
+
+func invokeWasm(ctx context.Context, m api.Module, req *remoteRequest) (*remoteReply, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	results, err := m.ExportedFunction(wasmMalloc).Call(ctx, uint64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	ptr := uint32(results[0])
+	if !m.Memory().Write(ptr, data) {
+		return nil, fmt.Errorf("write request out of memory range %d+%d", ptr, len(data))
+	}
+	results, err = m.ExportedFunction(wasmPhasePrefix+req.Phase).Call(ctx, uint64(ptr), uint64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	freeWasm(ctx, m, ptr, uint32(len(data)))
+
+	replyPtr, replySize := uint32(results[0]>>32), uint32(results[0])
+	reply := &remoteReply{Continue: true}
+	if replySize == 0 {
+		return reply, nil
+	}
+	body, ok := m.Memory().Read(replyPtr, replySize)
+	if !ok {
+		return nil, fmt.Errorf("read reply out of memory range %d+%d", replyPtr, replySize)
+	}
+	err = json.Unmarshal(body, reply)
+	freeWasm(ctx, m, replyPtr, replySize)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func freeWasm(ctx context.Context, m api.Module, ptr, size uint32) {
+	if free := m.ExportedFunction(wasmFree); free != nil {
+		free.Call(ctx, uint64(ptr), uint64(size))
+	}
+}
+
+//wasmLog 插件日志，level：0 debug、1 info、2 warn、3 error
+func wasmLog(name string, m api.Module, level, ptr, size uint32) {
+	data, ok := m.Memory().Read(ptr, size)
+	if !ok {
+		return
+	}
+	msg := fmt.Sprintf("[wasm plugin %s] %s", name, data)
+	switch level {
+	case 0:
+		log.Debug(msg)
+	case 1:
+		log.Info(msg)
+	case 2:
+		log.Warn(msg)
+	default:
+		log.Error(msg)
+	}
+}
+
+//loadWasmConfig 读取Wasm插件及其配置，插件文件不存在时返回false
+func loadWasmConfig(path string) ([]byte, *WasmConfig, bool, error) {
+	binary, has, err := readOptionalFile(path)
+	if !has || err != nil {
+		return nil, nil, has, err
+	}
+	c := new(WasmConfig)
+	data, has, err := readOptionalFile(path + ".json")
+	if err != nil {
+		return nil, nil, true, err
+	}
+	if has {
+		if err := json.Unmarshal(data, c); err != nil {
+			return nil, nil, true, err
+		}
+	}
+	return binary, c, true, nil
+}
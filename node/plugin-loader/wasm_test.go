@@ -0,0 +1,89 @@
+package plugin_loader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+const testWasmReply = `{"continue":true,"proxyHeader":{"X-Wasm":"1"}}`
+
+//testWasmModule 手工编码的Wasm模块：goku_malloc为递增分配，goku_access返回数据段中的testWasmReply，goku_before为死循环
+func testWasmModule() []byte {
+	section := func(id byte, items ...[]byte) []byte {
+		content := []byte{byte(len(items))}
+		for _, item := range items {
+			content = append(content, item...)
+		}
+		return append([]byte{id, byte(len(content))}, content...)
+	}
+	name := func(n string, kind, index byte) []byte {
+		return append(append([]byte{byte(len(n))}, n...), kind, index)
+	}
+	body := func(code ...byte) []byte {
+		return append([]byte{byte(len(code) + 1), 0x00}, code...)
+	}
+	data := append([]byte{0x00, 0x41, 0x10, 0x0b, byte(len(testWasmReply))}, testWasmReply...)
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, section(0x01,
+		[]byte{0x60, 0x01, 0x7f, 0x01, 0x7f},
+		[]byte{0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e})...)
+	module = append(module, section(0x03, []byte{0x00}, []byte{0x01}, []byte{0x01})...)
+	module = append(module, section(0x05, []byte{0x00, 0x01})...)
+	module = append(module, section(0x06, []byte{0x7f, 0x01, 0x41, 0x80, 0x08, 0x0b})...)
+	module = append(module, section(0x07,
+		name("memory", 0x02, 0),
+		name(wasmMalloc, 0x00, 0),
+		name("goku_access", 0x00, 1),
+		name("goku_before", 0x00, 2))...)
+	module = append(module, section(0x0a,
+		body(0x23, 0x00, 0x23, 0x00, 0x20, 0x00, 0x6a, 0x24, 0x00, 0x0b),
+		body(0x42, 0x10, 0x42, 0x20, 0x86, 0x42, byte(len(testWasmReply)), 0x84, 0x0b),
+		body(0x03, 0x40, 0x0c, 0x00, 0x0b, 0x42, 0x00, 0x0b))...)
+	module = append(module, section(0x0b, data)...)
+	return module
+}
+
+func TestWasmPlugin(t *testing.T) {
+	factory, err := newWasmFactory("wasm", testWasmModule(), &WasmConfig{
+		CallConfig: CallConfig{FailPolicy: FailClosed},
+		Timeout:    50,
+		PoolSize:   1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, _ := factory.Create("{}", "default", "", "s1", 1)
+	if obj.BeforeMatch == nil || obj.Access == nil || obj.Proxy != nil {
+		t.Fatalf("unexpected phases %+v", obj)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/a", nil)
+	for i := 0; i < 3; i++ {
+		ctx := common.NewContext(r, "r1", httptest.NewRecorder())
+		if flag, err := obj.Access.Access(ctx); !flag || err != nil || ctx.ProxyRequest.GetHeader("X-Wasm") != "1" {
+			t.Fatalf("expect continue with header, got %v %v", flag, err)
+		}
+	}
+
+	ctx := common.NewContext(r, "r2", httptest.NewRecorder())
+	if flag, err := obj.BeforeMatch.BeforeMatch(ctx); flag || err == nil || ctx.StatusCode() != defaultFailStatus {
+		t.Errorf("expect timeout to stop the request, got %v %v %d", flag, err, ctx.StatusCode())
+	}
+
+	// 超时的实例已销毁，后续调用使用新实例
+	ctx = common.NewContext(r, "r3", httptest.NewRecorder())
+	if flag, err := obj.Access.Access(ctx); !flag || err != nil {
+		t.Errorf("expect continue after timeout, got %v %v", flag, err)
+	}
+
+	if len(factory.pools) != 1 {
+		t.Errorf("expect one pool per config, got %d", len(factory.pools))
+	}
+	if _, err := newWasmFactory("bad", []byte("not wasm"), &WasmConfig{}); err == nil {
+		t.Error("expect compile error")
+	}
+}
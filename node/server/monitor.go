@@ -0,0 +1,41 @@
+package server
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+//setMonitor 应用监控配置
+func (s *Server) setMonitor(c *config.MonitorConfig) {
+	period := monitor.DefaultPeriod
+	var successCode []int
+	if c != nil {
+		if c.Period > 0 {
+			period = time.Duration(c.Period) * time.Second
+		}
+		successCode = c.SuccessCode
+	}
+	monitor.SetSuccessCode(successCode)
+	atomic.StoreInt64(&s.monitorPeriod, int64(period))
+}
+
+//reportMonitor 定时向控制台上报监控计数，上报失败时保留计数等待下次上报
+func (s *Server) reportMonitor() {
+	for {
+		time.Sleep(time.Duration(atomic.LoadInt64(&s.monitorPeriod)))
+		items := monitor.Collect()
+		plugins := monitor.CollectPlugins()
+		if len(items) == 0 && len(plugins) == 0 {
+			continue
+		}
+		if err := s.console.ReportMonitor(items, plugins); err != nil {
+			log.Warn("report monitor error:", err)
+			monitor.Restore(items)
+			monitor.RestorePlugins(plugins)
+		}
+	}
+}
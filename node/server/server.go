@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/eolinker/goku-api-gateway/common/endless"
 	"github.com/eolinker/goku-api-gateway/config"
@@ -17,7 +18,10 @@ import (
 type Server struct {
 	port    int
 	console *console.Console
-	router  http.Handler
+	router  atomic.Value
+	gateway *gateway.Gateway
+
+	monitorPeriod int64
 }
 
 //NewServer newServer
@@ -25,16 +29,28 @@ func NewServer(port int) *Server {
 	return &Server{
 		port:    port,
 		console: nil,
-		router:  nil,
 	}
 }
 
-//SetRouter setRouter
+//SetRouter 设置处理器，可与请求处理并发调用
 func (s *Server) SetRouter(r http.Handler) error {
-	s.router = r
+	if r == nil {
+		return errors.New("router is nil")
+	}
+	s.router.Store(routerHolder{handler: r})
 	return nil
 }
 
+//routerHolder atomic.Value要求每次存储的类型一致
+type routerHolder struct {
+	handler http.Handler
+}
+
+func (s *Server) getRouter() http.Handler {
+	r, _ := s.router.Load().(routerHolder)
+	return r.handler
+}
+
 //SetConsole setConsole
 func (s *Server) SetConsole(c *console.Console) {
 
@@ -44,7 +60,7 @@ func (s *Server) SetConsole(c *console.Console) {
 
 //Server server
 func (s *Server) Server() error {
-	if s.router == nil && s.console == nil {
+	if s.getRouter() == nil && s.console == nil {
 		return errors.New("can not start server widthout router and console")
 	}
 
@@ -56,44 +72,60 @@ func (s *Server) Server() error {
 		}
 		SetLog(conf.Log)
 		SetAccessLog(conf.AccessLog)
+		s.setMonitor(conf.Monitor)
 
-		r, err := gateway.Parse(conf, httprouter.Factory())
+		s.gateway = gateway.NewGateway(httprouter.Factory())
+		plugins, err := s.gateway.Apply(conf)
+		s.report(conf, plugins, err)
 		if err != nil {
 			log.Panic("parse config error:", err)
 		}
-		e := s.SetRouter(r)
+		e := s.SetRouter(s.gateway)
 		if e != nil {
 			return e
 		}
 
 		s.console.AddListen(s.FlushConfig)
+		go s.reportMonitor()
 	}
 
 	return endless.ListenAndServe(fmt.Sprintf(":%d", s.port), s)
 }
 
-//FlushConfig flushConfig
+//FlushConfig 更新配置，复用未变化的插件对象及应用；新配置无效时继续使用当前配置并上报错误。
+//控制台的配置轮询协程依次调用，在调用方协程中应用以保证按下发顺序生效，较早的配置不会覆盖较新的配置
 func (s *Server) FlushConfig(config *config.GokuConfig) {
+	plugins, err := s.gateway.Apply(config)
+	s.report(config, plugins, err)
+	if err != nil {
+		log.Error("parse config error:", err)
+		return
+	}
+	s.setMonitor(config.Monitor)
+}
 
-	go func() {
-		r, err := gateway.Parse(config, httprouter.Factory())
-		if err != nil {
-			log.Error("parse config error:", err)
-			return
-		}
-		s.SetRouter(r)
-
-	}()
-
+// report 向控制台上报配置的应用结果及该配置使用的插件的加载状态
+func (s *Server) report(conf *config.GokuConfig, plugins []*config.PluginLoadStatus, err error) {
+	r := &config.ApplyReport{
+		Version: conf.Version,
+		Status:  config.ApplyOk,
+		Plugins: plugins,
+	}
+	if err != nil {
+		r.Status = config.ApplyFailed
+		r.Error = err.Error()
+	}
+	go s.console.Report(r)
 }
 
 func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
-	if s.router == nil {
+	r := s.getRouter()
+	if r == nil {
 		w.WriteHeader(404)
 		return
 	}
 
-	s.router.ServeHTTP(w, req)
+	r.ServeHTTP(w, req)
 
 }
@@ -46,6 +46,12 @@ const (
 	ProxyStatusCode = "$proxy_status_code"
 	//Host 主机信息
 	Host = "$host"
+	//IPAccess IP黑白名单拦截信息
+	IPAccess = "$ip_access"
+	//PluginTime 插件执行耗时，格式为 插件名:耗时，多个插件以逗号分隔
+	PluginTime = "$plugin_time"
+	//PluginError 插件执行错误，格式为 插件名:错误，多个插件以逗号分隔
+	PluginError = "$plugin_error"
 )
 
 //Info 获取域信息
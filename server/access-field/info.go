@@ -22,5 +22,8 @@ var (
 		Proxy:             "记录转发的方法、URL和协议（例如 POST /proxy HTTPS)",
 		ProxyStatusCode:   "转发状态码",
 		Host:              "主机信息",
+		IPAccess:          "IP黑白名单拦截信息（strategy/api）",
+		PluginTime:        "插件执行耗时，格式为 插件名:耗时，多个插件以逗号分隔",
+		PluginError:       "插件执行错误（error/panic/timeout），格式为 插件名:错误，多个插件以逗号分隔",
 	}
 )
@@ -22,6 +22,7 @@ var (
 		BodyBytesSent,
 		HTTPReferer,
 		HTTPUserAgent,
+		IPAccess,
 	}
 	size = len(all)
 )
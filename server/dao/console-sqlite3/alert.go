@@ -0,0 +1,168 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const alertRuleColumns = "R.`ruleID`,R.`ruleName`,R.`ruleType`,R.`clusterID`,IFNULL(C.`name`,''),R.`strategyID`,R.`apiID`,R.`threshold`,R.`period`,R.`cooldown`,R.`receivers`,R.`webhookURL`,R.`webhookTemplate`,R.`enable`,R.`createTime`,R.`updateTime`"
+
+const alertRuleFrom = " FROM goku_alert_rule R LEFT JOIN goku_cluster C ON R.`clusterID` = C.`id`"
+
+//AddAlertRule 新增告警规则
+func AddAlertRule(rule *entity.AlertRule) (int, error) {
+	db := database.GetConnection()
+	sql := "INSERT INTO goku_alert_rule (`ruleName`,`ruleType`,`clusterID`,`strategyID`,`apiID`,`threshold`,`period`,`cooldown`,`receivers`,`webhookURL`,`webhookTemplate`,`enable`,`createTime`,`updateTime`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?);"
+	result, err := db.Exec(sql, rule.RuleName, rule.RuleType, rule.ClusterID, rule.StrategyID, rule.APIID, rule.Threshold, rule.Period, rule.Cooldown, rule.Receivers, rule.WebhookURL, rule.WebhookTemplate, enableValue(rule.Enable), rule.CreateTime, rule.UpdateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+//EditAlertRule 编辑告警规则
+func EditAlertRule(rule *entity.AlertRule) (bool, error) {
+	db := database.GetConnection()
+	sql := "UPDATE goku_alert_rule SET `ruleName` = ?,`ruleType` = ?,`clusterID` = ?,`strategyID` = ?,`apiID` = ?,`threshold` = ?,`period` = ?,`cooldown` = ?,`receivers` = ?,`webhookURL` = ?,`webhookTemplate` = ?,`enable` = ?,`updateTime` = ? WHERE `ruleID` = ?;"
+	result, err := db.Exec(sql, rule.RuleName, rule.RuleType, rule.ClusterID, rule.StrategyID, rule.APIID, rule.Threshold, rule.Period, rule.Cooldown, rule.Receivers, rule.WebhookURL, rule.WebhookTemplate, enableValue(rule.Enable), rule.UpdateTime, rule.RuleID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+//DeleteAlertRule 删除告警规则，告警记录保留
+func DeleteAlertRule(ruleID int) error {
+	db := database.GetConnection()
+	_, err := db.Exec("DELETE FROM goku_alert_rule WHERE `ruleID` = ?;", ruleID)
+	return err
+}
+
+//GetAlertRule 获取告警规则
+func GetAlertRule(ruleID int) (bool, *entity.AlertRule, error) {
+	db := database.GetConnection()
+	rule, err := scanAlertRule(db.QueryRow("SELECT "+alertRuleColumns+alertRuleFrom+" WHERE R.`ruleID` = ?;", ruleID))
+	if err == SQL.ErrNoRows {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	return true, rule, nil
+}
+
+//GetAlertRuleList 获取告警规则列表，onlyEnable为true时只获取启用的规则
+func GetAlertRuleList(onlyEnable bool) ([]*entity.AlertRule, error) {
+	db := database.GetConnection()
+	sql := "SELECT " + alertRuleColumns + alertRuleFrom
+	if onlyEnable {
+		sql += " WHERE R.`enable` = 1"
+	}
+	rows, err := db.Query(sql + " ORDER BY R.`ruleID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rules := make([]*entity.AlertRule, 0)
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func enableValue(enable bool) int {
+	if enable {
+		return 1
+	}
+	return 0
+}
+
+func scanAlertRule(row rowScanner) (*entity.AlertRule, error) {
+	rule := new(entity.AlertRule)
+	enable := 0
+	err := row.Scan(&rule.RuleID, &rule.RuleName, &rule.RuleType, &rule.ClusterID, &rule.ClusterName, &rule.StrategyID, &rule.APIID, &rule.Threshold, &rule.Period, &rule.Cooldown, &rule.Receivers, &rule.WebhookURL, &rule.WebhookTemplate, &enable, &rule.CreateTime, &rule.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	rule.Enable = enable == 1
+	return rule, nil
+}
+
+//AddAlertHistory 新增告警记录
+func AddAlertHistory(h *entity.AlertHistory) error {
+	db := database.GetConnection()
+	sql := "INSERT INTO goku_alert_history (`ruleID`,`ruleName`,`ruleType`,`target`,`value`,`threshold`,`message`,`notifyStatus`,`notifyError`,`alertTime`) VALUES (?,?,?,?,?,?,?,?,?,?);"
+	_, err := db.Exec(sql, h.RuleID, h.RuleName, h.RuleType, h.Target, h.Value, h.Threshold, h.Message, h.NotifyStatus, h.NotifyError, h.AlertTime)
+	return err
+}
+
+//GetLastAlertTime 获取规则对某个目标最近一次告警的时间，没有记录时返回空
+func GetLastAlertTime(ruleID int, target string) (string, error) {
+	db := database.GetConnection()
+	var alertTime string
+	err := db.QueryRow("SELECT IFNULL(MAX(`alertTime`),'') FROM goku_alert_history WHERE `ruleID` = ? AND `target` = ?;", ruleID, target).Scan(&alertTime)
+	return alertTime, err
+}
+
+//GetAlertHistoryList 获取告警记录列表
+func GetAlertHistoryList(cond *entity.AlertHistoryCondition, page, pageSize int) ([]*entity.AlertHistory, int, error) {
+	sql := "SELECT `alertID`,`ruleID`,`ruleName`,`ruleType`,`target`,`value`,`threshold`,`message`,`notifyStatus`,`notifyError`,`alertTime` FROM goku_alert_history"
+	where := make([]string, 0, 4)
+	args := make([]interface{}, 0, 4)
+	if cond.RuleID > 0 {
+		where = append(where, "`ruleID` = ?")
+		args = append(args, cond.RuleID)
+	}
+	if cond.RuleType != "" {
+		where = append(where, "`ruleType` = ?")
+		args = append(args, cond.RuleType)
+	}
+	if cond.BeginTime != "" {
+		where = append(where, "`alertTime` >= ?")
+		args = append(args, cond.BeginTime)
+	}
+	if cond.EndTime != "" {
+		where = append(where, "`alertTime` <= ?")
+		args = append(args, cond.EndTime)
+	}
+	if len(where) > 0 {
+		sql += " WHERE " + strings.Join(where, " AND ")
+	}
+	count := getCountSQL(sql, args...)
+	rows, err := getPageSQL(sql, "`alertID`", "DESC", page, pageSize, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	list := make([]*entity.AlertHistory, 0)
+	for rows.Next() {
+		h := new(entity.AlertHistory)
+		err = rows.Scan(&h.AlertID, &h.RuleID, &h.RuleName, &h.RuleType, &h.Target, &h.Value, &h.Threshold, &h.Message, &h.NotifyStatus, &h.NotifyError, &h.AlertTime)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, h)
+	}
+	return list, count, rows.Err()
+}
+
+//GetAlertSender 获取告警邮件发送配置
+func GetAlertSender() (*entity.AlertSender, error) {
+	db := database.GetConnection()
+	s := new(entity.AlertSender)
+	sql := "SELECT `alertStatus`,IFNULL(`sender`,''),IFNULL(`senderPassword`,''),IFNULL(`smtpAddress`,''),`smtpPort`,`smtpProtocol` FROM goku_gateway WHERE `id` = 1;"
+	err := db.QueryRow(sql).Scan(&s.AlertStatus, &s.Sender, &s.SenderPassword, &s.SMTPAddress, &s.SMTPPort, &s.SMTPProtocol)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -345,42 +345,35 @@ func CheckAPIIsExist(apiID int) (bool, error) {
 
 //BatchEditAPIBalance 批量修改接口负载
 func BatchEditAPIBalance(apiIDList []string, balance string) (string, error) {
-	db := database2.GetConnection()
-	now := time.Now().Format("2006-01-02 15:04:05")
-
-	sqlTpl := "UPDATE `goku_gateway_api` A LEFT JOIN `goku_gateway_project` P ON A.`projectID` = P.`projectID` SET A.`updateTime` = ?,  P.`updateTime`=?, A.`balanceName`=? WHERE A.`apiID` IN (%s)"
-
-	sql := fmt.Sprint(sqlTpl, strings.Join(apiIDList, ","))
-
-	stmt, err := db.Prepare(sql)
-	if err != nil {
-		return "[ERROR]Fail to Prepare SQL!", err
-	}
-	_, err = stmt.Exec(now, now, balance)
-	if err != nil {
-		return "[ERROR]Fail to excute SQL statement!", err
-	}
-	return "", nil
+	return batchEditAPI(apiIDList, "balanceName", balance)
 }
 
 //BatchEditAPIGroup 批量修改接口分组
 func BatchEditAPIGroup(apiIDList []string, groupID int) (string, error) {
+	return batchEditAPI(apiIDList, "groupID", groupID)
+}
+
+//batchEditAPI 批量修改接口字段并更新所属项目的更新时间
+func batchEditAPI(apiIDList []string, field string, value interface{}) (string, error) {
 	db := database2.GetConnection()
 	now := time.Now().Format("2006-01-02 15:04:05")
+	ids := strings.Join(apiIDList, ",")
 
-	sqlTpl := "UPDATE `goku_gateway_api` A LEFT JOIN `goku_gateway_project` P ON A.`projectID` = P.`projectID` SET A.`updateTime` = ?,  P.`updateTime`=?, A.`groupID`=? WHERE A.`apiID` IN (%s)"
-
-	sql := fmt.Sprintf(sqlTpl, strings.Join(apiIDList, ","))
-	stmt, err := db.Prepare(sql)
+	Tx, _ := db.Begin()
+	sql := fmt.Sprintf("UPDATE `goku_gateway_project` SET `updateTime` = ? WHERE `projectID` IN (SELECT `projectID` FROM `goku_gateway_api` WHERE `apiID` IN (%s))", ids)
+	_, err := Tx.Exec(sql, now)
 	if err != nil {
-		return "[ERROR]Fail to Prepare SQL!", err
+		Tx.Rollback()
+		return "[ERROR]Fail to excute SQL statement!", err
 	}
-	_, err = stmt.Exec(now, now, groupID)
+	sql = fmt.Sprintf("UPDATE `goku_gateway_api` SET `updateTime` = ?, `%s` = ? WHERE `apiID` IN (%s)", field, ids)
+	_, err = Tx.Exec(sql, now, value)
 	if err != nil {
+		Tx.Rollback()
 		return "[ERROR]Fail to excute SQL statement!", err
 	}
+	Tx.Commit()
 	return "", nil
-
 }
 
 //BatchDeleteAPI 批量修改接口
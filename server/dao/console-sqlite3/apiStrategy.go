@@ -74,6 +74,38 @@ func SetAPITargetOfStrategy(apiID int, strategyID string, target string) (bool,
 	return true, "", nil
 }
 
+// SetAPICanaryOfStrategy 设置接口灰度配置
+func SetAPICanaryOfStrategy(apiID int, strategyID string, canary string) (bool, string, error) {
+	db := database2.GetConnection()
+	sql := "UPDATE goku_conn_strategy_api SET `canary` = ?,`updateTime` = ? where apiID = ? AND strategyID = ? "
+	stmt, err := db.Prepare(sql)
+	if err != nil {
+		return false, err.Error(), err
+	}
+	defer stmt.Close()
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+	_, e := stmt.Exec(canary, now, apiID, strategyID)
+
+	if e != nil {
+		return false, e.Error(), e
+	}
+
+	return true, "", nil
+}
+
+// GetAPICanaryOfStrategy 获取接口灰度配置
+func GetAPICanaryOfStrategy(apiID int, strategyID string) (bool, string, error) {
+	db := database2.GetConnection()
+	sql := "SELECT IFNULL(`canary`,'') FROM goku_conn_strategy_api WHERE apiID = ? AND strategyID = ?"
+	var canary string
+	err := db.QueryRow(sql, apiID, strategyID).Scan(&canary)
+	if err != nil {
+		return false, "", err
+	}
+	return true, canary, nil
+}
+
 // BatchSetAPITargetOfStrategy 批量重定向接口负载
 func BatchSetAPITargetOfStrategy(apiIds []int, strategyID string, target string) (bool, string, error) {
 	idLen := len(apiIds)
@@ -0,0 +1,106 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const apiTokenColumns = "`tokenID`,`tokenName`,`tokenType`,`userID`,`scopes`,`expireTime`,`lastUsedTime`,`createTime`"
+
+//AddAPIToken 新增接口访问令牌，只保存令牌的摘要
+func AddAPIToken(token *entity.APIToken, tokenHash string) (int, error) {
+	db := database.GetConnection()
+	scopes, err := json.Marshal(token.Scopes)
+	if err != nil {
+		return 0, err
+	}
+	sql := "INSERT INTO goku_api_token (`tokenName`,`tokenHash`,`tokenType`,`userID`,`scopes`,`expireTime`,`createTime`) VALUES (?,?,?,?,?,?,?);"
+	result, err := db.Exec(sql, token.TokenName, tokenHash, token.TokenType, token.UserID, string(scopes), token.ExpireTime, token.CreateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+//GetAPITokenByHash 根据令牌摘要获取令牌
+func GetAPITokenByHash(tokenHash string) (bool, *entity.APIToken, error) {
+	db := database.GetConnection()
+	sql := "SELECT " + apiTokenColumns + " FROM goku_api_token WHERE `tokenHash` = ?;"
+	token, err := scanAPIToken(db.QueryRow(sql, tokenHash))
+	if err == SQL.ErrNoRows {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	return true, token, nil
+}
+
+//GetAPITokenList 获取令牌列表，userID为0时获取全部
+func GetAPITokenList(userID int) ([]*entity.APIToken, error) {
+	db := database.GetConnection()
+	sql := "SELECT " + apiTokenColumns + " FROM goku_api_token"
+	args := make([]interface{}, 0, 1)
+	if userID > 0 {
+		sql += " WHERE `userID` = ?"
+		args = append(args, userID)
+	}
+	rows, err := db.Query(sql+" ORDER BY `tokenID` DESC;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tokens := make([]*entity.APIToken, 0)
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+//DeleteAPIToken 删除令牌，userID为0时不限制令牌所属用户
+func DeleteAPIToken(tokenID, userID int) (bool, error) {
+	db := database.GetConnection()
+	sql := "DELETE FROM goku_api_token WHERE `tokenID` = ?"
+	args := []interface{}{tokenID}
+	if userID > 0 {
+		sql += " AND `userID` = ?"
+		args = append(args, userID)
+	}
+	result, err := db.Exec(sql+";", args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+//SetAPITokenUsedTime 更新令牌最近使用时间
+func SetAPITokenUsedTime(tokenID int, now string) error {
+	db := database.GetConnection()
+	_, err := db.Exec("UPDATE goku_api_token SET `lastUsedTime` = ? WHERE `tokenID` = ?;", now, tokenID)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIToken(row rowScanner) (*entity.APIToken, error) {
+	token := new(entity.APIToken)
+	var scopes string
+	err := row.Scan(&token.TokenID, &token.TokenName, &token.TokenType, &token.UserID, &scopes, &token.ExpireTime, &token.LastUsedTime, &token.CreateTime)
+	if err != nil {
+		return nil, err
+	}
+	token.Scopes = make([]string, 0)
+	json.Unmarshal([]byte(scopes), &token.Scopes)
+	return token, nil
+}
@@ -0,0 +1,78 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//AddAuditLog 新增审计日志
+func AddAuditLog(l *entity.AuditLog) error {
+	db := database.GetConnection()
+	sql := "INSERT INTO goku_audit_log (`userID`,`remoteAddr`,`forwardedFor`,`method`,`operation`,`targetType`,`targetID`,`requestData`,`beforeData`,`afterData`,`statusCode`,`resultDesc`,`operateTime`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?);"
+	_, err := db.Exec(sql, l.UserID, l.RemoteAddr, l.ForwardedFor, l.Method, l.Operation, l.TargetType, l.TargetID, l.RequestData, l.BeforeData, l.AfterData, l.StatusCode, l.ResultDesc, l.OperateTime)
+	return err
+}
+
+//GetAuditLogList 获取审计日志列表，pageSize为0时不分页
+func GetAuditLogList(cond *entity.AuditLogCondition, page, pageSize int) ([]*entity.AuditLog, int, error) {
+	sql := "SELECT L.`logID`,L.`userID`,IFNULL(A.`loginCall`,''),L.`remoteAddr`,L.`forwardedFor`,L.`method`,L.`operation`,L.`targetType`,L.`targetID`,IFNULL(L.`requestData`,''),IFNULL(L.`beforeData`,''),IFNULL(L.`afterData`,''),L.`statusCode`,IFNULL(L.`resultDesc`,''),L.`operateTime` FROM goku_audit_log L LEFT JOIN goku_admin A ON L.`userID` = A.`userID`"
+	where := make([]string, 0, 7)
+	args := make([]interface{}, 0, 8)
+	if cond.UserID > 0 {
+		where = append(where, "L.`userID` = ?")
+		args = append(args, cond.UserID)
+	}
+	if cond.TargetType != "" {
+		where = append(where, "L.`targetType` = ?")
+		args = append(args, cond.TargetType)
+	}
+	if cond.TargetID != "" {
+		where = append(where, "L.`targetID` = ?")
+		args = append(args, cond.TargetID)
+	}
+	if cond.Keyword != "" {
+		where = append(where, "(L.`operation` LIKE ? OR L.`requestData` LIKE ? OR A.`loginCall` LIKE ?)")
+		keyword := "%" + cond.Keyword + "%"
+		args = append(args, keyword, keyword, keyword)
+	}
+	if cond.RemoteAddr != "" {
+		where = append(where, "L.`remoteAddr` = ?")
+		args = append(args, cond.RemoteAddr)
+	}
+	if cond.BeginTime != "" {
+		where = append(where, "L.`operateTime` >= ?")
+		args = append(args, cond.BeginTime)
+	}
+	if cond.EndTime != "" {
+		where = append(where, "L.`operateTime` <= ?")
+		args = append(args, cond.EndTime)
+	}
+	if len(where) > 0 {
+		sql += " WHERE " + strings.Join(where, " AND ")
+	}
+	count := getCountSQL(sql, args...)
+	var rows *SQL.Rows
+	var err error
+	if pageSize > 0 {
+		rows, err = getPageSQL(sql, "L.`logID`", "DESC", page, pageSize, args...)
+	} else {
+		rows, err = database.GetConnection().Query(sql+" ORDER BY L.`logID` DESC;", args...)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	logs := make([]*entity.AuditLog, 0)
+	for rows.Next() {
+		l := new(entity.AuditLog)
+		err = rows.Scan(&l.LogID, &l.UserID, &l.LoginCall, &l.RemoteAddr, &l.ForwardedFor, &l.Method, &l.Operation, &l.TargetType, &l.TargetID, &l.RequestData, &l.BeforeData, &l.AfterData, &l.StatusCode, &l.ResultDesc, &l.OperateTime)
+		if err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, count, rows.Err()
+}
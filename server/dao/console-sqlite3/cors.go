@@ -0,0 +1,55 @@
+package console_sqlite3
+
+import (
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+)
+
+//SetStrategyCORS 设置策略跨域配置
+func SetStrategyCORS(strategyID, cors string) (bool, string, error) {
+	db := database.GetConnection()
+	now := time.Now().Format("2006-01-02 15:04:05")
+	sql := "UPDATE goku_gateway_strategy SET `cors` = ?,`updateTime` = ? WHERE strategyID = ?"
+	_, err := db.Exec(sql, cors, now, strategyID)
+	if err != nil {
+		return false, "[ERROR]Failed to update data!", err
+	}
+	return true, "", nil
+}
+
+//GetStrategyCORS 获取策略跨域配置
+func GetStrategyCORS(strategyID string) (bool, string, error) {
+	db := database.GetConnection()
+	sql := "SELECT IFNULL(`cors`,'') FROM goku_gateway_strategy WHERE strategyID = ?"
+	var cors string
+	err := db.QueryRow(sql, strategyID).Scan(&cors)
+	if err != nil {
+		return false, "", err
+	}
+	return true, cors, nil
+}
+
+//SetAPICORS 设置接口跨域配置
+func SetAPICORS(apiID int, cors string) (bool, string, error) {
+	db := database.GetConnection()
+	now := time.Now().Format("2006-01-02 15:04:05")
+	sql := "UPDATE goku_gateway_api SET `cors` = ?,`updateTime` = ? WHERE apiID = ?"
+	_, err := db.Exec(sql, cors, now, apiID)
+	if err != nil {
+		return false, "[ERROR]Failed to update data!", err
+	}
+	return true, "", nil
+}
+
+//GetAPICORS 获取接口跨域配置
+func GetAPICORS(apiID int) (bool, string, error) {
+	db := database.GetConnection()
+	sql := "SELECT IFNULL(`cors`,'') FROM goku_gateway_api WHERE apiID = ?"
+	var cors string
+	err := db.QueryRow(sql, apiID).Scan(&cors)
+	if err != nil {
+		return false, "", err
+	}
+	return true, cors, nil
+}
@@ -11,7 +11,7 @@ import (
 //GetAPIContent 获取接口信息
 func GetAPIContent() ([]*config.APIContent, error) {
 	db := database.GetConnection()
-	sql := "SELECT apiID,apiName,IFNULL(protocol,'http'),IFNULL(balanceName,''),IFNULL(targetURL,''),CASE WHEN isFollow = 'true' THEN 'FOLLOW' ELSE targetMethod END targetMethod,responseDataType,requestURL,requestMethod,timeout,alertValve,retryCount,IFNULL(linkApis,''),IFNULL(staticResponse,'') FROM goku_gateway_api"
+	sql := "SELECT apiID,apiName,IFNULL(protocol,'http'),IFNULL(balanceName,''),IFNULL(targetURL,''),CASE WHEN isFollow = 'true' THEN 'FOLLOW' ELSE targetMethod END targetMethod,responseDataType,requestURL,requestMethod,timeout,alertValve,retryCount,IFNULL(linkApis,''),IFNULL(staticResponse,''),IFNULL(cors,''),IFNULL(ipAccess,''),IFNULL(transform,''),IFNULL(mock,'') FROM goku_gateway_api"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -21,13 +21,37 @@ func GetAPIContent() ([]*config.APIContent, error) {
 	defer rows.Close()
 	for rows.Next() {
 		var apiContent config.APIContent
-		var linkApisStr, protocol, balance, targetURL, targetMethod, requestMethod string
+		var linkApisStr, protocol, balance, targetURL, targetMethod, requestMethod, cors, ipAccess, transform, mock string
 		var retryCount int
 		linkApis := make([]config.APIStepUIConfig, 0)
-		err = rows.Scan(&apiContent.ID, &apiContent.Name, &protocol, &balance, &targetURL, &targetMethod, &apiContent.OutPutEncoder, &apiContent.RequestURL, &requestMethod, &apiContent.TimeOutTotal, &apiContent.AlertThreshold, &retryCount, &linkApisStr, &apiContent.StaticResponse)
+		err = rows.Scan(&apiContent.ID, &apiContent.Name, &protocol, &balance, &targetURL, &targetMethod, &apiContent.OutPutEncoder, &apiContent.RequestURL, &requestMethod, &apiContent.TimeOutTotal, &apiContent.AlertThreshold, &retryCount, &linkApisStr, &apiContent.StaticResponse, &cors, &ipAccess, &transform, &mock)
 		if err != nil {
 			return nil, err
 		}
+		if cors != "" {
+			corsConfig := new(config.CORSConfig)
+			if err := json.Unmarshal([]byte(cors), corsConfig); err == nil {
+				apiContent.CORS = corsConfig
+			}
+		}
+		if ipAccess != "" {
+			ipAccessConfig := new(config.IPAccessConfig)
+			if err := json.Unmarshal([]byte(ipAccess), ipAccessConfig); err == nil {
+				apiContent.IPAccess = ipAccessConfig
+			}
+		}
+		if transform != "" {
+			transformConfig := new(config.TransformConfig)
+			if err := json.Unmarshal([]byte(transform), transformConfig); err == nil {
+				apiContent.Transform = transformConfig
+			}
+		}
+		if mock != "" {
+			mockConfig := new(config.MockConfig)
+			if err := json.Unmarshal([]byte(mock), mockConfig); err == nil {
+				apiContent.Mock = mockConfig
+			}
+		}
 		if linkApisStr != "" {
 			err = json.Unmarshal([]byte(linkApisStr), &linkApis)
 			if err != nil {
@@ -0,0 +1,45 @@
+package dao_version_config
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+//GetTrustedProxies 获取可信代理列表
+func GetTrustedProxies() ([]string, error) {
+	db := database.GetConnection()
+	sql := "SELECT IFNULL(`trustedProxies`,'') FROM goku_gateway WHERE id = 1;"
+	var trustedProxies string
+	err := db.QueryRow(sql).Scan(&trustedProxies)
+	if err != nil || trustedProxies == "" {
+		return nil, err
+	}
+	proxies := make([]string, 0)
+	err = json.Unmarshal([]byte(trustedProxies), &proxies)
+	if err != nil {
+		return nil, err
+	}
+	return proxies, nil
+}
+
+//GetMonitorConfig 获取监控上报周期及成功状态码
+func GetMonitorConfig() (*config.MonitorConfig, error) {
+	db := database.GetConnection()
+	sql := "SELECT IFNULL(`successCode`,''),`monitorUpdatePeriod` FROM goku_gateway WHERE id = 1;"
+	var successCode string
+	monitor := &config.MonitorConfig{SuccessCode: make([]int, 0)}
+	err := db.QueryRow(sql).Scan(&successCode, &monitor.Period)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range strings.Split(successCode, ",") {
+		if c, err := strconv.Atoi(strings.TrimSpace(code)); err == nil {
+			monitor.SuccessCode = append(monitor.SuccessCode, c)
+		}
+	}
+	return monitor, nil
+}
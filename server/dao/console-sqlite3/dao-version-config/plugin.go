@@ -10,7 +10,7 @@ import (
 //GetGlobalPlugin 获取全局插件
 func GetGlobalPlugin() (*config.GatewayPluginConfig, error) {
 	db := database.GetConnection()
-	sql := "SELECT pluginName,isStop,IFNULL(pluginConfig,''),pluginType FROM goku_plugin"
+	sql := "SELECT pluginName,isStop,IFNULL(pluginConfig,''),pluginType,pluginTimeout,failPolicy,failStatus,failBody FROM goku_plugin"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -24,22 +24,16 @@ func GetGlobalPlugin() (*config.GatewayPluginConfig, error) {
 		var pluginName, pluginConfig string
 		var isStop bool
 		var pluginType int
-		err = rows.Scan(&pluginName, &isStop, &pluginConfig, &pluginType)
+		cfg := new(config.PluginConfig)
+		err = rows.Scan(&pluginName, &isStop, &pluginConfig, &pluginType, &cfg.Timeout, &cfg.FailPolicy, &cfg.FailStatus, &cfg.FailBody)
 		if err != nil {
 			return nil, err
 		}
+		cfg.Name, cfg.IsStop, cfg.Config = pluginName, isStop, pluginConfig
 		if pluginType == 0 {
-			pluginConfigs.GlobalPlugins = append(pluginConfigs.GlobalPlugins, &config.PluginConfig{
-				Name:   pluginName,
-				IsStop: isStop,
-				Config: pluginConfig,
-			})
+			pluginConfigs.GlobalPlugins = append(pluginConfigs.GlobalPlugins, cfg)
 		} else {
-			pluginConfigs.BeforePlugins = append(pluginConfigs.BeforePlugins, &config.PluginConfig{
-				Name:   pluginName,
-				IsStop: isStop,
-				Config: pluginConfig,
-			})
+			pluginConfigs.BeforePlugins = append(pluginConfigs.BeforePlugins, cfg)
 		}
 	}
 	return &pluginConfigs, nil
@@ -48,7 +42,7 @@ func GetGlobalPlugin() (*config.GatewayPluginConfig, error) {
 //GetAPIPlugins 获取接口插件
 func GetAPIPlugins() (map[string][]*config.PluginConfig, error) {
 	db := database.GetConnection()
-	sql := "SELECT goku_conn_plugin_api.apiID,goku_conn_plugin_api.strategyID,goku_conn_plugin_api.pluginName,goku_conn_plugin_api.pluginConfig,goku_plugin.isStop,goku_conn_plugin_api.updateTag FROM goku_conn_plugin_api INNER JOIN goku_plugin ON goku_conn_plugin_api.pluginName = goku_plugin.pluginName"
+	sql := "SELECT goku_conn_plugin_api.apiID,goku_conn_plugin_api.strategyID,goku_conn_plugin_api.pluginName,goku_conn_plugin_api.pluginConfig,goku_plugin.isStop,goku_conn_plugin_api.updateTag,goku_plugin.pluginTimeout,goku_plugin.failPolicy,goku_plugin.failStatus,goku_plugin.failBody FROM goku_conn_plugin_api INNER JOIN goku_plugin ON goku_conn_plugin_api.pluginName = goku_plugin.pluginName"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -59,7 +53,8 @@ func GetAPIPlugins() (map[string][]*config.PluginConfig, error) {
 		var apiID int
 		var isStop bool
 		var pluginName, pluginConfig, updateTag, strategyID string
-		err = rows.Scan(&apiID, &strategyID, &pluginName, &pluginConfig, &isStop, &updateTag)
+		cfg := new(config.PluginConfig)
+		err = rows.Scan(&apiID, &strategyID, &pluginName, &pluginConfig, &isStop, &updateTag, &cfg.Timeout, &cfg.FailPolicy, &cfg.FailStatus, &cfg.FailBody)
 		if err != nil {
 			return nil, err
 		}
@@ -67,12 +62,8 @@ func GetAPIPlugins() (map[string][]*config.PluginConfig, error) {
 		if _, ok := pluginMaps[key]; !ok {
 			pluginMaps[key] = make([]*config.PluginConfig, 0, 20)
 		}
-		pluginMaps[key] = append(pluginMaps[key], &config.PluginConfig{
-			Name:      pluginName,
-			IsStop:    isStop,
-			Config:    pluginConfig,
-			UpdateTag: updateTag,
-		})
+		cfg.Name, cfg.IsStop, cfg.Config, cfg.UpdateTag = pluginName, isStop, pluginConfig, updateTag
+		pluginMaps[key] = append(pluginMaps[key], cfg)
 	}
 	return pluginMaps, nil
 
@@ -81,7 +72,7 @@ func GetAPIPlugins() (map[string][]*config.PluginConfig, error) {
 //GetStrategyPlugins 获取策略插件
 func GetStrategyPlugins() (map[string][]*config.PluginConfig, map[string]map[string]string, error) {
 	db := database.GetConnection()
-	sql := "SELECT goku_conn_plugin_strategy.strategyID,goku_conn_plugin_strategy.pluginName,goku_conn_plugin_strategy.pluginConfig,goku_plugin.isStop,goku_conn_plugin_strategy.updateTag FROM goku_conn_plugin_strategy INNER JOIN goku_plugin ON goku_conn_plugin_strategy.pluginName = goku_plugin.pluginName WHERE goku_plugin.pluginStatus = 1 AND goku_conn_plugin_strategy.pluginStatus = 1"
+	sql := "SELECT goku_conn_plugin_strategy.strategyID,goku_conn_plugin_strategy.pluginName,goku_conn_plugin_strategy.pluginConfig,goku_plugin.isStop,goku_conn_plugin_strategy.updateTag,goku_plugin.pluginTimeout,goku_plugin.failPolicy,goku_plugin.failStatus,goku_plugin.failBody FROM goku_conn_plugin_strategy INNER JOIN goku_plugin ON goku_conn_plugin_strategy.pluginName = goku_plugin.pluginName WHERE goku_plugin.pluginStatus = 1 AND goku_conn_plugin_strategy.pluginStatus = 1"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, nil, err
@@ -92,7 +83,8 @@ func GetStrategyPlugins() (map[string][]*config.PluginConfig, map[string]map[str
 	for rows.Next() {
 		var isStop bool
 		var pluginName, pluginConfig, updateTag, strategyID string
-		err = rows.Scan(&strategyID, &pluginName, &pluginConfig, &isStop, &updateTag)
+		cfg := new(config.PluginConfig)
+		err = rows.Scan(&strategyID, &pluginName, &pluginConfig, &isStop, &updateTag, &cfg.Timeout, &cfg.FailPolicy, &cfg.FailStatus, &cfg.FailBody)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -107,12 +99,8 @@ func GetStrategyPlugins() (map[string][]*config.PluginConfig, map[string]map[str
 			authMaps[key][v] = pluginConfig
 		}
 
-		pluginMaps[key] = append(pluginMaps[key], &config.PluginConfig{
-			Name:      pluginName,
-			IsStop:    isStop,
-			Config:    pluginConfig,
-			UpdateTag: updateTag,
-		})
+		cfg.Name, cfg.IsStop, cfg.Config, cfg.UpdateTag = pluginName, isStop, pluginConfig, updateTag
+		pluginMaps[key] = append(pluginMaps[key], cfg)
 	}
 	return pluginMaps, authMaps, nil
 
@@ -1,6 +1,8 @@
 package dao_version_config
 
 import (
+	"encoding/json"
+	"fmt"
 	"strconv"
 
 	"github.com/eolinker/goku-api-gateway/common/database"
@@ -17,7 +19,7 @@ var autoAuthNames = map[string]string{
 //GetAPIsOfStrategy 获取策略内接口数据
 func GetAPIsOfStrategy() (map[string][]*config.APIOfStrategy, error) {
 	db := database.GetConnection()
-	sql := "SELECT goku_conn_strategy_api.apiID,IFNULL(goku_conn_strategy_api.target,''),goku_conn_strategy_api.strategyID FROM goku_conn_strategy_api;"
+	sql := "SELECT goku_conn_strategy_api.apiID,IFNULL(goku_conn_strategy_api.target,''),goku_conn_strategy_api.strategyID,IFNULL(goku_conn_strategy_api.canary,'') FROM goku_conn_strategy_api;"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -30,8 +32,8 @@ func GetAPIsOfStrategy() (map[string][]*config.APIOfStrategy, error) {
 	apiMaps := make(map[string][]*config.APIOfStrategy)
 	for rows.Next() {
 		var apiID int
-		var balanceName, strategyID string
-		err = rows.Scan(&apiID, &balanceName, &strategyID)
+		var balanceName, strategyID, canary string
+		err = rows.Scan(&apiID, &balanceName, &strategyID, &canary)
 		if err != nil {
 			return nil, err
 		}
@@ -43,10 +45,18 @@ func GetAPIsOfStrategy() (map[string][]*config.APIOfStrategy, error) {
 		if v, ok := apiPlugins[key]; ok {
 			ap = v
 		}
+		var canaryConfig *config.CanaryConfig
+		if canary != "" {
+			canaryConfig = new(config.CanaryConfig)
+			if err := json.Unmarshal([]byte(canary), canaryConfig); err != nil {
+				return nil, fmt.Errorf("canary of api %d in strategy %s: %s", apiID, strategyID, err.Error())
+			}
+		}
 		apiMaps[strategyID] = append(apiMaps[strategyID], &config.APIOfStrategy{
 			ID:      apiID,
 			Balance: balanceName,
 			Plugins: ap,
+			Canary:  canaryConfig,
 		})
 	}
 	return apiMaps, nil
@@ -55,7 +65,7 @@ func GetAPIsOfStrategy() (map[string][]*config.APIOfStrategy, error) {
 //GetStrategyConfig 获取策略配置
 func GetStrategyConfig() (string, []*config.StrategyConfig, error) {
 	db := database.GetConnection()
-	sql := "SELECT strategyID,strategyName,enableStatus,strategyType FROM goku_gateway_strategy"
+	sql := "SELECT strategyID,strategyName,enableStatus,strategyType,IFNULL(cors,''),IFNULL(ipAccess,'') FROM goku_gateway_strategy"
 
 	rows, err := db.Query(sql)
 	if err != nil {
@@ -75,10 +85,23 @@ func GetStrategyConfig() (string, []*config.StrategyConfig, error) {
 	for rows.Next() {
 		var strategyConfig config.StrategyConfig
 		var strategyType int
-		err = rows.Scan(&strategyConfig.ID, &strategyConfig.Name, &strategyConfig.Enable, &strategyType)
+		var cors, ipAccess string
+		err = rows.Scan(&strategyConfig.ID, &strategyConfig.Name, &strategyConfig.Enable, &strategyType, &cors, &ipAccess)
 		if err != nil {
 			return "", nil, err
 		}
+		if cors != "" {
+			corsConfig := new(config.CORSConfig)
+			if err := json.Unmarshal([]byte(cors), corsConfig); err == nil {
+				strategyConfig.CORS = corsConfig
+			}
+		}
+		if ipAccess != "" {
+			ipAccessConfig := new(config.IPAccessConfig)
+			if err := json.Unmarshal([]byte(ipAccess), ipAccessConfig); err == nil {
+				strategyConfig.IPAccess = ipAccessConfig
+			}
+		}
 		if _, ok := strategyPlugins[strategyConfig.ID]; ok {
 			strategyConfig.Plugins = strategyPlugins[strategyConfig.ID]
 		}
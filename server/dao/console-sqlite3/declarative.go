@@ -0,0 +1,866 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	database2 "github.com/eolinker/goku-api-gateway/common/database"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+type declarativeQueryer interface {
+	Query(query string, args ...interface{}) (*SQL.Rows, error)
+}
+
+type declarativeAPI struct {
+	id        int
+	projectID int
+	api       *entity.DeclarativeAPI
+}
+
+type declarativeGroup struct {
+	id    int
+	path  string // 分组名称路径，以 / 分隔
+	ids   string // 数据库中的groupPath，以 , 分隔的分组ID
+	depth int
+}
+
+// declarativeState 数据库中的当前配置
+type declarativeState struct {
+	services       map[string]*entity.DeclarativeService
+	balances       map[string]*entity.DeclarativeBalance
+	projects       map[string]int
+	projectNames   map[int]string
+	groups         map[int]map[string]*declarativeGroup // projectID -> 分组路径
+	apis           map[int]map[string]*declarativeAPI   // projectID -> 接口名称
+	apiByID        map[int]*declarativeAPI
+	strategyGroups map[string]int
+	strategies     map[string]*entity.DeclarativeStrategy
+}
+
+type declarativePlan struct {
+	changes []*entity.DeclarativeChange
+}
+
+func (p *declarativePlan) add(action, kind, key string) {
+	p.changes = append(p.changes, &entity.DeclarativeChange{Action: action, Kind: kind, Key: key})
+}
+
+//ExportDeclarative 导出声明式网关配置
+func ExportDeclarative() (*entity.Declarative, error) {
+	st, err := loadDeclarativeState(database2.GetConnection())
+	if err != nil {
+		return nil, err
+	}
+	d := &entity.Declarative{
+		Projects:   make([]*entity.DeclarativeProject, 0, len(st.projects)),
+		Strategies: make([]*entity.DeclarativeStrategy, 0, len(st.strategies)),
+		Balances:   make([]*entity.DeclarativeBalance, 0, len(st.balances)),
+		Services:   make([]*entity.DeclarativeService, 0, len(st.services)),
+	}
+	for _, s := range st.services {
+		d.Services = append(d.Services, s)
+	}
+	sort.Slice(d.Services, func(i, j int) bool { return d.Services[i].Name < d.Services[j].Name })
+	for _, b := range st.balances {
+		d.Balances = append(d.Balances, b)
+	}
+	sort.Slice(d.Balances, func(i, j int) bool { return d.Balances[i].Name < d.Balances[j].Name })
+	for name, id := range st.projects {
+		p := &entity.DeclarativeProject{Name: name, Groups: make([]string, 0), APIs: make([]*entity.DeclarativeAPI, 0)}
+		for path := range st.groups[id] {
+			p.Groups = append(p.Groups, path)
+		}
+		sort.Strings(p.Groups)
+		for _, api := range st.apis[id] {
+			p.APIs = append(p.APIs, api.api)
+		}
+		sort.Slice(p.APIs, func(i, j int) bool { return p.APIs[i].Name < p.APIs[j].Name })
+		d.Projects = append(d.Projects, p)
+	}
+	sort.Slice(d.Projects, func(i, j int) bool { return d.Projects[i].Name < d.Projects[j].Name })
+	for _, s := range st.strategies {
+		d.Strategies = append(d.Strategies, s)
+	}
+	sort.Slice(d.Strategies, func(i, j int) bool { return d.Strategies[i].ID < d.Strategies[j].ID })
+	return d, nil
+}
+
+//ImportDeclarative 导入声明式网关配置，在同一事务中执行，dryRun时回滚并仅返回变更计划
+func ImportDeclarative(d *entity.Declarative, dryRun bool, userID int) ([]*entity.DeclarativeChange, error) {
+	db := database2.GetConnection()
+	Tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	st, err := loadDeclarativeState(Tx)
+	if err != nil {
+		Tx.Rollback()
+		return nil, err
+	}
+	t := time.Now()
+	im := &declarativeImporter{
+		Tx:        Tx,
+		st:        st,
+		plan:      &declarativePlan{changes: make([]*entity.DeclarativeChange, 0)},
+		userID:    userID,
+		now:       t.Format("2006-01-02 15:04:05"),
+		updateTag: t.Format("20060102150405"),
+	}
+	err = im.apply(d)
+	if err != nil || dryRun {
+		Tx.Rollback()
+		if err != nil {
+			return nil, err
+		}
+		return im.plan.changes, nil
+	}
+	err = Tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return im.plan.changes, nil
+}
+
+type declarativeImporter struct {
+	Tx        *SQL.Tx
+	st        *declarativeState
+	plan      *declarativePlan
+	userID    int
+	now       string
+	updateTag string
+}
+
+func (im *declarativeImporter) apply(d *entity.Declarative) error {
+	for _, s := range d.Services {
+		if err := im.applyService(s); err != nil {
+			return err
+		}
+	}
+	for _, b := range d.Balances {
+		if err := im.applyBalance(b); err != nil {
+			return err
+		}
+	}
+	for _, p := range d.Projects {
+		if err := im.applyProject(p); err != nil {
+			return err
+		}
+	}
+	for _, s := range d.Strategies {
+		if err := im.applyStrategy(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *declarativeImporter) applyService(s *entity.DeclarativeService) error {
+	clusterConfig := declarativeMap(s.ClusterConfig)
+	old, has := im.st.services[s.Name]
+	if has && sameDeclarative(old, s) {
+		return nil
+	}
+	if s.Default {
+		if _, err := im.Tx.Exec("UPDATE `goku_service_config` SET `default` = 0;"); err != nil {
+			return err
+		}
+	}
+	var err error
+	if !has {
+		_, err = im.Tx.Exec("INSERT INTO `goku_service_config`(`name`,`driver`,`default`,`desc`,`config`,`clusterConfig`,`healthCheck`,`healthCheckPath`,`healthCheckPeriod`,`healthCheckCode`,`healthCheckTimeOut`,`createTime`,`updateTime`)VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?);", s.Name, s.Driver, declarativeFlag(s.Default), s.Desc, s.Config, clusterConfig, declarativeFlag(s.HealthCheck), s.HealthCheckPath, s.HealthCheckPeriod, s.HealthCheckCode, s.HealthCheckTimeout, im.now, im.now)
+		im.plan.add(entity.DeclarativeCreate, "service", s.Name)
+	} else {
+		_, err = im.Tx.Exec("UPDATE `goku_service_config` SET `driver` = ?,`default` = ?,`desc` = ?,`config` = ?,`clusterConfig` = ?,`healthCheck` = ?,`healthCheckPath` = ?,`healthCheckPeriod` = ?,`healthCheckCode` = ?,`healthCheckTimeOut` = ?,`updateTime` = ? WHERE `name` = ?;", s.Driver, declarativeFlag(s.Default), s.Desc, s.Config, clusterConfig, declarativeFlag(s.HealthCheck), s.HealthCheckPath, s.HealthCheckPeriod, s.HealthCheckCode, s.HealthCheckTimeout, im.now, s.Name)
+		im.plan.add(entity.DeclarativeUpdate, "service", s.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if s.Default {
+		for _, o := range im.st.services {
+			o.Default = false
+		}
+	}
+	im.st.services[s.Name] = s
+	return nil
+}
+
+func (im *declarativeImporter) applyBalance(b *entity.DeclarativeBalance) error {
+	if _, has := im.st.services[b.Service]; !has {
+		return fmt.Errorf("balance %s: service %s does not exist", b.Name, b.Service)
+	}
+	staticCluster := declarativeMap(b.StaticCluster)
+	old, has := im.st.balances[b.Name]
+	if has && sameDeclarative(old, b) {
+		return nil
+	}
+	var err error
+	if !has {
+		_, err = im.Tx.Exec("INSERT INTO goku_balance (`balanceName`,`serviceName`,`appName`,`static`,`staticCluster`,`balanceDesc`,`createTime`,`updateTime`,`defaultConfig`,`clusterConfig`,`balanceConfig`) VALUES (?,?,?,?,?,?,?,?,'','','');", b.Name, b.Service, b.AppName, b.Static, staticCluster, b.Desc, im.now, im.now)
+		im.plan.add(entity.DeclarativeCreate, "balance", b.Name)
+	} else {
+		_, err = im.Tx.Exec("UPDATE `goku_balance` SET `serviceName` = ?,`appName` = ?,`static` = ?,`staticCluster` = ?,`balanceDesc` = ?,`updateTime` = ? WHERE `balanceName` = ?;", b.Service, b.AppName, b.Static, staticCluster, b.Desc, im.now, b.Name)
+		im.plan.add(entity.DeclarativeUpdate, "balance", b.Name)
+	}
+	if err != nil {
+		return err
+	}
+	im.st.balances[b.Name] = b
+	return nil
+}
+
+// applyProject 同步项目下的分组及接口，未声明的接口将被删除，分组仅新增
+func (im *declarativeImporter) applyProject(p *entity.DeclarativeProject) error {
+	projectID, has := im.st.projects[p.Name]
+	if !has {
+		result, err := im.Tx.Exec("INSERT INTO goku_gateway_project (projectName,createTime,updateTime) VALUES (?,?,?);", p.Name, im.now, im.now)
+		if err != nil {
+			return err
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		projectID = int(id)
+		im.st.projects[p.Name] = projectID
+		im.st.projectNames[projectID] = p.Name
+		im.st.groups[projectID] = make(map[string]*declarativeGroup)
+		im.st.apis[projectID] = make(map[string]*declarativeAPI)
+		im.plan.add(entity.DeclarativeCreate, "project", p.Name)
+	}
+	for _, path := range p.Groups {
+		if _, err := im.ensureGroup(projectID, p.Name, path); err != nil {
+			return err
+		}
+	}
+
+	changed := false
+	declared := make(map[string]bool)
+	for _, api := range p.APIs {
+		declared[api.Name] = true
+		groupID, err := im.ensureGroup(projectID, p.Name, api.Group)
+		if err != nil {
+			return err
+		}
+		old, has := im.st.apis[projectID][api.Name]
+		if has && sameDeclarative(old.api, api) {
+			continue
+		}
+		changed = true
+		args := []interface{}{groupID, api.RequestURL, api.RequestMethod, api.Protocol, api.Balance, api.TargetURL, api.TargetMethod, strconv.FormatBool(api.IsFollow), strconv.FormatBool(api.StripPrefix), strconv.FormatBool(api.StripSlash), api.Timeout, api.RetryCount, api.AlertValve, api.APIType, api.ResponseDataType, declarativeRaw(api.Steps), api.StaticResponse, declarativeRaw(api.CORS), declarativeRaw(api.IPAccess), declarativeRaw(api.Transform), declarativeRaw(api.Mock), im.now, im.userID}
+		key := p.Name + "/" + api.Name
+		if has {
+			_, err = im.Tx.Exec("UPDATE goku_gateway_api SET groupID = ?,requestURL = ?,requestMethod = ?,protocol = ?,balanceName = ?,targetURL = ?,targetMethod = ?,isFollow = ?,stripPrefix = ?,stripSlash = ?,timeout = ?,retryCount = ?,alertValve = ?,apiType = ?,responseDataType = ?,linkApis = ?,staticResponse = ?,cors = ?,ipAccess = ?,transform = ?,mock = ?,updateTime = ?,lastUpdateUserID = ? WHERE apiID = ?;", append(args, old.id)...)
+			if err != nil {
+				return err
+			}
+			old.api = api
+			im.plan.add(entity.DeclarativeUpdate, "api", key)
+			continue
+		}
+		result, err := im.Tx.Exec("INSERT INTO goku_gateway_api (groupID,requestURL,requestMethod,protocol,balanceName,targetURL,targetMethod,isFollow,stripPrefix,stripSlash,timeout,retryCount,alertValve,apiType,responseDataType,linkApis,staticResponse,cors,ipAccess,transform,mock,updateTime,lastUpdateUserID,projectID,apiName,createTime,managerID,createUserID) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);", append(args, projectID, api.Name, im.now, im.userID, im.userID)...)
+		if err != nil {
+			return err
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		a := &declarativeAPI{id: int(id), projectID: projectID, api: api}
+		im.st.apis[projectID][api.Name] = a
+		im.st.apiByID[a.id] = a
+		im.plan.add(entity.DeclarativeCreate, "api", key)
+	}
+	for name, api := range im.st.apis[projectID] {
+		if declared[name] {
+			continue
+		}
+		changed = true
+		for _, sql := range []string{"DELETE FROM goku_gateway_api WHERE apiID = ?;", "DELETE FROM goku_conn_strategy_api WHERE apiID = ?;", "DELETE FROM goku_conn_plugin_api WHERE apiID = ?;"} {
+			if _, err := im.Tx.Exec(sql, api.id); err != nil {
+				return err
+			}
+		}
+		delete(im.st.apis[projectID], name)
+		delete(im.st.apiByID, api.id)
+		im.plan.add(entity.DeclarativeDelete, "api", p.Name+"/"+name)
+	}
+	if changed {
+		_, err := im.Tx.Exec("UPDATE goku_gateway_project SET updateTime = ? WHERE projectID = ?;", im.now, projectID)
+		return err
+	}
+	return nil
+}
+
+// ensureGroup 按路径逐级创建缺失的接口分组
+func (im *declarativeImporter) ensureGroup(projectID int, projectName, path string) (int, error) {
+	if path == "" {
+		return 0, nil
+	}
+	if g, has := im.st.groups[projectID][path]; has {
+		return g.id, nil
+	}
+	parentID, parentPath, name := 0, "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		parentPath, name = path[:i], path[i+1:]
+		id, err := im.ensureGroup(projectID, projectName, parentPath)
+		if err != nil {
+			return 0, err
+		}
+		parentID = id
+	}
+	result, err := im.Tx.Exec("INSERT INTO goku_gateway_api_group (projectID,groupName,parentGroupID) VALUES (?,?,?);", projectID, name, parentID)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	g := &declarativeGroup{id: int(id), path: path, ids: strconv.Itoa(int(id)), depth: 1}
+	if parent, has := im.st.groups[projectID][parentPath]; has {
+		g.ids = parent.ids + "," + g.ids
+		g.depth = parent.depth + 1
+	}
+	_, err = im.Tx.Exec("UPDATE goku_gateway_api_group SET groupPath = ?,groupDepth = ? WHERE groupID = ?;", g.ids, g.depth, g.id)
+	if err != nil {
+		return 0, err
+	}
+	im.st.groups[projectID][path] = g
+	im.plan.add(entity.DeclarativeCreate, "apiGroup", projectName+"/"+path)
+	return g.id, nil
+}
+
+func (im *declarativeImporter) applyStrategy(s *entity.DeclarativeStrategy) error {
+	groupID := 0
+	if s.Group != "" {
+		id, has := im.st.strategyGroups[s.Group]
+		if !has {
+			result, err := im.Tx.Exec("INSERT INTO goku_gateway_strategy_group (groupName,groupType) VALUES (?,0);", s.Group)
+			if err != nil {
+				return err
+			}
+			newID, err := result.LastInsertId()
+			if err != nil {
+				return err
+			}
+			id = int(newID)
+			im.st.strategyGroups[s.Group] = id
+			im.plan.add(entity.DeclarativeCreate, "strategyGroup", s.Group)
+		}
+		groupID = id
+	}
+	old, has := im.st.strategies[s.ID]
+	if !has {
+		old = &entity.DeclarativeStrategy{ID: s.ID}
+		_, err := im.Tx.Exec("INSERT INTO goku_gateway_strategy (strategyID,strategyName,groupID,strategyType,enableStatus,cors,ipAccess,createTime,updateTime) VALUES (?,?,?,?,?,?,?,?,?);", s.ID, s.Name, groupID, s.Type, declarativeFlag(s.Enable), declarativeRaw(s.CORS), declarativeRaw(s.IPAccess), im.now, im.now)
+		if err != nil {
+			return err
+		}
+		im.plan.add(entity.DeclarativeCreate, "strategy", s.ID)
+	} else if old.Name != s.Name || old.Group != s.Group || old.Type != s.Type || old.Enable != s.Enable || !sameDeclarative(old.CORS, s.CORS) || !sameDeclarative(old.IPAccess, s.IPAccess) {
+		_, err := im.Tx.Exec("UPDATE goku_gateway_strategy SET strategyName = ?,groupID = ?,strategyType = ?,enableStatus = ?,cors = ?,ipAccess = ?,updateTime = ? WHERE strategyID = ?;", s.Name, groupID, s.Type, declarativeFlag(s.Enable), declarativeRaw(s.CORS), declarativeRaw(s.IPAccess), im.now, s.ID)
+		if err != nil {
+			return err
+		}
+		im.plan.add(entity.DeclarativeUpdate, "strategy", s.ID)
+	}
+
+	err := im.syncPlugins("strategyPlugin", s.ID, old.Plugins, s.Plugins,
+		"INSERT INTO goku_conn_plugin_strategy (pluginConfig,pluginStatus,updateTag,updateTime,createTime,updaterID,strategyID,pluginName) VALUES (?,?,?,?,?,?,?,?);",
+		"UPDATE goku_conn_plugin_strategy SET pluginConfig = ?,pluginStatus = ?,updateTag = ?,updateTime = ?,updaterID = ? WHERE strategyID = ? AND pluginName = ?;",
+		"DELETE FROM goku_conn_plugin_strategy WHERE strategyID = ? AND pluginName = ?;",
+		s.ID)
+	if err != nil {
+		return err
+	}
+
+	oldAPIs := make(map[int]*entity.DeclarativeStrategyAPI)
+	for _, a := range old.APIs {
+		if id, has := im.apiID(a.Project, a.API); has {
+			oldAPIs[id] = a
+		}
+	}
+	declared := make(map[int]bool)
+	for _, a := range s.APIs {
+		apiID, has := im.apiID(a.Project, a.API)
+		if !has {
+			return fmt.Errorf("strategy %s: api %s/%s does not exist", s.ID, a.Project, a.API)
+		}
+		declared[apiID] = true
+		key := s.ID + "/" + a.Project + "/" + a.API
+		o, has := oldAPIs[apiID]
+		if !has {
+			o = &entity.DeclarativeStrategyAPI{}
+			_, err = im.Tx.Exec("INSERT INTO goku_conn_strategy_api (strategyID,apiID,target,canary,updateTime) VALUES (?,?,?,?,?);", s.ID, apiID, a.Target, declarativeRaw(a.Canary), im.now)
+			im.plan.add(entity.DeclarativeCreate, "strategyAPI", key)
+		} else if o.Target != a.Target || !sameDeclarative(o.Canary, a.Canary) {
+			_, err = im.Tx.Exec("UPDATE goku_conn_strategy_api SET target = ?,canary = ?,updateTime = ? WHERE strategyID = ? AND apiID = ?;", a.Target, declarativeRaw(a.Canary), im.now, s.ID, apiID)
+			im.plan.add(entity.DeclarativeUpdate, "strategyAPI", key)
+		}
+		if err != nil {
+			return err
+		}
+		err = im.syncPlugins("apiPlugin", key, o.Plugins, a.Plugins,
+			"INSERT INTO goku_conn_plugin_api (pluginConfig,pluginStatus,updateTag,updateTime,createTime,updaterID,strategyID,apiID,pluginName) VALUES (?,?,?,?,?,?,?,?,?);",
+			"UPDATE goku_conn_plugin_api SET pluginConfig = ?,pluginStatus = ?,updateTag = ?,updateTime = ?,updaterID = ? WHERE strategyID = ? AND apiID = ? AND pluginName = ?;",
+			"DELETE FROM goku_conn_plugin_api WHERE strategyID = ? AND apiID = ? AND pluginName = ?;",
+			s.ID, apiID)
+		if err != nil {
+			return err
+		}
+	}
+	for apiID, a := range oldAPIs {
+		if declared[apiID] {
+			continue
+		}
+		if _, err := im.Tx.Exec("DELETE FROM goku_conn_strategy_api WHERE strategyID = ? AND apiID = ?;", s.ID, apiID); err != nil {
+			return err
+		}
+		if _, err := im.Tx.Exec("DELETE FROM goku_conn_plugin_api WHERE strategyID = ? AND apiID = ?;", s.ID, apiID); err != nil {
+			return err
+		}
+		im.plan.add(entity.DeclarativeDelete, "strategyAPI", s.ID+"/"+a.Project+"/"+a.API)
+	}
+	im.st.strategies[s.ID] = s
+	return nil
+}
+
+// syncPlugins 同步插件列表，owner为插件所属对象的主键参数
+func (im *declarativeImporter) syncPlugins(kind, key string, old, plugins []*entity.DeclarativePlugin, insertSQL, updateSQL, deleteSQL string, owner ...interface{}) error {
+	oldMap := make(map[string]*entity.DeclarativePlugin)
+	for _, p := range old {
+		oldMap[p.Name] = p
+	}
+	declared := make(map[string]bool)
+	for _, p := range plugins {
+		declared[p.Name] = true
+		o, has := oldMap[p.Name]
+		if has && sameDeclarative(o, p) {
+			continue
+		}
+		status := 0
+		if p.Enable {
+			status = 1
+		}
+		var err error
+		if has {
+			args := append([]interface{}{declarativeRaw(p.Config), status, im.updateTag, im.now, im.userID}, owner...)
+			_, err = im.Tx.Exec(updateSQL, append(args, p.Name)...)
+			im.plan.add(entity.DeclarativeUpdate, kind, key+"/"+p.Name)
+		} else {
+			args := append([]interface{}{declarativeRaw(p.Config), status, im.updateTag, im.now, im.now, im.userID}, owner...)
+			_, err = im.Tx.Exec(insertSQL, append(args, p.Name)...)
+			im.plan.add(entity.DeclarativeCreate, kind, key+"/"+p.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for name := range oldMap {
+		if declared[name] {
+			continue
+		}
+		if _, err := im.Tx.Exec(deleteSQL, append(owner, name)...); err != nil {
+			return err
+		}
+		im.plan.add(entity.DeclarativeDelete, kind, key+"/"+name)
+	}
+	return nil
+}
+
+func (im *declarativeImporter) apiID(project, api string) (int, bool) {
+	projectID, has := im.st.projects[project]
+	if !has {
+		return 0, false
+	}
+	a, has := im.st.apis[projectID][api]
+	if !has {
+		return 0, false
+	}
+	return a.id, true
+}
+
+func loadDeclarativeState(q declarativeQueryer) (*declarativeState, error) {
+	st := &declarativeState{
+		services:       make(map[string]*entity.DeclarativeService),
+		balances:       make(map[string]*entity.DeclarativeBalance),
+		projects:       make(map[string]int),
+		projectNames:   make(map[int]string),
+		groups:         make(map[int]map[string]*declarativeGroup),
+		apis:           make(map[int]map[string]*declarativeAPI),
+		apiByID:        make(map[int]*declarativeAPI),
+		strategyGroups: make(map[string]int),
+		strategies:     make(map[string]*entity.DeclarativeStrategy),
+	}
+	loaders := []func(declarativeQueryer) error{st.loadServices, st.loadBalances, st.loadProjects, st.loadGroups, st.loadAPIs, st.loadStrategies, st.loadStrategyPlugins, st.loadStrategyAPIs, st.loadAPIPlugins}
+	for _, load := range loaders {
+		if err := load(q); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+func (st *declarativeState) loadServices(q declarativeQueryer) error {
+	rows, err := q.Query("SELECT `name`,`driver`,IFNULL(`default`,0),`desc`,`config`,`clusterConfig`,`healthCheck`,`healthCheckPath`,`healthCheckPeriod`,`healthCheckCode`,`healthCheckTimeOut` FROM `goku_service_config`;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		s := &entity.DeclarativeService{}
+		clusterConfig := ""
+		err = rows.Scan(&s.Name, &s.Driver, &s.Default, &s.Desc, &s.Config, &clusterConfig, &s.HealthCheck, &s.HealthCheckPath, &s.HealthCheckPeriod, &s.HealthCheckCode, &s.HealthCheckTimeout)
+		if err != nil {
+			return err
+		}
+		if clusterConfig != "" {
+			json.Unmarshal([]byte(clusterConfig), &s.ClusterConfig)
+		}
+		st.services[s.Name] = s
+	}
+	return rows.Err()
+}
+
+func (st *declarativeState) loadBalances(q declarativeQueryer) error {
+	rows, err := q.Query("SELECT `balanceName`,`serviceName`,`appName`,IFNULL(`static`,''),IFNULL(`staticCluster`,''),IFNULL(`balanceDesc`,'') FROM `goku_balance`;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		b := &entity.DeclarativeBalance{}
+		staticCluster := ""
+		err = rows.Scan(&b.Name, &b.Service, &b.AppName, &b.Static, &staticCluster, &b.Desc)
+		if err != nil {
+			return err
+		}
+		if staticCluster != "" {
+			json.Unmarshal([]byte(staticCluster), &b.StaticCluster)
+		}
+		st.balances[b.Name] = b
+	}
+	return rows.Err()
+}
+
+func (st *declarativeState) loadProjects(q declarativeQueryer) error {
+	rows, err := q.Query("SELECT projectID,projectName FROM goku_gateway_project;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var name string
+		if err = rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		st.projects[name] = id
+		st.projectNames[id] = name
+		st.groups[id] = make(map[string]*declarativeGroup)
+		st.apis[id] = make(map[string]*declarativeAPI)
+	}
+	return rows.Err()
+}
+
+func (st *declarativeState) loadGroups(q declarativeQueryer) error {
+	rows, err := q.Query("SELECT groupID,projectID,groupName,IFNULL(groupPath,''),IFNULL(groupDepth,1) FROM goku_gateway_api_group;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	names := make(map[int]string)
+	groups := make(map[int]*declarativeGroup)
+	projects := make(map[int]int)
+	for rows.Next() {
+		g := &declarativeGroup{}
+		var projectID int
+		var name string
+		if err = rows.Scan(&g.id, &projectID, &name, &g.ids, &g.depth); err != nil {
+			return err
+		}
+		if g.ids == "" {
+			g.ids = strconv.Itoa(g.id)
+		}
+		names[g.id] = name
+		groups[g.id] = g
+		projects[g.id] = projectID
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	for id, g := range groups {
+		path := make([]string, 0, g.depth)
+		for _, s := range strings.Split(g.ids, ",") {
+			gid, _ := strconv.Atoi(s)
+			if name, has := names[gid]; has {
+				path = append(path, name)
+			}
+		}
+		g.path = strings.Join(path, "/")
+		if m, has := st.groups[projects[id]]; has {
+			m[g.path] = g
+		}
+	}
+	return nil
+}
+
+func (st *declarativeState) groupPath(projectID, groupID int) string {
+	for path, g := range st.groups[projectID] {
+		if g.id == groupID {
+			return path
+		}
+	}
+	return ""
+}
+
+func (st *declarativeState) loadAPIs(q declarativeQueryer) error {
+	rows, err := q.Query("SELECT apiID,projectID,groupID,apiName,requestURL,requestMethod,IFNULL(protocol,''),IFNULL(balanceName,''),IFNULL(targetURL,''),IFNULL(targetMethod,''),isFollow,IFNULL(stripPrefix,''),IFNULL(stripSlash,''),IFNULL(timeout,0),IFNULL(retryCount,0),alertValve,apiType,responseDataType,IFNULL(linkApis,''),IFNULL(staticResponse,''),IFNULL(cors,''),IFNULL(ipAccess,''),IFNULL(transform,''),IFNULL(mock,'') FROM goku_gateway_api;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		a := &declarativeAPI{api: &entity.DeclarativeAPI{}}
+		api := a.api
+		var groupID int
+		var isFollow, stripPrefix, stripSlash, steps, cors, ipAccess, transform, mock string
+		err = rows.Scan(&a.id, &a.projectID, &groupID, &api.Name, &api.RequestURL, &api.RequestMethod, &api.Protocol, &api.Balance, &api.TargetURL, &api.TargetMethod, &isFollow, &stripPrefix, &stripSlash, &api.Timeout, &api.RetryCount, &api.AlertValve, &api.APIType, &api.ResponseDataType, &steps, &api.StaticResponse, &cors, &ipAccess, &transform, &mock)
+		if err != nil {
+			return err
+		}
+		if _, has := st.apis[a.projectID]; !has {
+			continue
+		}
+		api.Group = st.groupPath(a.projectID, groupID)
+		api.IsFollow, api.StripPrefix, api.StripSlash = isFollow == "true", stripPrefix == "true", stripSlash == "true"
+		api.Steps, api.CORS, api.IPAccess, api.Transform, api.Mock = declarativeValue(steps), declarativeValue(cors), declarativeValue(ipAccess), declarativeValue(transform), declarativeValue(mock)
+		st.apis[a.projectID][api.Name] = a
+		st.apiByID[a.id] = a
+	}
+	return rows.Err()
+}
+
+func (st *declarativeState) loadStrategies(q declarativeQueryer) error {
+	rows, err := q.Query("SELECT S.strategyID,S.strategyName,IFNULL(G.groupName,''),S.strategyType,S.enableStatus,IFNULL(S.cors,''),IFNULL(S.ipAccess,'') FROM goku_gateway_strategy S LEFT JOIN goku_gateway_strategy_group G ON S.groupID = G.groupID;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		s := &entity.DeclarativeStrategy{Plugins: make([]*entity.DeclarativePlugin, 0), APIs: make([]*entity.DeclarativeStrategyAPI, 0)}
+		var cors, ipAccess string
+		if err = rows.Scan(&s.ID, &s.Name, &s.Group, &s.Type, &s.Enable, &cors, &ipAccess); err != nil {
+			return err
+		}
+		s.CORS, s.IPAccess = declarativeValue(cors), declarativeValue(ipAccess)
+		st.strategies[s.ID] = s
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	groups, err := q.Query("SELECT groupID,groupName FROM goku_gateway_strategy_group;")
+	if err != nil {
+		return err
+	}
+	defer groups.Close()
+	for groups.Next() {
+		var id int
+		var name string
+		if err = groups.Scan(&id, &name); err != nil {
+			return err
+		}
+		st.strategyGroups[name] = id
+	}
+	return groups.Err()
+}
+
+func (st *declarativeState) loadStrategyPlugins(q declarativeQueryer) error {
+	rows, err := q.Query("SELECT strategyID,pluginName,IFNULL(pluginConfig,''),IFNULL(pluginStatus,0) FROM goku_conn_plugin_strategy ORDER BY pluginName;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var strategyID, config string
+		var status int
+		p := &entity.DeclarativePlugin{}
+		if err = rows.Scan(&strategyID, &p.Name, &config, &status); err != nil {
+			return err
+		}
+		p.Enable, p.Config = status == 1, declarativeValue(config)
+		if s, has := st.strategies[strategyID]; has {
+			s.Plugins = append(s.Plugins, p)
+		}
+	}
+	return rows.Err()
+}
+
+func (st *declarativeState) loadStrategyAPIs(q declarativeQueryer) error {
+	rows, err := q.Query("SELECT strategyID,apiID,IFNULL(target,''),IFNULL(canary,'') FROM goku_conn_strategy_api;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var strategyID, target, canary string
+		var apiID int
+		if err = rows.Scan(&strategyID, &apiID, &target, &canary); err != nil {
+			return err
+		}
+		s, has := st.strategies[strategyID]
+		api, ok := st.apiByID[apiID]
+		if !has || !ok {
+			continue
+		}
+		s.APIs = append(s.APIs, &entity.DeclarativeStrategyAPI{
+			Project: st.projectNames[api.projectID],
+			API:     api.api.Name,
+			Target:  target,
+			Canary:  declarativeValue(canary),
+			Plugins: make([]*entity.DeclarativePlugin, 0),
+		})
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	for _, s := range st.strategies {
+		sort.Slice(s.APIs, func(i, j int) bool {
+			if s.APIs[i].Project != s.APIs[j].Project {
+				return s.APIs[i].Project < s.APIs[j].Project
+			}
+			return s.APIs[i].API < s.APIs[j].API
+		})
+	}
+	return nil
+}
+
+func (st *declarativeState) loadAPIPlugins(q declarativeQueryer) error {
+	rows, err := q.Query("SELECT strategyID,apiID,pluginName,IFNULL(pluginConfig,''),IFNULL(pluginStatus,0) FROM goku_conn_plugin_api ORDER BY pluginName;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var strategyID, config string
+		var apiID, status int
+		p := &entity.DeclarativePlugin{}
+		if err = rows.Scan(&strategyID, &apiID, &p.Name, &config, &status); err != nil {
+			return err
+		}
+		p.Enable, p.Config = status == 1, declarativeValue(config)
+		s, has := st.strategies[strategyID]
+		api, ok := st.apiByID[apiID]
+		if !has || !ok {
+			continue
+		}
+		for _, a := range s.APIs {
+			if a.Project == st.projectNames[api.projectID] && a.API == api.api.Name {
+				a.Plugins = append(a.Plugins, p)
+				break
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// declarativeValue 数据库中的json字段解析为结构化数据，非json内容保持为字符串
+func declarativeValue(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err == nil {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return v
+		}
+	}
+	return s
+}
+
+// declarativeFlag 布尔值转换为整数列中存储的0、1
+func declarativeFlag(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// declarativeRaw 结构化数据转换为数据库中存储的字符串
+func declarativeRaw(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func declarativeMap(m map[string]string) string {
+	if len(m) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(m)
+	return string(data)
+}
+
+// sameDeclarative 以json通用结构比较两个配置是否一致，json字符串与对应的对象视为相同
+func sameDeclarative(a, b interface{}) bool {
+	return reflect.DeepEqual(declarativeNormalize(a), declarativeNormalize(b))
+}
+
+func declarativeNormalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var n interface{}
+	if err := json.Unmarshal(data, &n); err != nil {
+		return v
+	}
+	return declarativeExpand(n)
+}
+
+func declarativeExpand(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		if e := declarativeValue(value); e != nil {
+			if _, ok := e.(string); !ok {
+				return declarativeExpand(e)
+			}
+		}
+		if value == "" {
+			return nil
+		}
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = declarativeExpand(item)
+			if value[k] == nil {
+				delete(value, k)
+			}
+		}
+		if len(value) == 0 {
+			return nil
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = declarativeExpand(item)
+		}
+	}
+	return v
+}
@@ -0,0 +1,304 @@
+package console_sqlite3
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+	"github.com/eolinker/goku-api-gateway/config"
+	dao_version_config "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/dao-version-config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+type testDatabase struct {
+	driver string
+	source string
+}
+
+func (d *testDatabase) GetDriver() string {
+	return d.driver
+}
+
+func (d *testDatabase) GetSource() string {
+	return d.source
+}
+
+//testDatabases 待测试的数据库，设置GOKU_TEST_POSTGRES为连接串时同时测试PostgreSQL
+func testDatabases(t *testing.T) []*testDatabase {
+	dir, err := ioutil.TempDir("", "goku-dao")
+	if err != nil {
+		t.Fatal(err)
+	}
+	databases := []*testDatabase{{driver: "sqlite3", source: filepath.Join(dir, "goku.db")}}
+	if dsn := os.Getenv("GOKU_TEST_POSTGRES"); dsn != "" {
+		databases = append(databases, &testDatabase{driver: "postgres", source: dsn})
+	} else {
+		t.Log("GOKU_TEST_POSTGRES is not set, skip postgres")
+	}
+	return databases
+}
+
+func initTestDatabase(t *testing.T, d *testDatabase) {
+	if err := database.InitConnection(d); err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	// 迁移脚本路径相对于控制台的资源目录
+	if err := os.Chdir("../../../build/console/resources"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.Migrate(false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDialects(t *testing.T) {
+	for _, d := range testDatabases(t) {
+		t.Run(d.driver, func(t *testing.T) {
+			initTestDatabase(t, d)
+			testAccount(t)
+			testInsertID(t)
+			testPage(t)
+			testReplace(t)
+			testMonitor(t)
+			testPluginPolicy(t)
+			testAlert(t)
+			testRollback(t)
+			testCanary(t)
+		})
+	}
+}
+
+//TestUpgradeBaseline 旧版本创建的数据库（没有版本表）升级后，登录依赖的用户来源等列可用
+func TestUpgradeBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goku-upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := database.InitConnection(&testDatabase{driver: "sqlite3", source: filepath.Join(dir, "goku.db")}); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := ioutil.ReadFile("../../../build/console/resources/sql/migrations/sqlite3/0001_init.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.GetConnection().Exec(string(baseline)); err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	if err := os.Chdir("../../../build/console/resources"); err != nil {
+		t.Fatal(err)
+	}
+	status, err := database.GetMigrationStatus()
+	if err != nil || !status.Baseline {
+		t.Fatalf("expect baseline database, got %+v %v", status, err)
+	}
+	if _, err := database.Migrate(false); err != nil {
+		t.Fatal(err)
+	}
+	testAccount(t)
+}
+
+func testAccount(t *testing.T) {
+	if !Register("admin", "pw") {
+		t.Fatal("register failed")
+	}
+	flag, userID := Login("admin", "pw")
+	if !flag || userID == 0 {
+		t.Fatal("login failed")
+	}
+	if flag, info, err := GetUserInfo(userID); !flag {
+		t.Fatal(info, err)
+	}
+	if _, err := database.GetConnection().Exec(`INSERT INTO goku_gateway_permission_group (groupName,permissions) VALUES (?,?);`, "ops", `{"apiManagement":{"read":true}}`); err != nil {
+		t.Fatal(err)
+	}
+	groups, err := GetPermissionGroupByName([]string{"ops", "none"})
+	if err != nil || len(groups) != 1 || groups[0].GroupID == 0 {
+		t.Fatalf("unexpected groups %v %v", groups, err)
+	}
+}
+
+func testInsertID(t *testing.T) {
+	flag, id, err := AddProject("p1")
+	if !flag || id.(int64) == 0 {
+		t.Fatal(id, err)
+	}
+	flag, groupID, err := AddAPIGroup("g1", int(id.(int64)), 0)
+	if !flag || groupID.(int64) == 0 {
+		t.Fatal(groupID, err)
+	}
+	flag, list, err := GetProjectList("p")
+	if !flag || len(list) != 1 || list[0].ProjectName != "p1" {
+		t.Fatal(list, err)
+	}
+}
+
+func testPage(t *testing.T) {
+	for _, op := range []string{"a", "b", "c"} {
+		if err := AddAuditLog(&entity.AuditLog{UserID: 1, Operation: op, OperateTime: "2019-10-17 00:00:00"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logs, count, err := GetAuditLogList(&entity.AuditLogCondition{}, 2, 2)
+	if err != nil || count != 3 || len(logs) != 1 || logs[0].Operation != "a" {
+		t.Fatal(logs, count, err)
+	}
+}
+
+func testReplace(t *testing.T) {
+	if err := SetNodeApplyStatus(1, &config.ApplyReport{Version: "v1", Status: config.ApplyOk}, "now"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetNodeApplyStatus(1, &config.ApplyReport{Version: "v2", Status: config.ApplyFailed, Error: "e"}, "now"); err != nil {
+		t.Fatal(err)
+	}
+	status, err := GetNodeApplyStatus()
+	if err != nil || len(status) != 1 {
+		t.Fatal(status, err)
+	}
+	if s := status[1]; s.Version != "v1" || s.FailedVersion != "v2" || s.Status != config.ApplyFailed {
+		t.Errorf("unexpected status %+v", s)
+	}
+}
+
+func testMonitor(t *testing.T) {
+	items := []*config.MonitorItem{
+		{StrategyID: "s1", APIID: 1, Hour: 3600, GatewayRequestCount: 2, GatewaySuccessCount: 1, RequestTime: 30, MaxRequestTime: 20},
+		{StrategyID: "s1", APIID: 2, Hour: 3600, GatewayRequestCount: 1, GatewaySuccessCount: 1, RequestTime: 50, MaxRequestTime: 50},
+	}
+	for i := 0; i < 2; i++ {
+		if err := AddMonitorRecords(1, items, nil, "now"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := AddMonitorRecords(1, []*config.MonitorItem{{StrategyID: "s1", APIID: 1, Hour: 7200, GatewayRequestCount: 1, RequestTime: 40, MaxRequestTime: 40}}, nil, "now"); err != nil {
+		t.Fatal(err)
+	}
+	count, err := GetMonitorSummary(&entity.MonitorCondition{BeginHour: 3600, EndHour: 7200})
+	if err != nil || count.GatewayRequestCount != 7 || count.GatewaySuccessCount != 4 || count.MaxRequestTime != 50 || count.AvgRequestTime != 28 {
+		t.Fatalf("unexpected summary %+v %v", count, err)
+	}
+	trend, err := GetMonitorTrend(&entity.MonitorCondition{APIID: 1})
+	if err != nil || len(trend) != 2 || trend[0].Hour != 3600 || trend[0].GatewayRequestCount != 4 {
+		t.Fatalf("unexpected trend %v %v", trend, err)
+	}
+	rank, err := GetMonitorAPIRank(&entity.MonitorCondition{}, MonitorOrderFailure, 1)
+	if err != nil || len(rank) != 1 || rank[0].APIID != 1 {
+		t.Fatalf("unexpected failure rank %v %v", rank, err)
+	}
+	rank, err = GetMonitorAPIRank(&entity.MonitorCondition{}, MonitorOrderSlowest, 10)
+	if err != nil || len(rank) != 2 || rank[0].APIID != 2 {
+		t.Fatalf("unexpected slowest rank %v %v", rank, err)
+	}
+}
+
+func testPluginPolicy(t *testing.T) {
+	_, err := database.GetConnection().Exec("INSERT INTO goku_plugin (pluginName,pluginStatus,pluginPriority,isStop,pluginType,official,version,isCheck) VALUES ('p1',1,1,0,1,'false','1.0',1);")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flag, _, err := EditPluginPolicy("p1", &entity.PluginPolicy{Timeout: 100, FailPolicy: "fallback", FailStatus: 200, FailBody: "{}"}); !flag {
+		t.Fatal(err)
+	}
+	if flag, _, _ := EditPluginPolicy("missing", &entity.PluginPolicy{}); flag {
+		t.Error("expect missing plugin to fail")
+	}
+	if flag, p, err := GetPluginInfo("p1"); !flag || p.Timeout != 100 || p.FailPolicy != "fallback" || p.FailBody != "{}" {
+		t.Fatalf("unexpected plugin %+v %v", p, err)
+	}
+
+	items := []*config.PluginMonitorItem{
+		{PluginName: "p1", Hour: 3600, CallCount: 2, ErrorCount: 1, CallTime: 300, MaxCallTime: 200},
+		{PluginName: "p2", Hour: 3600, CallCount: 1, TimeoutCount: 1, CallTime: 50, MaxCallTime: 50},
+	}
+	for i := 0; i < 2; i++ {
+		if err := AddMonitorRecords(1, nil, items, "now"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := GetMonitorPluginList(&entity.MonitorCondition{BeginHour: 3600, EndHour: 3600})
+	if err != nil || len(list) != 2 || list[0].PluginName != "p1" || list[0].CallCount != 4 || list[0].AvgCallTime != 150 || list[0].MaxCallTime != 200 || list[1].TimeoutCount != 2 {
+		t.Fatalf("unexpected plugin monitor %v %v", list, err)
+	}
+}
+
+func testAlert(t *testing.T) {
+	rule := &entity.AlertRule{RuleName: "r1", RuleType: "apiError", Threshold: 1, Period: 5, Cooldown: 5, Receivers: "a@b.c", Enable: true, CreateTime: "now", UpdateTime: "now"}
+	ruleID, err := AddAlertRule(rule)
+	if err != nil || ruleID == 0 {
+		t.Fatal(ruleID, err)
+	}
+	rule.RuleID, rule.Enable = ruleID, false
+	if flag, err := EditAlertRule(rule); !flag {
+		t.Fatal(err)
+	}
+	if rules, err := GetAlertRuleList(true); err != nil || len(rules) != 0 {
+		t.Fatalf("unexpected enabled rules %v %v", rules, err)
+	}
+	if flag, r, err := GetAlertRule(ruleID); !flag || r.Enable || r.Receivers != "a@b.c" {
+		t.Fatalf("unexpected rule %+v %v", r, err)
+	}
+	for _, alertTime := range []string{"2019-10-17 00:00:00", "2019-10-17 01:00:00"} {
+		if err := AddAlertHistory(&entity.AlertHistory{RuleID: ruleID, RuleName: "r1", RuleType: "apiError", Target: "api:1", Value: 1.5, Message: "m", AlertTime: alertTime}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if last, err := GetLastAlertTime(ruleID, "api:1"); err != nil || last != "2019-10-17 01:00:00" {
+		t.Errorf("unexpected last alert time %s %v", last, err)
+	}
+	list, count, err := GetAlertHistoryList(&entity.AlertHistoryCondition{RuleID: ruleID}, 1, 1)
+	if err != nil || count != 2 || len(list) != 1 || list[0].Value != 1.5 {
+		t.Fatalf("unexpected history %v %d %v", list, count, err)
+	}
+	if _, err := GetAlertSender(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testRollback(t *testing.T) {
+	ids := make([]int, 3)
+	for i := range ids {
+		id, err := AddVersionConfig("v", strconv.Itoa(i), "", "{}", "{}", "{}", "now")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = PublishVersion(id, 1, "now"); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = id
+	}
+	// 连续回滚依次回到更早的版本，而不是在最近两个版本之间切换
+	for _, expect := range []int{ids[1], ids[0]} {
+		previous, err := GetPreviousPublishVersionID(GetPublishVersionID())
+		if err != nil || previous != expect {
+			t.Fatalf("expect previous version %d, got %d %v", expect, previous, err)
+		}
+		if err = RollbackVersion(previous, 1, "now"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if previous, err := GetPreviousPublishVersionID(GetPublishVersionID()); err == nil {
+		t.Errorf("expect no previous version, got %d", previous)
+	}
+}
+
+func testCanary(t *testing.T) {
+	if _, err := database.GetConnection().Exec("INSERT INTO goku_conn_strategy_api (strategyID,apiID,updateTime) VALUES ('canary',1,'now');"); err != nil {
+		t.Fatal(err)
+	}
+	SetAPICanaryOfStrategy(1, "canary", `{"rules":`)
+	if _, err := dao_version_config.GetAPIsOfStrategy(); err == nil {
+		t.Error("expect illegal canary to fail the config build")
+	}
+	SetAPICanaryOfStrategy(1, "canary", "")
+	if apis, err := dao_version_config.GetAPIsOfStrategy(); err != nil || len(apis["canary"]) != 1 {
+		t.Errorf("unexpected strategy apis %v %v", apis, err)
+	}
+}
@@ -102,3 +102,26 @@ func GetGatewayInfo() (nodeStartCount, nodeStopCount, projectCount, apiCount, st
 	}
 	return
 }
+
+//SetTrustedProxies 设置可信代理列表
+func SetTrustedProxies(trustedProxies string) (bool, string, error) {
+	db := database2.GetConnection()
+	sql := "UPDATE goku_gateway SET trustedProxies = ? WHERE id = 1;"
+	_, err := db.Exec(sql, trustedProxies)
+	if err != nil {
+		return false, "[ERROR]Fail to excute SQL Statement!", err
+	}
+	return true, "", nil
+}
+
+//GetTrustedProxies 获取可信代理列表
+func GetTrustedProxies() (bool, string, error) {
+	db := database2.GetConnection()
+	sql := "SELECT IFNULL(trustedProxies,'') FROM goku_gateway WHERE id = 1;"
+	var trustedProxies string
+	err := db.QueryRow(sql).Scan(&trustedProxies)
+	if err != nil {
+		return false, "", err
+	}
+	return true, trustedProxies, nil
+}
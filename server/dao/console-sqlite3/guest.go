@@ -7,11 +7,11 @@ import (
 	"github.com/eolinker/goku-api-gateway/utils"
 )
 
-//Login 登录
+//Login 登录，仅限本地账号
 func Login(loginCall, loginPassword string) (bool, int) {
 	db := database.GetConnection()
 	var userID int
-	err := db.QueryRow("SELECT userID FROM goku_admin WHERE loginCall = ? AND loginPassword = ?;", loginCall, loginPassword).Scan(&userID)
+	err := db.QueryRow("SELECT userID FROM goku_admin WHERE loginCall = ? AND loginPassword = ? AND source = 'local';", loginCall, loginPassword).Scan(&userID)
 	if err != nil {
 		return false, 0
 	}
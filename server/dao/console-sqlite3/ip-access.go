@@ -0,0 +1,55 @@
+package console_sqlite3
+
+import (
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+)
+
+//SetStrategyIPAccess 设置策略IP黑白名单
+func SetStrategyIPAccess(strategyID, ipAccess string) (bool, string, error) {
+	db := database.GetConnection()
+	now := time.Now().Format("2006-01-02 15:04:05")
+	sql := "UPDATE goku_gateway_strategy SET `ipAccess` = ?,`updateTime` = ? WHERE strategyID = ?"
+	_, err := db.Exec(sql, ipAccess, now, strategyID)
+	if err != nil {
+		return false, "[ERROR]Failed to update data!", err
+	}
+	return true, "", nil
+}
+
+//GetStrategyIPAccess 获取策略IP黑白名单
+func GetStrategyIPAccess(strategyID string) (bool, string, error) {
+	db := database.GetConnection()
+	sql := "SELECT IFNULL(`ipAccess`,'') FROM goku_gateway_strategy WHERE strategyID = ?"
+	var ipAccess string
+	err := db.QueryRow(sql, strategyID).Scan(&ipAccess)
+	if err != nil {
+		return false, "", err
+	}
+	return true, ipAccess, nil
+}
+
+//SetAPIIPAccess 设置接口IP黑白名单
+func SetAPIIPAccess(apiID int, ipAccess string) (bool, string, error) {
+	db := database.GetConnection()
+	now := time.Now().Format("2006-01-02 15:04:05")
+	sql := "UPDATE goku_gateway_api SET `ipAccess` = ?,`updateTime` = ? WHERE apiID = ?"
+	_, err := db.Exec(sql, ipAccess, now, apiID)
+	if err != nil {
+		return false, "[ERROR]Failed to update data!", err
+	}
+	return true, "", nil
+}
+
+//GetAPIIPAccess 获取接口IP黑白名单
+func GetAPIIPAccess(apiID int) (bool, string, error) {
+	db := database.GetConnection()
+	sql := "SELECT IFNULL(`ipAccess`,'') FROM goku_gateway_api WHERE apiID = ?"
+	var ipAccess string
+	err := db.QueryRow(sql, apiID).Scan(&ipAccess)
+	if err != nil {
+		return false, "", err
+	}
+	return true, ipAccess, nil
+}
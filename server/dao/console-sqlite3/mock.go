@@ -0,0 +1,31 @@
+package console_sqlite3
+
+import (
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+)
+
+//SetAPIMock 设置接口mock配置
+func SetAPIMock(apiID int, mock string) (bool, string, error) {
+	db := database.GetConnection()
+	now := time.Now().Format("2006-01-02 15:04:05")
+	sql := "UPDATE goku_gateway_api SET `mock` = ?,`updateTime` = ? WHERE apiID = ?"
+	_, err := db.Exec(sql, mock, now, apiID)
+	if err != nil {
+		return false, "[ERROR]Failed to update data!", err
+	}
+	return true, "", nil
+}
+
+//GetAPIMock 获取接口mock配置
+func GetAPIMock(apiID int) (bool, string, error) {
+	db := database.GetConnection()
+	sql := "SELECT IFNULL(`mock`,'') FROM goku_gateway_api WHERE apiID = ?"
+	var mock string
+	err := db.QueryRow(sql, apiID).Scan(&mock)
+	if err != nil {
+		return false, "", err
+	}
+	return true, mock, nil
+}
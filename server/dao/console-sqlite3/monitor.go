@@ -0,0 +1,193 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+	"github.com/eolinker/goku-api-gateway/config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//MonitorOrderSlowest 按平均耗时排序
+	MonitorOrderSlowest = "slowest"
+	//MonitorOrderFailure 按失败次数排序
+	MonitorOrderFailure = "failure"
+)
+
+const monitorCountFields = "IFNULL(SUM(M.`gatewayRequestCount`),0),IFNULL(SUM(M.`gatewaySuccessCount`),0),IFNULL(SUM(M.`gatewayStatus2xxCount`),0),IFNULL(SUM(M.`gatewayStatus4xxCount`),0),IFNULL(SUM(M.`gatewayStatus5xxCount`),0),IFNULL(SUM(M.`proxyRequestCount`),0),IFNULL(SUM(M.`proxySuccessCount`),0),IFNULL(SUM(M.`proxyStatus2xxCount`),0),IFNULL(SUM(M.`proxyStatus4xxCount`),0),IFNULL(SUM(M.`proxyStatus5xxCount`),0),IFNULL(SUM(M.`proxyTimeoutCount`),0),IFNULL(SUM(M.`requestTime`),0),IFNULL(MAX(M.`maxRequestTime`),0)"
+
+//AddMonitorRecords 累加节点上报的监控计数及插件执行计数，在同一事务中写入，失败时均不写入以便节点整体重报
+func AddMonitorRecords(clusterID int, items []*config.MonitorItem, plugins []*config.PluginMonitorItem, now string) error {
+	db := database.GetConnection()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err = addMonitorRecords(tx, clusterID, items, now); err == nil {
+		err = addPluginMonitorRecords(tx, clusterID, plugins, now)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// addMonitorRecords 按集群、策略、接口、小时合并监控计数
+func addMonitorRecords(tx *SQL.Tx, clusterID int, items []*config.MonitorItem, now string) error {
+	// 以唯一索引monitorKey合并，多个节点同时上报同一小时的计数时不会因先查后插而冲突
+	sql := "INSERT INTO goku_monitor_cluster (`clusterID`,`strategyID`,`apiID`,`hour`,`gatewayRequestCount`,`gatewaySuccessCount`,`gatewayStatus2xxCount`,`gatewayStatus4xxCount`,`gatewayStatus5xxCount`,`proxyRequestCount`,`proxySuccessCount`,`proxyStatus2xxCount`,`proxyStatus4xxCount`,`proxyStatus5xxCount`,`proxyTimeoutCount`,`requestTime`,`maxRequestTime`,`updateTime`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?) ON CONFLICT (`clusterID`,`strategyID`,`apiID`,`hour`) DO UPDATE SET `gatewayRequestCount` = goku_monitor_cluster.`gatewayRequestCount` + excluded.`gatewayRequestCount`,`gatewaySuccessCount` = goku_monitor_cluster.`gatewaySuccessCount` + excluded.`gatewaySuccessCount`,`gatewayStatus2xxCount` = goku_monitor_cluster.`gatewayStatus2xxCount` + excluded.`gatewayStatus2xxCount`,`gatewayStatus4xxCount` = goku_monitor_cluster.`gatewayStatus4xxCount` + excluded.`gatewayStatus4xxCount`,`gatewayStatus5xxCount` = goku_monitor_cluster.`gatewayStatus5xxCount` + excluded.`gatewayStatus5xxCount`,`proxyRequestCount` = goku_monitor_cluster.`proxyRequestCount` + excluded.`proxyRequestCount`,`proxySuccessCount` = goku_monitor_cluster.`proxySuccessCount` + excluded.`proxySuccessCount`,`proxyStatus2xxCount` = goku_monitor_cluster.`proxyStatus2xxCount` + excluded.`proxyStatus2xxCount`,`proxyStatus4xxCount` = goku_monitor_cluster.`proxyStatus4xxCount` + excluded.`proxyStatus4xxCount`,`proxyStatus5xxCount` = goku_monitor_cluster.`proxyStatus5xxCount` + excluded.`proxyStatus5xxCount`,`proxyTimeoutCount` = goku_monitor_cluster.`proxyTimeoutCount` + excluded.`proxyTimeoutCount`,`requestTime` = goku_monitor_cluster.`requestTime` + excluded.`requestTime`,`maxRequestTime` = CASE WHEN goku_monitor_cluster.`maxRequestTime` < excluded.`maxRequestTime` THEN excluded.`maxRequestTime` ELSE goku_monitor_cluster.`maxRequestTime` END,`updateTime` = excluded.`updateTime`;"
+	for _, item := range items {
+		_, err := tx.Exec(sql, clusterID, item.StrategyID, item.APIID, item.Hour, item.GatewayRequestCount, item.GatewaySuccessCount, item.GatewayStatus2xxCount, item.GatewayStatus4xxCount, item.GatewayStatus5xxCount, item.ProxyRequestCount, item.ProxySuccessCount, item.ProxyStatus2xxCount, item.ProxyStatus4xxCount, item.ProxyStatus5xxCount, item.ProxyTimeoutCount, item.RequestTime, item.MaxRequestTime, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func monitorWhere(cond *entity.MonitorCondition) (string, []interface{}) {
+	where := make([]string, 0, 5)
+	args := make([]interface{}, 0, 5)
+	if cond.BeginHour > 0 {
+		where = append(where, "M.`hour` >= ?")
+		args = append(args, cond.BeginHour)
+	}
+	if cond.EndHour > 0 {
+		where = append(where, "M.`hour` <= ?")
+		args = append(args, cond.EndHour)
+	}
+	if cond.ClusterID > 0 {
+		where = append(where, "M.`clusterID` = ?")
+		args = append(args, cond.ClusterID)
+	}
+	if cond.StrategyID != "" {
+		where = append(where, "M.`strategyID` = ?")
+		args = append(args, cond.StrategyID)
+	}
+	if cond.APIID > 0 {
+		where = append(where, "M.`apiID` = ?")
+		args = append(args, cond.APIID)
+	}
+	if len(where) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(where, " AND "), args
+}
+
+func scanMonitorCount(rows *SQL.Rows, count *entity.MonitorCount, dest ...interface{}) error {
+	var requestTime int64
+	dest = append(dest, &count.GatewayRequestCount, &count.GatewaySuccessCount, &count.GatewayStatus2xxCount, &count.GatewayStatus4xxCount, &count.GatewayStatus5xxCount, &count.ProxyRequestCount, &count.ProxySuccessCount, &count.ProxyStatus2xxCount, &count.ProxyStatus4xxCount, &count.ProxyStatus5xxCount, &count.ProxyTimeoutCount, &requestTime, &count.MaxRequestTime)
+	if err := rows.Scan(dest...); err != nil {
+		return err
+	}
+	if count.GatewayRequestCount > 0 {
+		count.AvgRequestTime = requestTime / count.GatewayRequestCount
+	}
+	return nil
+}
+
+//GetMonitorSummary 获取监控计数汇总
+func GetMonitorSummary(cond *entity.MonitorCondition) (*entity.MonitorCount, error) {
+	where, args := monitorWhere(cond)
+	rows, err := database.GetConnection().Query("SELECT "+monitorCountFields+" FROM goku_monitor_cluster M"+where+";", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	count := new(entity.MonitorCount)
+	if rows.Next() {
+		if err = scanMonitorCount(rows, count); err != nil {
+			return nil, err
+		}
+	}
+	return count, rows.Err()
+}
+
+//GetMonitorTrend 获取每小时的监控计数
+func GetMonitorTrend(cond *entity.MonitorCondition) ([]*entity.MonitorTrend, error) {
+	where, args := monitorWhere(cond)
+	sql := "SELECT M.`hour`," + monitorCountFields + " FROM goku_monitor_cluster M" + where + " GROUP BY M.`hour` ORDER BY M.`hour` ASC;"
+	rows, err := database.GetConnection().Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	trend := make([]*entity.MonitorTrend, 0)
+	for rows.Next() {
+		t := new(entity.MonitorTrend)
+		if err = scanMonitorCount(rows, &t.MonitorCount, &t.Hour); err != nil {
+			return nil, err
+		}
+		trend = append(trend, t)
+	}
+	return trend, rows.Err()
+}
+
+//GetMonitorAPIRank 获取接口排行，orderBy为slowest时按平均耗时排序，为failure时按失败次数排序
+func GetMonitorAPIRank(cond *entity.MonitorCondition, orderBy string, limit int) ([]*entity.MonitorAPIRank, error) {
+	where, args := monitorWhere(cond)
+	if where == "" {
+		where = " WHERE M.`apiID` > 0"
+	} else {
+		where += " AND M.`apiID` > 0"
+	}
+	order := "SUM(M.`gatewayRequestCount`) - SUM(M.`gatewaySuccessCount`) DESC"
+	if orderBy == MonitorOrderSlowest {
+		order = "SUM(M.`requestTime`) * 1.0 / SUM(M.`gatewayRequestCount`) DESC"
+	}
+	sql := "SELECT M.`apiID`,IFNULL(A.`apiName`,'')," + monitorCountFields + " FROM goku_monitor_cluster M LEFT JOIN goku_gateway_api A ON M.`apiID` = A.`apiID`" + where + " GROUP BY M.`apiID`,A.`apiName` HAVING SUM(M.`gatewayRequestCount`) > 0 ORDER BY " + order + ",M.`apiID` ASC LIMIT ?;"
+	rows, err := database.GetConnection().Query(sql, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rank := make([]*entity.MonitorAPIRank, 0)
+	for rows.Next() {
+		r := new(entity.MonitorAPIRank)
+		if err = scanMonitorCount(rows, &r.MonitorCount, &r.APIID, &r.APIName); err != nil {
+			return nil, err
+		}
+		rank = append(rank, r)
+	}
+	return rank, rows.Err()
+}
+
+// addPluginMonitorRecords 按集群、插件、小时合并插件执行计数
+func addPluginMonitorRecords(tx *SQL.Tx, clusterID int, items []*config.PluginMonitorItem, now string) error {
+	// 以唯一索引monitorPluginKey合并
+	sql := "INSERT INTO goku_monitor_plugin (`clusterID`,`pluginName`,`hour`,`callCount`,`errorCount`,`panicCount`,`timeoutCount`,`callTime`,`maxCallTime`,`updateTime`) VALUES (?,?,?,?,?,?,?,?,?,?) ON CONFLICT (`clusterID`,`pluginName`,`hour`) DO UPDATE SET `callCount` = goku_monitor_plugin.`callCount` + excluded.`callCount`,`errorCount` = goku_monitor_plugin.`errorCount` + excluded.`errorCount`,`panicCount` = goku_monitor_plugin.`panicCount` + excluded.`panicCount`,`timeoutCount` = goku_monitor_plugin.`timeoutCount` + excluded.`timeoutCount`,`callTime` = goku_monitor_plugin.`callTime` + excluded.`callTime`,`maxCallTime` = CASE WHEN goku_monitor_plugin.`maxCallTime` < excluded.`maxCallTime` THEN excluded.`maxCallTime` ELSE goku_monitor_plugin.`maxCallTime` END,`updateTime` = excluded.`updateTime`;"
+	for _, item := range items {
+		_, err := tx.Exec(sql, clusterID, item.PluginName, item.Hour, item.CallCount, item.ErrorCount, item.PanicCount, item.TimeoutCount, item.CallTime, item.MaxCallTime, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//GetMonitorPluginList 获取插件执行计数，按失败次数及平均耗时排序，仅使用条件中的时间及集群
+func GetMonitorPluginList(cond *entity.MonitorCondition) ([]*entity.MonitorPlugin, error) {
+	where, args := monitorWhere(&entity.MonitorCondition{BeginHour: cond.BeginHour, EndHour: cond.EndHour, ClusterID: cond.ClusterID})
+	sql := "SELECT M.`pluginName`,SUM(M.`callCount`),SUM(M.`errorCount`),SUM(M.`panicCount`),SUM(M.`timeoutCount`),SUM(M.`callTime`),MAX(M.`maxCallTime`) FROM goku_monitor_plugin M" + where + " GROUP BY M.`pluginName` HAVING SUM(M.`callCount`) > 0 ORDER BY SUM(M.`errorCount`) + SUM(M.`panicCount`) + SUM(M.`timeoutCount`) DESC,SUM(M.`callTime`) * 1.0 / SUM(M.`callCount`) DESC,M.`pluginName` ASC;"
+	rows, err := database.GetConnection().Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.MonitorPlugin, 0)
+	for rows.Next() {
+		p := new(entity.MonitorPlugin)
+		var callTime int64
+		if err = rows.Scan(&p.PluginName, &p.CallCount, &p.ErrorCount, &p.PanicCount, &p.TimeoutCount, &callTime, &p.MaxCallTime); err != nil {
+			return nil, err
+		}
+		if p.CallCount > 0 {
+			p.AvgCallTime = callTime / p.CallCount
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
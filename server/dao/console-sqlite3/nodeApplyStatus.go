@@ -0,0 +1,49 @@
+package console_sqlite3
+
+import (
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+	"github.com/eolinker/goku-api-gateway/config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//SetNodeApplyStatus 更新节点配置应用状态，应用失败时保留上一次成功应用的版本
+func SetNodeApplyStatus(nodeID int, report *config.ApplyReport, now string) error {
+	db := database.GetConnection()
+	plugins, err := json.Marshal(report.Plugins)
+	if err != nil {
+		return err
+	}
+	if report.Status == config.ApplyOk {
+		sql := "REPLACE INTO goku_node_apply_status (`nodeID`,`version`,`status`,`error`,`failedVersion`,`plugins`,`reportTime`) VALUES (?,?,?,'','',?,?);"
+		_, err = db.Exec(sql, nodeID, report.Version, report.Status, string(plugins), now)
+		return err
+	}
+	sql := "REPLACE INTO goku_node_apply_status (`nodeID`,`version`,`status`,`error`,`failedVersion`,`plugins`,`reportTime`) VALUES (?,IFNULL((SELECT `version` FROM goku_node_apply_status WHERE `nodeID` = ?),''),?,?,?,?,?);"
+	_, err = db.Exec(sql, nodeID, nodeID, report.Status, report.Error, report.Version, string(plugins), now)
+	return err
+}
+
+//GetNodeApplyStatus 获取节点配置应用状态
+func GetNodeApplyStatus() (map[int]*entity.NodeApplyStatus, error) {
+	db := database.GetConnection()
+	sql := "SELECT `nodeID`,IFNULL(`version`,''),`status`,IFNULL(`error`,''),IFNULL(`failedVersion`,''),IFNULL(`plugins`,'[]'),IFNULL(`reportTime`,'') FROM goku_node_apply_status;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make(map[int]*entity.NodeApplyStatus)
+	for rows.Next() {
+		s := new(entity.NodeApplyStatus)
+		var plugins string
+		err = rows.Scan(&s.NodeID, &s.Version, &s.Status, &s.Error, &s.FailedVersion, &plugins, &s.ReportTime)
+		if err != nil {
+			return nil, err
+		}
+		json.Unmarshal([]byte(plugins), &s.Plugins)
+		result[s.NodeID] = s
+	}
+	return result, nil
+}
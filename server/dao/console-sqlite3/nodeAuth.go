@@ -0,0 +1,79 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const nodeSQLToken = "SELECT  A.`nodeID`, A.`nodeName`, A.`nodeIP`, A.`nodePort`, A.`updateTime`, A.`createTime`, A.`version`,   A.`gatewayPath`, A.`groupID`, IFNULL(G.`groupName`,''),  C.`name` As cluster, C.`title` As cluster_title  FROM goku_node_info A LEFT JOIN goku_node_group G ON A.`groupID` = G.`groupID` LEFT JOIN `goku_cluster` C ON A.`clusterID` = C.`id`WHERE A.`token` = ? ;"
+
+const clusterAuthSQL = "SELECT `id`,`name`,IFNULL(`token`,''),`autoRegister`,`registerGroupID` FROM goku_cluster"
+
+func getClusterAuth(sql string, args ...interface{}) (bool, *entity.ClusterAuth, error) {
+	db := database.GetConnection()
+	auth := new(entity.ClusterAuth)
+	var autoRegister int
+	err := db.QueryRow(sql, args...).Scan(&auth.ClusterID, &auth.Name, &auth.Token, &autoRegister, &auth.RegisterGroupID)
+	if err == SQL.ErrNoRows {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	auth.AutoRegister = autoRegister == 1
+	return true, auth, nil
+}
+
+//GetClusterAuth 获取集群节点接入认证配置
+func GetClusterAuth(name string) (bool, *entity.ClusterAuth, error) {
+	return getClusterAuth(clusterAuthSQL+" WHERE `name` = ?", name)
+}
+
+//GetClusterAuthByToken 通过集群凭证的摘要获取集群
+func GetClusterAuthByToken(hash string) (bool, *entity.ClusterAuth, error) {
+	return getClusterAuth(clusterAuthSQL+" WHERE `token` = ?", hash)
+}
+
+//SetClusterToken 设置集群凭证的摘要
+func SetClusterToken(clusterID int, hash string) error {
+	db := database.GetConnection()
+	_, err := db.Exec("UPDATE goku_cluster SET `token` = ? WHERE `id` = ?", hash, clusterID)
+	return err
+}
+
+//SetClusterRegister 设置集群节点自动注册
+func SetClusterRegister(clusterID int, autoRegister bool, groupID int) error {
+	db := database.GetConnection()
+	auto := 0
+	if autoRegister {
+		auto = 1
+	}
+	_, err := db.Exec("UPDATE goku_cluster SET `autoRegister` = ?,`registerGroupID` = ? WHERE `id` = ?", auto, groupID, clusterID)
+	return err
+}
+
+//GetNodeByToken 通过节点凭证的摘要获取节点信息
+func GetNodeByToken(hash string) (bool, *entity.Node, error) {
+	has, node, err := getNodeInfo(nodeSQLToken, hash)
+	if err == SQL.ErrNoRows {
+		return false, nil, nil
+	}
+	return has, node, err
+}
+
+//GetNodeToken 获取节点凭证的摘要
+func GetNodeToken(nodeID int) (string, error) {
+	db := database.GetConnection()
+	var token string
+	err := db.QueryRow("SELECT IFNULL(`token`,'') FROM goku_node_info WHERE `nodeID` = ?", nodeID).Scan(&token)
+	return token, err
+}
+
+//SetNodeToken 设置节点凭证的摘要
+func SetNodeToken(nodeID int, hash string) error {
+	db := database.GetConnection()
+	_, err := db.Exec("UPDATE goku_node_info SET `token` = ? WHERE `nodeID` = ?", hash, nodeID)
+	return err
+}
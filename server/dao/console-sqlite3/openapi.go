@@ -0,0 +1,156 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	database2 "github.com/eolinker/goku-api-gateway/common/database"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//ImportOpenAPI 导入OpenAPI文档中的接口，api.Group为parentGroupID下的分组名称，请求路径及方法已存在的接口将被跳过
+func ImportOpenAPI(projectID, parentGroupID, userID int, apis []*entity.DeclarativeAPI) (int, int, error) {
+	db := database2.GetConnection()
+	now := time.Now().Format("2006-01-02 15:04:05")
+	Tx, err := db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	exists, err := getProjectRoutes(Tx, projectID)
+	if err != nil {
+		Tx.Rollback()
+		return 0, 0, err
+	}
+	groupPath, groupDepth := "", 0
+	if parentGroupID > 0 {
+		err = Tx.QueryRow("SELECT IFNULL(groupPath,''),IFNULL(groupDepth,1) FROM goku_gateway_api_group WHERE groupID = ? AND projectID = ?;", parentGroupID, projectID).Scan(&groupPath, &groupDepth)
+		if err != nil {
+			Tx.Rollback()
+			return 0, 0, err
+		}
+	}
+	groups := make(map[string]int)
+	created, skipped := 0, 0
+	for _, api := range apis {
+		route := strings.ToUpper(api.RequestMethod) + " " + api.RequestURL
+		if exists[route] {
+			skipped++
+			continue
+		}
+		groupID := parentGroupID
+		if api.Group != "" {
+			id, has := groups[api.Group]
+			if !has {
+				id, err = getOrAddChildGroup(Tx, projectID, parentGroupID, groupPath, groupDepth, api.Group)
+				if err != nil {
+					Tx.Rollback()
+					return 0, 0, err
+				}
+				groups[api.Group] = id
+			}
+			groupID = id
+		}
+		_, err = Tx.Exec("INSERT INTO goku_gateway_api (projectID,groupID,apiName,requestURL,targetURL,requestMethod,targetMethod,isFollow,stripPrefix,stripSlash,timeout,retryCount,createTime,updateTime,protocol,balanceName,responseDataType,managerID,lastUpdateUserID,createUserID) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);", projectID, groupID, api.Name, api.RequestURL, api.TargetURL, api.RequestMethod, api.TargetMethod, strconv.FormatBool(api.IsFollow), strconv.FormatBool(api.StripPrefix), strconv.FormatBool(api.StripSlash), api.Timeout, api.RetryCount, now, now, api.Protocol, api.Balance, api.ResponseDataType, userID, userID, userID)
+		if err != nil {
+			Tx.Rollback()
+			return 0, 0, err
+		}
+		exists[route] = true
+		created++
+	}
+	_, err = Tx.Exec("UPDATE goku_gateway_project SET updateTime = ? WHERE projectID = ?;", now, projectID)
+	if err != nil {
+		Tx.Rollback()
+		return 0, 0, err
+	}
+	return created, skipped, Tx.Commit()
+}
+
+// getProjectRoutes 获取项目中已存在的请求方法及路径
+func getProjectRoutes(Tx *SQL.Tx, projectID int) (map[string]bool, error) {
+	rows, err := Tx.Query("SELECT requestURL,requestMethod FROM goku_gateway_api WHERE projectID = ?;", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	routes := make(map[string]bool)
+	for rows.Next() {
+		var requestURL, requestMethod string
+		if err = rows.Scan(&requestURL, &requestMethod); err != nil {
+			return nil, err
+		}
+		for _, method := range strings.Split(requestMethod, ",") {
+			routes[strings.ToUpper(strings.TrimSpace(method))+" "+requestURL] = true
+		}
+	}
+	return routes, rows.Err()
+}
+
+func getOrAddChildGroup(Tx *SQL.Tx, projectID, parentGroupID int, parentPath string, parentDepth int, groupName string) (int, error) {
+	var groupID int
+	err := Tx.QueryRow("SELECT groupID FROM goku_gateway_api_group WHERE projectID = ? AND parentGroupID = ? AND groupName = ?;", projectID, parentGroupID, groupName).Scan(&groupID)
+	if err == nil {
+		return groupID, nil
+	}
+	if err != SQL.ErrNoRows {
+		return 0, err
+	}
+	if parentDepth > 4 {
+		return 0, errors.New("[ERROR]Exceeding the grouping level!")
+	}
+	result, err := Tx.Exec("INSERT INTO goku_gateway_api_group (projectID,groupName,parentGroupID) VALUES (?,?,?);", projectID, groupName, parentGroupID)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	groupPath := strconv.Itoa(int(id))
+	if parentPath != "" {
+		groupPath = parentPath + "," + groupPath
+	}
+	_, err = Tx.Exec("UPDATE goku_gateway_api_group SET groupPath = ?,groupDepth = ? WHERE groupID = ?;", groupPath, parentDepth+1, id)
+	return int(id), err
+}
+
+//GetOpenAPIList 获取项目或策略下的接口，用于导出OpenAPI文档，返回项目或策略名称
+func GetOpenAPIList(projectID int, strategyID string) (string, []*entity.DeclarativeAPI, error) {
+	db := database2.GetConnection()
+	var title string
+	var err error
+	sql := "SELECT A.apiName,IFNULL(G.groupName,''),A.requestURL,A.requestMethod,IFNULL(A.protocol,''),IFNULL(A.balanceName,''),IFNULL(A.targetURL,''),IFNULL(A.targetMethod,''),A.isFollow FROM goku_gateway_api A LEFT JOIN goku_gateway_api_group G ON A.groupID = G.groupID"
+	var args []interface{}
+	if strategyID != "" {
+		err = db.QueryRow("SELECT strategyName FROM goku_gateway_strategy WHERE strategyID = ?;", strategyID).Scan(&title)
+		sql += " INNER JOIN goku_conn_strategy_api S ON A.apiID = S.apiID WHERE S.strategyID = ?"
+		args = append(args, strategyID)
+	} else {
+		err = db.QueryRow("SELECT projectName FROM goku_gateway_project WHERE projectID = ?;", projectID).Scan(&title)
+		sql += " WHERE A.projectID = ?"
+		args = append(args, projectID)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	rows, err := db.Query(sql+" ORDER BY A.requestURL,A.apiID;", args...)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rows.Close()
+	apis := make([]*entity.DeclarativeAPI, 0)
+	for rows.Next() {
+		api := new(entity.DeclarativeAPI)
+		var isFollow string
+		err = rows.Scan(&api.Name, &api.Group, &api.RequestURL, &api.RequestMethod, &api.Protocol, &api.Balance, &api.TargetURL, &api.TargetMethod, &isFollow)
+		if err != nil {
+			return "", nil, err
+		}
+		api.IsFollow = isFollow == "true"
+		apis = append(apis, api)
+	}
+	return title, apis, rows.Err()
+}
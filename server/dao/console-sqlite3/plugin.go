@@ -15,9 +15,9 @@ import (
 //GetPluginInfo 获取插件配置信息
 func GetPluginInfo(pluginName string) (bool, *entity.Plugin, error) {
 	db := database2.GetConnection()
-	sql := `SELECT pluginID,pluginName,pluginStatus,IFNULL(pluginConfig,""),pluginPriority,isStop,IFNULL(pluginDesc,""),IFNULL(version,""),pluginType FROM goku_plugin WHERE pluginName = ?;`
+	sql := `SELECT pluginID,pluginName,pluginStatus,IFNULL(pluginConfig,""),pluginPriority,isStop,IFNULL(pluginDesc,""),IFNULL(version,""),pluginType,pluginTimeout,failPolicy,failStatus,failBody FROM goku_plugin WHERE pluginName = ?;`
 	plugin := &entity.Plugin{}
-	err := db.QueryRow(sql, pluginName).Scan(&plugin.PluginID, &plugin.PluginName, &plugin.PluginStatus, &plugin.PluginConfig, &plugin.PluginIndex, &plugin.IsStop, &plugin.PluginDesc, &plugin.Version, &plugin.PluginType)
+	err := db.QueryRow(sql, pluginName).Scan(&plugin.PluginID, &plugin.PluginName, &plugin.PluginStatus, &plugin.PluginConfig, &plugin.PluginIndex, &plugin.IsStop, &plugin.PluginDesc, &plugin.Version, &plugin.PluginType, &plugin.Timeout, &plugin.FailPolicy, &plugin.FailStatus, &plugin.FailBody)
 	if err != nil {
 		return false, &entity.Plugin{}, err
 	}
@@ -125,6 +125,19 @@ func EditPlugin(pluginName, pluginConfig, pluginDesc, version string, pluginPrio
 	return true, "", nil
 }
 
+//EditPluginPolicy 修改插件执行超时时间及失败策略
+func EditPluginPolicy(pluginName string, policy *entity.PluginPolicy) (bool, string, error) {
+	db := database2.GetConnection()
+	r, err := db.Exec("UPDATE goku_plugin SET pluginTimeout = ?,failPolicy = ?,failStatus = ?,failBody = ? WHERE pluginName = ?;", policy.Timeout, policy.FailPolicy, policy.FailStatus, policy.FailBody, pluginName)
+	if err != nil {
+		return false, "[ERROR]Failed to update data!", err
+	}
+	if affected, _ := r.RowsAffected(); affected == 0 {
+		return false, "[ERROR]The plugin is not exist!", nil
+	}
+	return true, "", nil
+}
+
 // DeletePlugin 删除插件信息
 func DeletePlugin(pluginName string) (bool, string, error) {
 	db := database2.GetConnection()
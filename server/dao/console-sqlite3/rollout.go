@@ -0,0 +1,131 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const rolloutSQL = "SELECT `id`,`versionID`,`baseVersionID`,`targetType`,IFNULL(`target`,'{}'),`autoAbort`,`failureThreshold`,`applyTimeout`,`status`,IFNULL(`reason`,''),`operatorID`,IFNULL(`createTime`,''),IFNULL(`updateTime`,'') FROM goku_gateway_version_rollout"
+
+//AddRollout 新增灰度发布
+func AddRollout(r *entity.Rollout) (int, error) {
+	db := database.GetConnection()
+	target, err := json.Marshal(r.Target)
+	if err != nil {
+		return 0, err
+	}
+	autoAbort := 0
+	if r.AutoAbort {
+		autoAbort = 1
+	}
+	sql := "INSERT INTO goku_gateway_version_rollout (`versionID`,`baseVersionID`,`targetType`,`target`,`autoAbort`,`failureThreshold`,`applyTimeout`,`status`,`operatorID`,`createTime`,`updateTime`) VALUES (?,?,?,?,?,?,?,?,?,?,?)"
+	result, err := db.Exec(sql, r.VersionID, r.BaseVersionID, r.TargetType, string(target), autoAbort, r.FailureThreshold, r.ApplyTimeout, entity.RolloutRunning, r.OperatorID, r.CreateTime, r.CreateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+func scanRollout(row interface {
+	Scan(dest ...interface{}) error
+}) (*entity.Rollout, error) {
+	r := new(entity.Rollout)
+	var target string
+	var autoAbort int
+	err := row.Scan(&r.ID, &r.VersionID, &r.BaseVersionID, &r.TargetType, &target, &autoAbort, &r.FailureThreshold, &r.ApplyTimeout, &r.Status, &r.Reason, &r.OperatorID, &r.CreateTime, &r.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	r.AutoAbort = autoAbort == 1
+	json.Unmarshal([]byte(target), &r.Target)
+	return r, nil
+}
+
+//GetRunningRollout 获取进行中的灰度发布
+func GetRunningRollout() (bool, *entity.Rollout, error) {
+	db := database.GetConnection()
+	r, err := scanRollout(db.QueryRow(rolloutSQL+" WHERE `status` = ? ORDER BY `id` DESC LIMIT 1", entity.RolloutRunning))
+	if err == SQL.ErrNoRows {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	return true, r, nil
+}
+
+//GetRolloutList 获取灰度发布记录
+func GetRolloutList() ([]*entity.Rollout, error) {
+	db := database.GetConnection()
+	rows, err := db.Query(rolloutSQL + " ORDER BY `id` DESC")
+	if err != nil {
+		return make([]*entity.Rollout, 0), err
+	}
+	defer rows.Close()
+	list := make([]*entity.Rollout, 0, 10)
+	for rows.Next() {
+		r, err := scanRollout(rows)
+		if err != nil {
+			return list, err
+		}
+		list = append(list, r)
+	}
+	return list, nil
+}
+
+//AbortRollout 终止灰度发布
+func AbortRollout(id int, reason, now string) error {
+	db := database.GetConnection()
+	sql := "UPDATE goku_gateway_version_rollout SET `status` = ?,`reason` = ?,`updateTime` = ? WHERE `id` = ? AND `status` = ?"
+	_, err := db.Exec(sql, entity.RolloutAborted, reason, now, id, entity.RolloutRunning)
+	return err
+}
+
+//PromoteRollout 灰度版本全量发布
+func PromoteRollout(id, versionID, userID int, now string) error {
+	db := database.GetConnection()
+	Tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	sql := "UPDATE goku_gateway_version_rollout SET `status` = ?,`updateTime` = ? WHERE `id` = ? AND `status` = ?"
+	_, err = Tx.Exec(sql, entity.RolloutPromoted, now, id, entity.RolloutRunning)
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	err = publishVersionTx(Tx, versionID, userID, now, "promote")
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	return Tx.Commit()
+}
+
+//GetAllNodes 获取所有节点
+func GetAllNodes() ([]*entity.Node, error) {
+	db := database.GetConnection()
+	sql := "SELECT A.`nodeID`,A.`nodeName`,A.`nodeIP`,A.`nodePort`,A.`groupID`,IFNULL(C.`name`,'') FROM goku_node_info A LEFT JOIN goku_cluster C ON A.`clusterID` = C.`id` ORDER BY A.`nodeID`"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	nodes := make([]*entity.Node, 0, 10)
+	for rows.Next() {
+		node := new(entity.Node)
+		err = rows.Scan(&node.NodeID, &node.NodeName, &node.NodeIP, &node.NodePort, &node.GroupID, &node.Cluster)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,67 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//GetPermissionGroupByName 根据分组名称获取权限分组
+func GetPermissionGroupByName(groupNames []string) ([]*entity.PermissionGroup, error) {
+	groups := make([]*entity.PermissionGroup, 0, len(groupNames))
+	if len(groupNames) == 0 {
+		return groups, nil
+	}
+	db := database.GetConnection()
+	args := make([]interface{}, 0, len(groupNames))
+	for _, name := range groupNames {
+		args = append(args, name)
+	}
+	sql := `SELECT groupID,groupName,IFNULL(permissions,"") FROM goku_gateway_permission_group WHERE groupName IN (` + strings.TrimSuffix(strings.Repeat("?,", len(args)), ",") + `) ORDER BY groupID ASC;`
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		group := new(entity.PermissionGroup)
+		if err := rows.Scan(&group.GroupID, &group.GroupName, &group.Permissions); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+//GetUserByLoginCall 根据登录名获取用户ID、密码及来源，用户不存在时userID为0
+func GetUserByLoginCall(loginCall string) (int, string, string, error) {
+	db := database.GetConnection()
+	var userID int
+	var loginPassword, source string
+	err := db.QueryRow("SELECT userID,loginPassword,source FROM goku_admin WHERE loginCall = ?;", loginCall).Scan(&userID, &loginPassword, &source)
+	if err == SQL.ErrNoRows {
+		return 0, "", "", nil
+	}
+	return userID, loginPassword, source, err
+}
+
+//AddSSOUser 新增单点登录用户
+func AddSSOUser(loginCall, loginPassword, source string, groupID int, permissions string) (int, error) {
+	db := database.GetConnection()
+	sql := "INSERT INTO goku_admin (loginCall,loginPassword,userType,groupID,permissions,source) VALUES (?,?,2,?,?,?);"
+	r, err := db.Exec(sql, loginCall, loginPassword, groupID, permissions, source)
+	if err != nil {
+		return 0, err
+	}
+	userID, err := r.LastInsertId()
+	return int(userID), err
+}
+
+//UpdateSSOUser 同步单点登录用户的权限分组
+func UpdateSSOUser(userID, groupID int, permissions string) error {
+	db := database.GetConnection()
+	_, err := db.Exec("UPDATE goku_admin SET groupID = ?,permissions = ? WHERE userID = ?;", groupID, permissions, userID)
+	return err
+}
@@ -323,11 +323,16 @@ func GetBalanceListInStrategy(strategyID string, balanceType int) (bool, []strin
 func CopyStrategy(strategyID string, newStrategyID string, userID int) (string, error) {
 	db := database2.GetConnection()
 	now := time.Now().Format("2006-01-02 15:04:05")
-	sql := "INSERT INTO goku_conn_strategy_api (strategyID,apiID,apiMonitorStatus,strategyMonitorStatus,target,updateTime) SELECT ?,apiID,apiMonitorStatus,strategyMonitorStatus,target,? FROM goku_conn_strategy_api WHERE strategyID = ?"
+	sql := "INSERT INTO goku_conn_strategy_api (strategyID,apiID,apiMonitorStatus,strategyMonitorStatus,target,canary,updateTime) SELECT ?,apiID,apiMonitorStatus,strategyMonitorStatus,target,canary,? FROM goku_conn_strategy_api WHERE strategyID = ?"
 	_, err := db.Exec(sql, newStrategyID, now, strategyID)
 	if err != nil {
 		return "", err
 	}
+	sql = "UPDATE goku_gateway_strategy SET (cors,ipAccess) = (SELECT cors,ipAccess FROM goku_gateway_strategy WHERE strategyID = ?) WHERE strategyID = ?"
+	_, err = db.Exec(sql, strategyID, newStrategyID)
+	if err != nil {
+		return "", err
+	}
 	sql = "INSERT INTO goku_conn_plugin_strategy (strategyID,pluginName,pluginConfig,pluginInfo,createTime,updateTime,pluginStatus,updateTag,updaterID) SELECT ?,pluginName,pluginConfig,pluginInfo,?,?,pluginStatus,updateTag,? FROM goku_conn_plugin_strategy WHERE strategyID = ?"
 	_, err = db.Exec(sql, newStrategyID, now, now, userID, strategyID)
 	if err != nil {
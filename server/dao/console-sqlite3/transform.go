@@ -0,0 +1,31 @@
+package console_sqlite3
+
+import (
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/database"
+)
+
+//SetAPITransform 设置接口转换规则
+func SetAPITransform(apiID int, transform string) (bool, string, error) {
+	db := database.GetConnection()
+	now := time.Now().Format("2006-01-02 15:04:05")
+	sql := "UPDATE goku_gateway_api SET `transform` = ?,`updateTime` = ? WHERE apiID = ?"
+	_, err := db.Exec(sql, transform, now, apiID)
+	if err != nil {
+		return false, "[ERROR]Failed to update data!", err
+	}
+	return true, "", nil
+}
+
+//GetAPITransform 获取接口转换规则
+func GetAPITransform(apiID int) (bool, string, error) {
+	db := database.GetConnection()
+	sql := "SELECT IFNULL(`transform`,'') FROM goku_gateway_api WHERE apiID = ?"
+	var transform string
+	err := db.QueryRow(sql, apiID).Scan(&transform)
+	if err != nil {
+		return false, "", err
+	}
+	return true, transform, nil
+}
@@ -14,11 +14,18 @@ type permissionsJSON map[string]bool
 func EditPassword(oldPassword, newPassword string, userID int) (bool, string, error) {
 	db := database2.GetConnection()
 	// 查询旧密码是否存在
-	var loginCall, password string
+	var loginCall, password, source string
+	err := db.QueryRow("SELECT source FROM goku_admin WHERE userID = ?;", userID).Scan(&source)
+	if err != nil {
+		return false, "[ERROR]This user does not exist!", err
+	}
+	if source != "local" {
+		return false, "[ERROR]Password of single sign-on user can not be edited!", errors.New("[ERROR]Password of single sign-on user can not be edited")
+	}
 	oldPassword = utils.Md5(oldPassword)
 	newPassword = utils.Md5(newPassword)
 	sql := "SELECT loginCall,loginPassword FROM goku_admin WHERE loginPassword = ? AND userID = ?;"
-	err := db.QueryRow(sql, oldPassword, userID).Scan(&loginCall, &password)
+	err = db.QueryRow(sql, oldPassword, userID).Scan(&loginCall, &password)
 	if err != nil {
 		return false, "[ERROR]Old password error!", err
 	}
@@ -43,10 +50,10 @@ func EditPassword(oldPassword, newPassword string, userID int) (bool, string, er
 //GetUserInfo 获取账户信息
 func GetUserInfo(userID int) (bool, interface{}, error) {
 	db := database2.GetConnection()
-	sql := `SELECT loginCall,IFNULL(remark,""),IFNULL(permissions,""),userType FROM goku_admin WHERE userID = ?;`
-	var loginCall, remark, permissions string
+	sql := `SELECT loginCall,IFNULL(remark,""),IFNULL(permissions,""),userType,source FROM goku_admin WHERE userID = ?;`
+	var loginCall, remark, permissions, source string
 	var userType int
-	err := db.QueryRow(sql, userID).Scan(&loginCall, &remark, &permissions, &userType)
+	err := db.QueryRow(sql, userID).Scan(&loginCall, &remark, &permissions, &userType, &source)
 	if err != nil {
 		return false, "[ERROR]This user does not exist!", err
 	}
@@ -64,6 +71,7 @@ func GetUserInfo(userID int) (bool, interface{}, error) {
 		"remark":     remark,
 		"permission": perssionMap,
 		"userType":   userType,
+		"source":     source,
 	}
 	return true, userInfo, nil
 }
@@ -18,7 +18,7 @@ func getCountSQL(sql string, args ...interface{}) int {
 }
 
 func getPageSQL(sql string, orderBy, orderType string, page, pageSize int, args ...interface{}) (*SQL.Rows, error) {
-	pageSQL := fmt.Sprintf("%s ORDER BY %s %s LIMIT ?,?", sql, orderBy, orderType)
-	args = append(args, (page-1)*pageSize, pageSize)
+	pageSQL := fmt.Sprintf("%s ORDER BY %s %s LIMIT ? OFFSET ?", sql, orderBy, orderType)
+	args = append(args, pageSize, (page-1)*pageSize)
 	return database.GetConnection().Query(pageSQL, args...)
 }
@@ -1,6 +1,7 @@
 package console_sqlite3
 
 import (
+	SQL "database/sql"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -79,19 +80,120 @@ func BatchDeleteVersionConfig(ids []int, publishID int) error {
 }
 
 //PublishVersion 发布版本
-func PublishVersion(id int, now string) error {
+func PublishVersion(id, userID int, now string) error {
+	return publishVersion(id, userID, now, "publish")
+}
+
+//RollbackVersion 回滚到指定版本
+func RollbackVersion(id, userID int, now string) error {
+	return publishVersion(id, userID, now, "rollback")
+}
+
+func publishVersion(id, userID int, now, action string) error {
 	db := database.GetConnection()
+	Tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	err = publishVersionTx(Tx, id, userID, now, action)
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	return Tx.Commit()
+}
+
+func publishVersionTx(Tx *SQL.Tx, id, userID int, now, action string) error {
 	sql := "UPDATE goku_gateway SET versionID = ?"
-	_, err := db.Exec(sql, id)
+	_, err := Tx.Exec(sql, id)
 	if err != nil {
 		return err
 	}
 	sql = "UPDATE goku_gateway_version_config SET publishTime = ? WHERE versionID = ?"
-	_, err = db.Exec(sql, now, id)
+	_, err = Tx.Exec(sql, now, id)
 	if err != nil {
 		return err
 	}
-	return nil
+	sql = "INSERT INTO goku_gateway_version_publish (`versionID`,`action`,`operatorID`,`publishTime`) VALUES (?,?,?,?)"
+	_, err = Tx.Exec(sql, id, action, userID, now)
+	return err
+}
+
+//GetPreviousPublishVersionID 获取回滚的目标版本：按顺序重放发布记录，回滚撤销目标版本之后的发布，取当前版本之前仍存在的版本，连续回滚时逐个向前
+func GetPreviousPublishVersionID(currentID int) (int, error) {
+	db := database.GetConnection()
+	sql := "SELECT P.versionID,P.action,IFNULL(V.versionID,0) FROM goku_gateway_version_publish P LEFT JOIN goku_gateway_version_config V ON P.versionID = V.versionID ORDER BY P.id ASC"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	published := make([]int, 0, 10)
+	exists := make(map[int]bool)
+	for rows.Next() {
+		var id, existID int
+		var action string
+		if err = rows.Scan(&id, &action, &existID); err != nil {
+			return 0, err
+		}
+		exists[id] = existID != 0
+		if action == "rollback" {
+			if i := lastIndexOf(published, id); i >= 0 {
+				published = published[:i+1]
+				continue
+			}
+		}
+		published = append(published, id)
+	}
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+	for i := len(published) - 1; i >= 0; i-- {
+		if id := published[i]; id != currentID && exists[id] {
+			return id, nil
+		}
+	}
+	return 0, SQL.ErrNoRows
+}
+
+func lastIndexOf(ids []int, id int) int {
+	for i := len(ids) - 1; i >= 0; i-- {
+		if ids[i] == id {
+			return i
+		}
+	}
+	return -1
+}
+
+//GetPublishHistory 获取发布记录
+func GetPublishHistory() ([]map[string]interface{}, error) {
+	db := database.GetConnection()
+	sql := "SELECT P.id,P.versionID,IFNULL(V.name,''),IFNULL(V.version,''),P.action,P.operatorID,IFNULL(A.remark,IFNULL(A.loginCall,'')),P.publishTime FROM goku_gateway_version_publish P LEFT JOIN goku_gateway_version_config V ON P.versionID = V.versionID LEFT JOIN goku_admin A ON P.operatorID = A.userID ORDER BY P.id DESC"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return make([]map[string]interface{}, 0), err
+	}
+	defer rows.Close()
+	history := make([]map[string]interface{}, 0, 10)
+	for rows.Next() {
+		var id, versionID, operatorID int
+		var name, version, action, operator, publishTime string
+		err = rows.Scan(&id, &versionID, &name, &version, &action, &operatorID, &operator, &publishTime)
+		if err != nil {
+			return history, err
+		}
+		history = append(history, map[string]interface{}{
+			"id":           id,
+			"versionID":    versionID,
+			"name":         name,
+			"version":      version,
+			"action":       action,
+			"operatorID":   operatorID,
+			"operatorName": operator,
+			"publishTime":  publishTime,
+		})
+	}
+	return history, nil
 }
 
 //GetVersionConfigCount 获取版本配置数量
@@ -120,11 +222,21 @@ func GetPublishVersionID() int {
 
 //GetVersionConfig 获取当前版本配置
 func GetVersionConfig() (*config.GokuConfig, map[string]map[string]*config.BalanceConfig, map[string]map[string]*config.DiscoverConfig, error) {
-	db := database.GetConnection()
 	sql := "SELECT IFNULL(goku_gateway_version_config.config,'{}'),IFNULL(goku_gateway_version_config.balanceConfig,'{}'),IFNULL(goku_gateway_version_config.discoverConfig,'{}') FROM goku_gateway_version_config INNER JOIN goku_gateway ON goku_gateway.versionID = goku_gateway_version_config.versionID"
+	return getVersionConfig(sql)
+}
+
+//GetVersionConfigByID 根据版本ID获取版本配置
+func GetVersionConfigByID(id int) (*config.GokuConfig, map[string]map[string]*config.BalanceConfig, map[string]map[string]*config.DiscoverConfig, error) {
+	sql := "SELECT IFNULL(config,'{}'),IFNULL(balanceConfig,'{}'),IFNULL(discoverConfig,'{}') FROM goku_gateway_version_config WHERE versionID = ?"
+	return getVersionConfig(sql, id)
+}
+
+func getVersionConfig(sql string, args ...interface{}) (*config.GokuConfig, map[string]map[string]*config.BalanceConfig, map[string]map[string]*config.DiscoverConfig, error) {
+	db := database.GetConnection()
 	var cf, bf, df string
 
-	err := db.QueryRow(sql).Scan(&cf, &bf, &df)
+	err := db.QueryRow(sql, args...).Scan(&cf, &bf, &df)
 	if err != nil {
 		return nil, nil, nil, err
 	}
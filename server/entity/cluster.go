@@ -28,6 +28,7 @@ type ClusterDB struct {
 	Password string `json:"password" yaml:"password"`
 	Database string `json:"database" yaml:"database"`
 	Path     string `json:"path" yaml:"path"`
+	SSLMode  string `json:"sslMode" yaml:"sslMode"`
 }
 
 //CLusterRedis 集群redis配置
@@ -0,0 +1,60 @@
+package entity
+
+//AlertRule 告警规则
+type AlertRule struct {
+	RuleID   int    `json:"ruleID"`
+	RuleName string `json:"ruleName"`
+	//RuleType 规则类型：apiError、status5xxRate、proxyTimeout、nodeLost
+	RuleType string `json:"ruleType"`
+	//ClusterID 为0表示全部集群
+	ClusterID   int    `json:"clusterID"`
+	ClusterName string `json:"cluster"`
+	StrategyID  string `json:"strategyID"`
+	APIID       int    `json:"apiID"`
+	//Threshold 阈值，status5xxRate为百分比，其余为次数，nodeLost不使用
+	Threshold int `json:"threshold"`
+	//Period 统计周期，单位分钟，nodeLost为失联时长
+	Period int `json:"period"`
+	//Cooldown 同一目标重复通知的间隔，单位分钟
+	Cooldown        int    `json:"cooldown"`
+	Receivers       string `json:"receivers"`
+	WebhookURL      string `json:"webhookURL"`
+	WebhookTemplate string `json:"webhookTemplate"`
+	Enable          bool   `json:"enable"`
+	CreateTime      string `json:"createTime"`
+	UpdateTime      string `json:"updateTime"`
+}
+
+//AlertHistory 告警记录
+type AlertHistory struct {
+	AlertID   int     `json:"alertID"`
+	RuleID    int     `json:"ruleID"`
+	RuleName  string  `json:"ruleName"`
+	RuleType  string  `json:"ruleType"`
+	Target    string  `json:"target"`
+	Value     float64 `json:"value"`
+	Threshold int     `json:"threshold"`
+	Message   string  `json:"message"`
+	//NotifyStatus 1表示全部通知发送成功
+	NotifyStatus int    `json:"notifyStatus"`
+	NotifyError  string `json:"notifyError"`
+	AlertTime    string `json:"alertTime"`
+}
+
+//AlertHistoryCondition 告警记录查询条件，零值表示不限制
+type AlertHistoryCondition struct {
+	RuleID    int
+	RuleType  string
+	BeginTime string
+	EndTime   string
+}
+
+//AlertSender 告警邮件发送配置
+type AlertSender struct {
+	AlertStatus    int    `json:"alertStatus"`
+	Sender         string `json:"sender"`
+	SenderPassword string `json:"senderPassword,omitempty"`
+	SMTPAddress    string `json:"smtpAddress"`
+	SMTPPort       int    `json:"smtpPort"`
+	SMTPProtocol   int    `json:"smtpProtocol"`
+}
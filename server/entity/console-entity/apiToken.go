@@ -0,0 +1,20 @@
+package entity
+
+const (
+	//TokenPersonal 个人令牌，权限为令牌范围与用户权限的交集
+	TokenPersonal = "personal"
+	//TokenService 服务令牌，仅由管理员创建，权限为令牌范围
+	TokenService = "service"
+)
+
+//APIToken 控制台接口访问令牌
+type APIToken struct {
+	TokenID      int      `json:"tokenID"`
+	TokenName    string   `json:"tokenName"`
+	TokenType    string   `json:"tokenType"`
+	UserID       int      `json:"userID"`
+	Scopes       []string `json:"scopes"` // operationType:read|edit，edit包含read
+	ExpireTime   string   `json:"expireTime"`
+	LastUsedTime string   `json:"lastUsedTime"`
+	CreateTime   string   `json:"createTime"`
+}
@@ -0,0 +1,31 @@
+package entity
+
+//AuditLog 控制台操作审计日志
+type AuditLog struct {
+	LogID        int    `json:"logID"`
+	UserID       int    `json:"userID"`
+	LoginCall    string `json:"loginCall"`
+	RemoteAddr   string `json:"remoteAddr"`
+	ForwardedFor string `json:"forwardedFor"`
+	Method       string `json:"method"`
+	Operation    string `json:"operation"`
+	TargetType   string `json:"targetType"`
+	TargetID     string `json:"targetID"`
+	RequestData  string `json:"requestData"`
+	BeforeData   string `json:"beforeData"`
+	AfterData    string `json:"afterData"`
+	StatusCode   string `json:"statusCode"`
+	ResultDesc   string `json:"resultDesc"`
+	OperateTime  string `json:"operateTime"`
+}
+
+//AuditLogCondition 审计日志查询条件，零值表示不限制
+type AuditLogCondition struct {
+	UserID     int
+	TargetType string
+	TargetID   string
+	Keyword    string
+	RemoteAddr string
+	BeginTime  string
+	EndTime    string
+}
@@ -8,3 +8,13 @@ type Cluster struct {
 	Note      string `json:"note"`
 	NodeCount int    `json:"nodeCount"`
 }
+
+//ClusterAuth 集群节点接入认证配置
+type ClusterAuth struct {
+	ClusterID       int    `json:"clusterID"`
+	Name            string `json:"name"`
+	Token           string `json:"-"`               // 凭证的摘要，明文仅在重新生成时返回一次
+	HasToken        bool   `json:"hasToken"`        // 是否已设置集群凭证
+	AutoRegister    bool   `json:"autoRegister"`    // 是否允许持有集群凭证的节点首次接入时自动注册
+	RegisterGroupID int    `json:"registerGroupID"` // 自动注册的节点所属分组
+}
@@ -0,0 +1,112 @@
+package entity
+
+const (
+	//DeclarativeCreate 新增
+	DeclarativeCreate = "create"
+	//DeclarativeUpdate 修改
+	DeclarativeUpdate = "update"
+	//DeclarativeDelete 删除
+	DeclarativeDelete = "delete"
+)
+
+// Declarative 声明式网关配置，按名称/ID关联，可重复导入
+type Declarative struct {
+	Projects   []*DeclarativeProject  `json:"projects,omitempty" yaml:"projects,omitempty"`
+	Strategies []*DeclarativeStrategy `json:"strategies,omitempty" yaml:"strategies,omitempty"`
+	Balances   []*DeclarativeBalance  `json:"balances,omitempty" yaml:"balances,omitempty"`
+	Services   []*DeclarativeService  `json:"services,omitempty" yaml:"services,omitempty"`
+}
+
+// DeclarativeProject 项目
+type DeclarativeProject struct {
+	Name   string            `json:"name" yaml:"name"`
+	Groups []string          `json:"groups,omitempty" yaml:"groups,omitempty"` // 接口分组路径，以 / 分隔
+	APIs   []*DeclarativeAPI `json:"apis,omitempty" yaml:"apis,omitempty"`
+}
+
+// DeclarativeAPI 接口，以项目及接口名称唯一确定
+type DeclarativeAPI struct {
+	Name             string      `json:"name" yaml:"name"`
+	Group            string      `json:"group,omitempty" yaml:"group,omitempty"`
+	RequestURL       string      `json:"requestURL" yaml:"requestURL"`
+	RequestMethod    string      `json:"requestMethod" yaml:"requestMethod"`
+	Protocol         string      `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Balance          string      `json:"balance,omitempty" yaml:"balance,omitempty"`
+	TargetURL        string      `json:"targetURL,omitempty" yaml:"targetURL,omitempty"`
+	TargetMethod     string      `json:"targetMethod,omitempty" yaml:"targetMethod,omitempty"`
+	IsFollow         bool        `json:"isFollow" yaml:"isFollow"`
+	StripPrefix      bool        `json:"stripPrefix" yaml:"stripPrefix"`
+	StripSlash       bool        `json:"stripSlash" yaml:"stripSlash"`
+	Timeout          int         `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	RetryCount       int         `json:"retryCount,omitempty" yaml:"retryCount,omitempty"`
+	AlertValve       int         `json:"alertValve,omitempty" yaml:"alertValve,omitempty"`
+	APIType          int         `json:"apiType,omitempty" yaml:"apiType,omitempty"`
+	ResponseDataType string      `json:"responseDataType,omitempty" yaml:"responseDataType,omitempty"`
+	Steps            interface{} `json:"steps,omitempty" yaml:"steps,omitempty"` // 编排接口的步骤
+	StaticResponse   string      `json:"staticResponse,omitempty" yaml:"staticResponse,omitempty"`
+	CORS             interface{} `json:"cors,omitempty" yaml:"cors,omitempty"`
+	IPAccess         interface{} `json:"ipAccess,omitempty" yaml:"ipAccess,omitempty"`
+	Transform        interface{} `json:"transform,omitempty" yaml:"transform,omitempty"`
+	Mock             interface{} `json:"mock,omitempty" yaml:"mock,omitempty"`
+}
+
+// DeclarativeStrategy 策略，以策略ID唯一确定
+type DeclarativeStrategy struct {
+	ID       string                    `json:"id" yaml:"id"`
+	Name     string                    `json:"name" yaml:"name"`
+	Group    string                    `json:"group,omitempty" yaml:"group,omitempty"`
+	Type     int                       `json:"type,omitempty" yaml:"type,omitempty"`
+	Enable   bool                      `json:"enable" yaml:"enable"`
+	CORS     interface{}               `json:"cors,omitempty" yaml:"cors,omitempty"`
+	IPAccess interface{}               `json:"ipAccess,omitempty" yaml:"ipAccess,omitempty"`
+	Plugins  []*DeclarativePlugin      `json:"plugins,omitempty" yaml:"plugins,omitempty"` // 包含鉴权插件
+	APIs     []*DeclarativeStrategyAPI `json:"apis,omitempty" yaml:"apis,omitempty"`
+}
+
+// DeclarativeStrategyAPI 策略绑定的接口
+type DeclarativeStrategyAPI struct {
+	Project string               `json:"project" yaml:"project"`
+	API     string               `json:"api" yaml:"api"`
+	Target  string               `json:"target,omitempty" yaml:"target,omitempty"`
+	Canary  interface{}          `json:"canary,omitempty" yaml:"canary,omitempty"`
+	Plugins []*DeclarativePlugin `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+}
+
+// DeclarativePlugin 插件
+type DeclarativePlugin struct {
+	Name   string      `json:"name" yaml:"name"`
+	Enable bool        `json:"enable" yaml:"enable"`
+	Config interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// DeclarativeBalance 负载
+type DeclarativeBalance struct {
+	Name          string            `json:"name" yaml:"name"`
+	Service       string            `json:"service" yaml:"service"`
+	AppName       string            `json:"appName,omitempty" yaml:"appName,omitempty"`
+	Static        string            `json:"static,omitempty" yaml:"static,omitempty"`
+	StaticCluster map[string]string `json:"staticCluster,omitempty" yaml:"staticCluster,omitempty"`
+	Desc          string            `json:"desc,omitempty" yaml:"desc,omitempty"`
+}
+
+// DeclarativeService 服务注册方式
+type DeclarativeService struct {
+	Name               string            `json:"name" yaml:"name"`
+	Driver             string            `json:"driver" yaml:"driver"`
+	Default            bool              `json:"default,omitempty" yaml:"default,omitempty"`
+	Desc               string            `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Config             string            `json:"config,omitempty" yaml:"config,omitempty"`
+	ClusterConfig      map[string]string `json:"clusterConfig,omitempty" yaml:"clusterConfig,omitempty"`
+	HealthCheck        bool              `json:"healthCheck" yaml:"healthCheck"`
+	HealthCheckPath    string            `json:"healthCheckPath,omitempty" yaml:"healthCheckPath,omitempty"`
+	HealthCheckCode    string            `json:"healthCheckCode,omitempty" yaml:"healthCheckCode,omitempty"`
+	HealthCheckPeriod  int               `json:"healthCheckPeriod,omitempty" yaml:"healthCheckPeriod,omitempty"`
+	HealthCheckTimeout int               `json:"healthCheckTimeout,omitempty" yaml:"healthCheckTimeout,omitempty"`
+}
+
+// DeclarativeChange 导入计划中的一项变更
+type DeclarativeChange struct {
+	Action string `json:"action"` // create | update | delete
+	Kind   string `json:"kind"`
+	Key    string `json:"key"`
+}
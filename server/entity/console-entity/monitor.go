@@ -0,0 +1,52 @@
+package entity
+
+//MonitorCount 请求计数
+type MonitorCount struct {
+	GatewayRequestCount   int64 `json:"gatewayRequestCount"`
+	GatewaySuccessCount   int64 `json:"gatewaySuccessCount"`
+	GatewayStatus2xxCount int64 `json:"gatewayStatus2xxCount"`
+	GatewayStatus4xxCount int64 `json:"gatewayStatus4xxCount"`
+	GatewayStatus5xxCount int64 `json:"gatewayStatus5xxCount"`
+	ProxyRequestCount     int64 `json:"proxyRequestCount"`
+	ProxySuccessCount     int64 `json:"proxySuccessCount"`
+	ProxyStatus2xxCount   int64 `json:"proxyStatus2xxCount"`
+	ProxyStatus4xxCount   int64 `json:"proxyStatus4xxCount"`
+	ProxyStatus5xxCount   int64 `json:"proxyStatus5xxCount"`
+	ProxyTimeoutCount     int64 `json:"proxyTimeoutCount"`
+	AvgRequestTime        int64 `json:"avgRequestTime"` // 单位毫秒
+	MaxRequestTime        int64 `json:"maxRequestTime"` // 单位毫秒
+}
+
+//MonitorTrend 每小时的请求计数
+type MonitorTrend struct {
+	Hour int64  `json:"hour"` // 小时起始的unix时间戳
+	Time string `json:"time"`
+	MonitorCount
+}
+
+//MonitorAPIRank 接口请求计数排行
+type MonitorAPIRank struct {
+	APIID   int    `json:"apiID"`
+	APIName string `json:"apiName"`
+	MonitorCount
+}
+
+//MonitorPlugin 插件执行计数
+type MonitorPlugin struct {
+	PluginName   string `json:"pluginName"`
+	CallCount    int64  `json:"callCount"`
+	ErrorCount   int64  `json:"errorCount"`
+	PanicCount   int64  `json:"panicCount"`
+	TimeoutCount int64  `json:"timeoutCount"`
+	AvgCallTime  int64  `json:"avgCallTime"` // 单位微秒
+	MaxCallTime  int64  `json:"maxCallTime"` // 单位微秒
+}
+
+//MonitorCondition 监控查询条件，时间为小时起始的unix时间戳，零值表示不限制
+type MonitorCondition struct {
+	BeginHour  int64
+	EndHour    int64
+	ClusterID  int
+	StrategyID string
+	APIID      int
+}
@@ -0,0 +1,32 @@
+package entity
+
+import "github.com/eolinker/goku-api-gateway/config"
+
+//NodeApplyStatus 节点配置应用状态
+type NodeApplyStatus struct {
+	NodeID        int                        `json:"nodeID"`
+	Version       string                     `json:"version"` // 最近一次成功应用的版本
+	Status        string                     `json:"status"`  // 最近一次应用结果 applied | failed
+	Error         string                     `json:"error,omitempty"`
+	FailedVersion string                     `json:"failedVersion,omitempty"`
+	Plugins       []*config.PluginLoadStatus `json:"plugins"`
+	ReportTime    string                     `json:"reportTime"`
+}
+
+//NodeConfigStatus 节点配置状态，用于展示配置漂移
+type NodeConfigStatus struct {
+	NodeID          int                        `json:"nodeID"`
+	NodeName        string                     `json:"nodeName"`
+	NodeIP          string                     `json:"nodeIP"`
+	NodePort        string                     `json:"nodePort"`
+	Cluster         string                     `json:"cluster"`
+	NodeStatus      int                        `json:"nodeStatus"`
+	ExpectVersionID int                        `json:"expectVersionID"`
+	VersionID       int                        `json:"versionID"` // 0 表示未知
+	Version         string                     `json:"version"`
+	ApplyStatus     string                     `json:"applyStatus"`
+	Error           string                     `json:"error,omitempty"`
+	Plugins         []*config.PluginLoadStatus `json:"plugins"`
+	ReportTime      string                     `json:"reportTime"`
+	Drift           string                     `json:"drift"` // 空表示与期望版本一致，outdated | failed | unknown
+}
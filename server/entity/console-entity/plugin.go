@@ -15,6 +15,15 @@ type Plugin struct {
 	PluginDesc string `json:"pluginDesc"`
 	IsStop     int    `json:"isStop"`
 	IsCheck    int    `json:"isCheck"`
+	PluginPolicy
+}
+
+//PluginPolicy 插件执行超时时间及panic、超时时的失败策略
+type PluginPolicy struct {
+	Timeout    int    `json:"timeout"` // 单位毫秒，0表示不限制
+	FailPolicy string `json:"failPolicy"`
+	FailStatus int    `json:"failStatus"`
+	FailBody   string `json:"failBody"`
 }
 
 //PluginList 插件列表
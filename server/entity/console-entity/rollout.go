@@ -0,0 +1,58 @@
+package entity
+
+const (
+	//RolloutTargetGroup 按节点分组灰度
+	RolloutTargetGroup = "group"
+	//RolloutTargetPercent 按节点百分比灰度
+	RolloutTargetPercent = "percent"
+	//RolloutTargetNode 按指定节点灰度
+	RolloutTargetNode = "node"
+
+	//RolloutRunning 灰度中
+	RolloutRunning = "running"
+	//RolloutPromoted 已全量发布
+	RolloutPromoted = "promoted"
+	//RolloutAborted 已终止
+	RolloutAborted = "aborted"
+)
+
+//Rollout 版本灰度发布
+type Rollout struct {
+	ID               int           `json:"id"`
+	VersionID        int           `json:"versionID"`
+	BaseVersionID    int           `json:"baseVersionID"`
+	TargetType       string        `json:"targetType"` // group | percent | node
+	Target           RolloutTarget `json:"target"`
+	AutoAbort        bool          `json:"autoAbort"`
+	FailureThreshold int           `json:"failureThreshold"` // 失败节点数达到该值时自动终止
+	ApplyTimeout     int           `json:"applyTimeout"`     // 节点应用配置的超时时间，秒
+	Status           string        `json:"status"`
+	Reason           string        `json:"reason"`
+	OperatorID       int           `json:"operatorID"`
+	CreateTime       string        `json:"createTime"`
+	UpdateTime       string        `json:"updateTime"`
+}
+
+//RolloutTarget 灰度目标
+type RolloutTarget struct {
+	GroupIDs []int `json:"groupIDs,omitempty"`
+	Percent  int   `json:"percent,omitempty"`
+	NodeIDs  []int `json:"nodeIDs,omitempty"`
+}
+
+//RolloutNode 灰度节点状态
+type RolloutNode struct {
+	NodeID           int    `json:"nodeID"`
+	NodeName         string `json:"nodeName"`
+	NodeIP           string `json:"nodeIP"`
+	NodePort         string `json:"nodePort"`
+	Cluster          string `json:"cluster"`
+	GroupID          int    `json:"groupID"`
+	NodeStatus       int    `json:"nodeStatus"`
+	InRollout        bool   `json:"inRollout"`
+	ExpectVersionID  int    `json:"expectVersionID"`
+	RunningVersionID int    `json:"runningVersionID"` // 0 表示未知
+	LastPollTime     string `json:"lastPollTime"`
+	Failed           bool   `json:"failed"`
+	FailReason       string `json:"failReason,omitempty"`
+}
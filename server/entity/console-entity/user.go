@@ -8,3 +8,17 @@ type UserInfo struct {
 	UserType  int    `json:"userType"`
 	CanDelete bool   `json:"canDelete"`
 }
+
+// 用户来源
+const (
+	UserSourceLocal = "local"
+	UserSourceLDAP  = "ldap"
+	UserSourceOIDC  = "oidc"
+)
+
+//PermissionGroup 权限分组
+type PermissionGroup struct {
+	GroupID     int    `json:"groupID"`
+	GroupName   string `json:"groupName"`
+	Permissions string `json:"permissions"`
+}